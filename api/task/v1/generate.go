@@ -0,0 +1,7 @@
+// Package taskv1 holds the protobuf messages of TaskService and its
+// generated gRPC client and server stubs. Regenerate them after changing
+// task.proto with go generate, which needs protoc, protoc-gen-go and
+// protoc-gen-go-grpc.
+package taskv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative task.proto
@@ -0,0 +1,62 @@
+// Package auth identifies the user behind a request and carries the
+// identity through the request context.
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// UserHeader is the header carrying the ID of the calling user.
+const UserHeader = "X-User-ID"
+
+// WorkspaceHeader is the header carrying the ID of the workspace the calling
+// user works in. Like UserHeader, it is set by the gateway in front of the
+// API, which checks that the user is a member of the workspace.
+const WorkspaceHeader = "X-Workspace-ID"
+
+type contextKey int
+
+const (
+	userIDKey contextKey = iota
+	workspaceIDKey
+)
+
+// Middleware stores the calling user's ID and workspace in the request
+// context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get(UserHeader)
+		if userID != "" {
+			r = r.WithContext(WithUserID(r.Context(), userID))
+		}
+		workspaceID := r.Header.Get(WorkspaceHeader)
+		if userID != "" && workspaceID != "" {
+			r = r.WithContext(WithWorkspaceID(r.Context(), workspaceID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithUserID returns a copy of ctx carrying the given user ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the ID of the calling user, or "" for anonymous requests.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+// WithWorkspaceID returns a copy of ctx carrying the given workspace ID.
+func WithWorkspaceID(ctx context.Context, workspaceID string) context.Context {
+	return context.WithValue(ctx, workspaceIDKey, workspaceID)
+}
+
+// WorkspaceID returns the ID of the workspace of the calling user, or "" when
+// the request has none.
+func WorkspaceID(ctx context.Context) string {
+	workspaceID, _ := ctx.Value(workspaceIDKey).(string)
+	return workspaceID
+}
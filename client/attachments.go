@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// checksumHeader carries the hex encoded SHA-256 of attachments
+const checksumHeader = "X-Checksum-SHA256"
+
+// UploadAttachment attaches a file to a task. The content is read into
+// memory, so that the upload can be retried. A non-empty checksum, the hex
+// encoded SHA-256 of the content, is checked by the API.
+func (c *Client) UploadAttachment(ctx context.Context, taskID, filename string, content io.Reader, checksum string) (models.Attachment, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if checksum != "" {
+		err := w.WriteField("sha256", checksum)
+		if err != nil {
+			return models.Attachment{}, err
+		}
+	}
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	_, err = io.Copy(part, content)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	err = w.Close()
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	req := &request{
+		method:      http.MethodPost,
+		path:        resource("tasks", taskID, "attachments"),
+		header:      http.Header{},
+		body:        body.Bytes(),
+		contentType: w.FormDataContentType(),
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	var attachment models.Attachment
+	return attachment, decode(resp, &attachment)
+}
+
+// DownloadAttachment returns an attachment, with the details sent along
+// its content. The caller closes the content.
+func (c *Client) DownloadAttachment(ctx context.Context, id string) (models.Attachment, io.ReadCloser, error) {
+	req, _ := newRequest(http.MethodGet, resource("attachments", id), nil)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	attachment := models.Attachment{
+		ID:          id,
+		ContentType: resp.Header.Get("Content-Type"),
+		Checksum:    resp.Header.Get(checksumHeader),
+	}
+	attachment.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		attachment.Filename = params["filename"]
+	}
+	return attachment, resp.Body, nil
+}
+
+// DeleteAttachment deletes an attachment.
+func (c *Client) DeleteAttachment(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("attachments", id), nil, nil)
+}
@@ -0,0 +1,339 @@
+// Package client calls the task API over HTTP. Methods take a context, and
+// return the models of the API with an *Error when the API answers with a
+// problem.
+//
+//	c := client.New("http://localhost:8080", client.WithUserID("42"))
+//	task, err := c.CreateTask(ctx, models.Task{Title: "Call John"})
+//
+// Requests failing with a 5xx or 429 status, or without a response, are
+// retried with exponential backoff. Changes are sent with an
+// Idempotency-Key, so that a retry is never applied twice.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vikash-parashar/task-manager-2/auth"
+)
+
+// Defaults of the retry policy
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Client calls the task API. It is safe for concurrent use.
+type Client struct {
+	baseURL     string // Ends with the version prefix, e.g. http://localhost:8080/v1
+	httpClient  *http.Client
+	userID      string
+	workspaceID string
+	editors     []func(*http.Request) error
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client sending the requests, instead of
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserID identifies the calling user, with the X-User-ID header.
+func WithUserID(userID string) Option {
+	return func(c *Client) {
+		c.userID = userID
+	}
+}
+
+// UserID returns the calling user, as set by WithUserID.
+func (c *Client) UserID() string {
+	return c.userID
+}
+
+// WithWorkspaceID sets the workspace of the calling user, with the
+// X-Workspace-ID header.
+func WithWorkspaceID(workspaceID string) Option {
+	return func(c *Client) {
+		c.workspaceID = workspaceID
+	}
+}
+
+// WithRequestEditor calls edit on every request before it is sent, e.g. to
+// add the token a gateway in front of the API expects.
+func WithRequestEditor(edit func(*http.Request) error) Option {
+	return func(c *Client) {
+		c.editors = append(c.editors, edit)
+	}
+}
+
+// WithRetry sets how many times a request is retried, and the bounds of
+// the backoff between attempts. Zero retries disables them.
+func WithRetry(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// New returns a client of the API served at baseURL, e.g.
+// http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/v1",
+		httpClient: http.DefaultClient,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FieldError is an invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"` // JSON path of the field, e.g. reminders[0].date
+	Message string `json:"message"`
+}
+
+// Error is a problem the API answered with (RFC 7807)
+type Error struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance"`
+	Code      string       `json:"code"` // Stable error code, e.g. task_not_found
+	RequestID string       `json:"requestID"`
+	Errors    []FieldError `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("task api: %d %s", e.Status, message)
+	}
+	return fmt.Sprintf("task api: %d %s (%s)", e.Status, message, e.Code)
+}
+
+// IsNotFound reports whether err is an API error with the 404 status.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// request is an API call
+type request struct {
+	method      string
+	path        string // Relative to the base URL, with its path parameters escaped
+	query       url.Values
+	header      http.Header
+	body        []byte
+	contentType string
+}
+
+// newRequest returns a call with a JSON body, unless body is nil.
+func newRequest(method, path string, body interface{}) (*request, error) {
+	req := &request{method: method, path: path, header: http.Header{}}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		req.body = data
+		req.contentType = "application/json"
+	}
+	return req, nil
+}
+
+// call sends a request and decodes the JSON response into out, unless it
+// is nil.
+func (c *Client) call(ctx context.Context, method, path string, in, out interface{}) error {
+	req, err := newRequest(method, path, in)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	return decode(resp, out)
+}
+
+// decode reads a successful response into out, unless it is nil, and
+// closes it.
+func decode(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// do sends a request, retrying it on failure, and returns the response
+// with a 2xx or 3xx status. Other responses are returned as an *Error.
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	// The same key on every attempt lets the API replay the first response
+	if req.method != http.MethodGet && req.method != http.MethodHead && req.header.Get("Idempotency-Key") == "" {
+		req.header.Set("Idempotency-Key", uuid.NewString())
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+		if err == nil {
+			err = readError(resp)
+		}
+
+		if attempt >= c.maxRetries || !retryable(ctx, err) {
+			return nil, err
+		}
+		err = sleep(ctx, c.backoff(attempt, resp))
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes one attempt of a request.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if c.userID != "" {
+		httpReq.Header.Set(auth.UserHeader, c.userID)
+	}
+	if c.workspaceID != "" {
+		httpReq.Header.Set(auth.WorkspaceHeader, c.workspaceID)
+	}
+	for _, edit := range c.editors {
+		err := edit(httpReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// readError returns the error of a failed response, and closes it.
+func readError(resp *http.Response) error {
+	defer resp.Body.Close()
+	apiErr := &Error{Status: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Status == 0 {
+		apiErr.Status = resp.StatusCode
+		apiErr.Detail = strings.TrimSpace(string(data))
+	}
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// retryable reports whether a failed attempt may succeed when retried:
+// responses with a 5xx or 429 status, and requests that got no response.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		// The first request with the same Idempotency-Key is still running
+		if apiErr.Code == "idempotency_key_in_progress" {
+			return true
+		}
+		return apiErr.Status >= 500 || apiErr.Status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// backoff returns how long to wait before the next attempt: the
+// Retry-After of the response if any, or an exponential backoff with
+// jitter.
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	d := c.minBackoff << attempt
+	if d > c.maxBackoff || d <= 0 {
+		d = c.maxBackoff
+	}
+	d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			d = time.Duration(seconds) * time.Second
+			if d > c.maxBackoff {
+				d = c.maxBackoff
+			}
+		}
+	}
+	return d
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resource returns the path of a resource from its segments, escaping
+// them.
+func resource(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
@@ -0,0 +1,457 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/handlers"
+	"github.com/vikash-parashar/task-manager-2/models"
+	"github.com/vikash-parashar/task-manager-2/routes"
+)
+
+// attempt is a request that reached the test server
+type attempt struct {
+	at             time.Time
+	query          url.Values
+	idempotencyKey string
+}
+
+// testServer serves the v1 routes from an in-memory store, behind the
+// middleware of the API. fail may answer a request instead of the routes:
+// it is called with the number of the attempt, from 0, and returns whether
+// it wrote the response.
+type testServer struct {
+	url   string
+	store *memoryStore
+	fail  func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool
+
+	mu       sync.Mutex
+	attempts []attempt
+}
+
+// newTestServer starts a test server and returns a client of it, calling as
+// user 42 with short backoffs. opts are applied after these defaults.
+func newTestServer(t *testing.T, opts ...Option) (*testServer, *Client) {
+	t.Helper()
+	s := &testServer{store: newMemoryStore()}
+
+	db := config.DB
+	config.DB = sql.OpenDB(s.store)
+	t.Cleanup(func() {
+		config.DB.Close()
+		config.DB = db
+	})
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(auth.Middleware)
+	r.Use(handlers.Idempotent)
+	r.Route("/v1", routes.V1)
+
+	srv := httptest.NewServer(s.handler(r))
+	t.Cleanup(srv.Close)
+	s.url = srv.URL
+
+	opts = append([]Option{WithUserID("42"), WithRetry(3, time.Millisecond, 10*time.Millisecond)}, opts...)
+	return s, New(s.url, opts...)
+}
+
+// handler records the attempts, and lets fail answer them.
+func (s *testServer) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		n := len(s.attempts)
+		s.attempts = append(s.attempts, attempt{
+			at:             time.Now(),
+			query:          r.URL.Query(),
+			idempotencyKey: r.Header.Get(handlers.IdempotencyKeyHeader),
+		})
+		s.mu.Unlock()
+
+		if s.fail != nil && s.fail(n, w, r, next) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Attempts returns the requests that reached the server, in order.
+func (s *testServer) Attempts() []attempt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]attempt(nil), s.attempts...)
+}
+
+func TestContacts(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+
+	john, err := c.CreateContact(ctx, models.Contact{Name: " John ", Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if john.ID == "" || john.UserID != "42" || john.Name != "John" || john.Email != "john@example.com" || john.CreatedAt.IsZero() {
+		t.Errorf("CreateContact = %+v", john)
+	}
+	anna, err := c.CreateContact(ctx, models.Contact{Name: "Anna", Phone: "+33 1 23 45 67 89"})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	got, err := c.GetContact(ctx, john.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if !reflect.DeepEqual(got, john) {
+		t.Errorf("GetContact = %+v, want %+v", got, john)
+	}
+
+	list, err := c.ListContacts(ctx)
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != anna.ID || list[1].ID != john.ID {
+		t.Errorf("ListContacts = %+v, want Anna then John", list)
+	}
+
+	updated, err := c.UpdateContact(ctx, john.ID, models.Contact{Name: "John Doe"})
+	if err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	if updated.ID != john.ID || updated.Name != "John Doe" || updated.Email != "" {
+		t.Errorf("UpdateContact = %+v", updated)
+	}
+
+	err = c.DeleteContact(ctx, john.ID)
+	if err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	_, err = c.GetContact(ctx, john.ID)
+	if !IsNotFound(err) {
+		t.Errorf("GetContact of a deleted contact: got %v, want a 404 error", err)
+	}
+
+	// Contacts are scoped to the calling user
+	other := New(s.url, WithUserID("43"))
+	_, err = other.GetContact(ctx, anna.ID)
+	if !IsNotFound(err) {
+		t.Errorf("GetContact of another user: got %v, want a 404 error", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := c.GetContact(ctx, "missing")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetContact of a missing contact: got %v, want an *Error", err)
+	}
+	if apiErr.Status != http.StatusNotFound || apiErr.Code != "contact_not_found" || apiErr.Title != "Not Found" ||
+		apiErr.Detail != "contact not found" || apiErr.Instance != "/v1/contacts/missing" || apiErr.RequestID == "" {
+		t.Errorf("GetContact of a missing contact: got %+v", apiErr)
+	}
+	if want := "task api: 404 contact not found (contact_not_found)"; apiErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", apiErr.Error(), want)
+	}
+
+	_, err = c.CreateContact(ctx, models.Contact{Email: "not an email"})
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreateContact of an invalid contact: got %v, want an *Error", err)
+	}
+	want := []FieldError{{Field: "name", Message: "is required"}, {Field: "email", Message: "must be an email address"}}
+	if apiErr.Status != http.StatusBadRequest || apiErr.Code != "invalid_fields" || !reflect.DeepEqual(apiErr.Errors, want) {
+		t.Errorf("CreateContact of an invalid contact: got %+v, want the invalid fields %+v", apiErr, want)
+	}
+
+	// Client errors are not retried
+	if n := len(s.Attempts()); n != 2 {
+		t.Errorf("got %d attempts, want 2", n)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	s, c := newTestServer(t)
+	s.fail = func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool {
+		if n < 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return true
+		}
+		return false
+	}
+
+	contact, err := c.CreateContact(context.Background(), models.Contact{Name: "John"})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if contact.Name != "John" {
+		t.Errorf("CreateContact = %+v", contact)
+	}
+	attempts := s.Attempts()
+	if len(attempts) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(attempts))
+	}
+	for _, a := range attempts[1:] {
+		if a.idempotencyKey == "" || a.idempotencyKey != attempts[0].idempotencyKey {
+			t.Errorf("got Idempotency-Key %q on a retry, want %q", a.idempotencyKey, attempts[0].idempotencyKey)
+		}
+	}
+	if n := s.store.contactCount(); n != 1 {
+		t.Errorf("got %d contacts, want 1", n)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	s, c := newTestServer(t, WithRetry(2, time.Millisecond, 10*time.Millisecond))
+	s.fail = func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return true
+	}
+
+	_, err := c.ListContacts(context.Background())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || apiErr.Detail != "bad gateway" {
+		t.Errorf("ListContacts: got %v, want the 502 error", err)
+	}
+	if n := len(s.Attempts()); n != 3 {
+		t.Errorf("got %d attempts, want the first one and 2 retries", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	// Retry-After is capped by the maximum backoff
+	const maxBackoff = 300 * time.Millisecond
+	s, c := newTestServer(t, WithRetry(3, time.Millisecond, maxBackoff))
+	s.fail = func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool {
+		if n == 0 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return true
+		}
+		return false
+	}
+
+	_, err := c.ListContacts(context.Background())
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	attempts := s.Attempts()
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(attempts))
+	}
+	if wait := attempts[1].at.Sub(attempts[0].at); wait < maxBackoff || wait >= time.Second {
+		t.Errorf("retried after %v, want the Retry-After of 1s capped to %v", wait, maxBackoff)
+	}
+}
+
+func TestRetryReplaysIdempotentResponse(t *testing.T) {
+	s, c := newTestServer(t)
+	var replayed string
+	s.fail = func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool {
+		if n == 0 {
+			// The contact is created, but its response is lost on the way
+			next.ServeHTTP(httptest.NewRecorder(), r)
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return true
+		}
+		next.ServeHTTP(w, r)
+		replayed = w.Header().Get(handlers.IdempotentReplayedHeader)
+		return true
+	}
+
+	contact, err := c.CreateContact(context.Background(), models.Contact{Name: "John"})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	attempts := s.Attempts()
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(attempts))
+	}
+	if attempts[0].idempotencyKey == "" || attempts[1].idempotencyKey != attempts[0].idempotencyKey {
+		t.Errorf("got Idempotency-Keys %q and %q, want the same key on both attempts", attempts[0].idempotencyKey, attempts[1].idempotencyKey)
+	}
+	if replayed != "true" {
+		t.Errorf("got %s header %q on the retry, want true", handlers.IdempotentReplayedHeader, replayed)
+	}
+	if n := s.store.contactCount(); n != 1 {
+		t.Errorf("got %d contacts, want the retry not to create another one", n)
+	}
+	got, err := c.GetContact(context.Background(), contact.ID)
+	if err != nil || !reflect.DeepEqual(got, contact) {
+		t.Errorf("GetContact = %+v, %v, want the replayed contact %+v", got, err, contact)
+	}
+}
+
+func TestAuditIterator(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		entries int
+		before  []string // Cursors of the requested pages
+	}{
+		{"partial last page", 5, []string{"", "4", "2"}},
+		{"full last page", 4, []string{"", "3", "1"}},
+		{"empty log", 0, []string{""}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestServer(t)
+			s.store.addTask("t1", "42")
+			s.store.addTask("t2", "42")
+			var want []int64
+			for i := 0; i < tt.entries; i++ {
+				entry := s.store.addAudit(models.AuditEntry{ActorID: "42", Action: models.AuditUpdate, EntityType: models.AuditEntityTask, EntityID: "t1", TaskID: "t1"})
+				want = append([]int64{entry.ID}, want...)
+			}
+			// An entry of another task, left out by the filter
+			s.store.addAudit(models.AuditEntry{ActorID: "42", Action: models.AuditCreate, EntityType: models.AuditEntityTask, EntityID: "t2", TaskID: "t2"})
+
+			it := c.AuditLog(models.AuditFilter{TaskID: "t1", Limit: 2})
+			var got []int64
+			for it.Next(context.Background()) {
+				got = append(got, it.Entry().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got entries %v, want %v", got, want)
+			}
+
+			var before []string
+			for _, a := range s.Attempts() {
+				if a.query.Get("taskID") != "t1" || a.query.Get("limit") != "2" {
+					t.Errorf("got query %v, want the filter of the iterator", a.query)
+				}
+				before = append(before, a.query.Get("before"))
+			}
+			if !reflect.DeepEqual(before, tt.before) {
+				t.Errorf("got pages before %q, want %q", before, tt.before)
+			}
+		})
+	}
+}
+
+func TestAuditIteratorError(t *testing.T) {
+	s, c := newTestServer(t, WithRetry(0, 0, 0))
+	s.store.addTask("t1", "42")
+	for i := 0; i < 3; i++ {
+		s.store.addAudit(models.AuditEntry{ActorID: "42", Action: models.AuditUpdate, EntityType: models.AuditEntityTask, EntityID: "t1", TaskID: "t1"})
+	}
+	s.fail = func(n int, w http.ResponseWriter, r *http.Request, next http.Handler) bool {
+		if n == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return true
+		}
+		return false
+	}
+
+	it := c.AuditLog(models.AuditFilter{Limit: 2})
+	var n int
+	for it.Next(context.Background()) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("got %d entries, want the first page of 2", n)
+	}
+	var apiErr *Error
+	if !errors.As(it.Err(), &apiErr) || apiErr.Status != http.StatusServiceUnavailable {
+		t.Errorf("Err = %v, want the 503 error of the second page", it.Err())
+	}
+	if it.Next(context.Background()) {
+		t.Errorf("Next after an error returned true")
+	}
+}
+
+func TestAuditLogScope(t *testing.T) {
+	s, c := newTestServer(t)
+	s.store.addTask("t1", "42")
+	s.store.addTask("t2", "43")
+	own := s.store.addAudit(models.AuditEntry{ActorID: "42", Action: models.AuditCreate, EntityType: models.AuditEntityTask, EntityID: "t1", TaskID: "t1"})
+	s.store.addAudit(models.AuditEntry{ActorID: "43", Action: models.AuditCreate, EntityType: models.AuditEntityTask, EntityID: "t2", TaskID: "t2"})
+
+	entries, err := c.GetAuditLog(context.Background(), models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditLog: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != own.ID {
+		t.Errorf("GetAuditLog = %+v, want only the entry of the task of the user", entries)
+	}
+
+	_, err = New(s.url).GetAuditLog(context.Background(), models.AuditFilter{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("GetAuditLog without a user: got %v, want a 401 error", err)
+	}
+}
+
+func TestBatchInvalidTasks(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	// The typed client cannot send invalid tasks
+	post := func(body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, s.url+"/v1/tasks/batch", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(auth.UserHeader, "42")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	// Each invalid task fails its operation alone
+	resp := post(`{"mode": "bestEffort", "operations": [
+		{"op": "create", "task": {"title": "Call John", "dueDateTime": "tomorrow"}},
+		{"op": "update", "id": "1", "task": {"title": 42}},
+		{"op": "create", "task": "Call John"}
+	]}`)
+	var results []BatchResult
+	err := json.NewDecoder(resp.Body).Decode(&results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]FieldError{
+		{{Field: "operations[0].task.dueDateTime", Message: "must be a date in RFC3339 format"}},
+		{{Field: "operations[1].task.title", Message: "has an invalid type"}},
+		{{Field: "operations[2].task", Message: "must be a task"}},
+	}
+	if resp.StatusCode != http.StatusOK || len(results) != len(want) {
+		t.Fatalf("got status %d and %d results, want 200 and %d results", resp.StatusCode, len(results), len(want))
+	}
+	for i, result := range results {
+		if result.Index != i || result.Status != http.StatusBadRequest || result.Error == nil || !reflect.DeepEqual(result.Error.Errors, want[i]) {
+			t.Errorf("operation %d: got %+v, want a 400 error with the invalid fields %+v", i, result, want[i])
+		}
+	}
+
+	// In atomic mode the invalid task fails the batch
+	resp = post(`{"operations": [{"op": "create", "task": {"title": "Call John", "dueDateTime": "tomorrow"}}]}`)
+	var apiErr Error
+	err = json.NewDecoder(resp.Body).Decode(&apiErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || !reflect.DeepEqual(apiErr.Errors, want[0]) {
+		t.Errorf("atomic batch: got status %d and %+v, want 400 with the invalid fields %+v", resp.StatusCode, apiErr, want[0])
+	}
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// CreateTag creates a tag.
+func (c *Client) CreateTag(ctx context.Context, tag models.Tag) (models.Tag, error) {
+	var created models.Tag
+	return created, c.call(ctx, http.MethodPost, "/tags", tag, &created)
+}
+
+// GetTag returns a tag.
+func (c *Client) GetTag(ctx context.Context, id string) (models.Tag, error) {
+	var tag models.Tag
+	return tag, c.call(ctx, http.MethodGet, resource("tags", id), nil, &tag)
+}
+
+// ListTags returns the private tags of the calling user or, unless
+// workspaceID is empty, the tags of the workspace set with WithWorkspaceID.
+func (c *Client) ListTags(ctx context.Context, workspaceID string) ([]models.Tag, error) {
+	req, _ := newRequest(http.MethodGet, "/tags", nil)
+	if workspaceID != "" {
+		req.query = url.Values{"workspaceID": {workspaceID}}
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var tags []models.Tag
+	return tags, decode(resp, &tags)
+}
+
+// UpdateTag renames or recolors a tag.
+func (c *Client) UpdateTag(ctx context.Context, id string, tag models.Tag) (models.Tag, error) {
+	var updated models.Tag
+	return updated, c.call(ctx, http.MethodPut, resource("tags", id), tag, &updated)
+}
+
+// DeleteTag deletes a tag, detaching it from its tasks.
+func (c *Client) DeleteTag(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("tags", id), nil, nil)
+}
+
+// MergeTags merges tags into the target one, which their tasks then carry.
+func (c *Client) MergeTags(ctx context.Context, targetID string, tagIDs []string) (models.Tag, error) {
+	var tag models.Tag
+	body := map[string][]string{"tagIDs": tagIDs}
+	return tag, c.call(ctx, http.MethodPost, resource("tags", targetID, "merge"), body, &tag)
+}
+
+// AttachTags adds tags to a task, and returns the tags of the task.
+func (c *Client) AttachTags(ctx context.Context, taskID string, tagIDs []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	body := map[string][]string{"tagIDs": tagIDs}
+	return tags, c.call(ctx, http.MethodPost, resource("tasks", taskID, "tags"), body, &tags)
+}
+
+// DetachTags removes tags from a task, and returns the tags of the task.
+func (c *Client) DetachTags(ctx context.Context, taskID string, tagIDs []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	body := map[string][]string{"tagIDs": tagIDs}
+	return tags, c.call(ctx, http.MethodDelete, resource("tasks", taskID, "tags"), body, &tags)
+}
+
+// CreateProject creates a project, placed after the other projects of the
+// user.
+func (c *Client) CreateProject(ctx context.Context, project models.Project) (models.Project, error) {
+	var created models.Project
+	return created, c.call(ctx, http.MethodPost, "/projects", project, &created)
+}
+
+// GetProject returns a project.
+func (c *Client) GetProject(ctx context.Context, id string) (models.Project, error) {
+	var project models.Project
+	return project, c.call(ctx, http.MethodGet, resource("projects", id), nil, &project)
+}
+
+// ListProjects returns the active projects of the calling user in their
+// order, or the archived ones.
+func (c *Client) ListProjects(ctx context.Context, archived bool) ([]models.Project, error) {
+	req, _ := newRequest(http.MethodGet, "/projects", nil)
+	if archived {
+		req.query = url.Values{"archived": {"true"}}
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var projects []models.Project
+	return projects, decode(resp, &projects)
+}
+
+// UpdateProject changes the name, description and task defaults of a
+// project.
+func (c *Client) UpdateProject(ctx context.Context, id string, project models.Project) (models.Project, error) {
+	var updated models.Project
+	return updated, c.call(ctx, http.MethodPut, resource("projects", id), project, &updated)
+}
+
+// ArchiveProject hides a project from the default listing.
+func (c *Client) ArchiveProject(ctx context.Context, id string) (models.Project, error) {
+	var project models.Project
+	return project, c.call(ctx, http.MethodPost, resource("projects", id, "archive"), nil, &project)
+}
+
+// UnarchiveProject lists an archived project again.
+func (c *Client) UnarchiveProject(ctx context.Context, id string) (models.Project, error) {
+	var project models.Project
+	return project, c.call(ctx, http.MethodPost, resource("projects", id, "unarchive"), nil, &project)
+}
+
+// ReorderProjects puts the projects of the calling user in the given order.
+func (c *Client) ReorderProjects(ctx context.Context, projectIDs []string) ([]models.Project, error) {
+	var projects []models.Project
+	body := map[string][]string{"projectIDs": projectIDs}
+	return projects, c.call(ctx, http.MethodPut, "/projects/order", body, &projects)
+}
+
+// DeleteProject deletes a project, its tasks are kept without a project.
+func (c *Client) DeleteProject(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("projects", id), nil, nil)
+}
+
+// CreateContact creates a contact owned by the calling user.
+func (c *Client) CreateContact(ctx context.Context, contact models.Contact) (models.Contact, error) {
+	var created models.Contact
+	return created, c.call(ctx, http.MethodPost, "/contacts", contact, &created)
+}
+
+// GetContact returns a contact.
+func (c *Client) GetContact(ctx context.Context, id string) (models.Contact, error) {
+	var contact models.Contact
+	return contact, c.call(ctx, http.MethodGet, resource("contacts", id), nil, &contact)
+}
+
+// ListContacts returns the contacts of the calling user by name.
+func (c *Client) ListContacts(ctx context.Context) ([]models.Contact, error) {
+	var contacts []models.Contact
+	return contacts, c.call(ctx, http.MethodGet, "/contacts", nil, &contacts)
+}
+
+// UpdateContact replaces the details of a contact.
+func (c *Client) UpdateContact(ctx context.Context, id string, contact models.Contact) (models.Contact, error) {
+	var updated models.Contact
+	return updated, c.call(ctx, http.MethodPut, resource("contacts", id), contact, &updated)
+}
+
+// DeleteContact deletes a contact, its tasks are kept without a contact.
+func (c *Client) DeleteContact(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("contacts", id), nil, nil)
+}
+
+// CreateChecklistItem adds an item to the checklist of a task.
+func (c *Client) CreateChecklistItem(ctx context.Context, item models.ChecklistItem) (models.ChecklistItem, error) {
+	var created models.ChecklistItem
+	return created, c.call(ctx, http.MethodPost, "/checklistItems", item, &created)
+}
+
+// UpdateChecklistItem changes the text and state of a checklist item.
+func (c *Client) UpdateChecklistItem(ctx context.Context, id string, item models.ChecklistItem) (models.ChecklistItem, error) {
+	var updated models.ChecklistItem
+	return updated, c.call(ctx, http.MethodPut, resource("checklistItems", id), item, &updated)
+}
+
+// ToggleChecklistItem checks or unchecks a checklist item.
+func (c *Client) ToggleChecklistItem(ctx context.Context, id string) (models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	return item, c.call(ctx, http.MethodPost, resource("checklistItems", id, "toggle"), nil, &item)
+}
+
+// ReorderChecklist puts the checklist items of a task in the given order.
+func (c *Client) ReorderChecklist(ctx context.Context, taskID string, itemIDs []string) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	body := map[string][]string{"itemIDs": itemIDs}
+	return items, c.call(ctx, http.MethodPut, resource("tasks", taskID, "checklist", "order"), body, &items)
+}
+
+// DeleteChecklistItem removes an item from a checklist.
+func (c *Client) DeleteChecklistItem(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("checklistItems", id), nil, nil)
+}
+
+// CreateComment posts a comment on a task as the calling user.
+func (c *Client) CreateComment(ctx context.Context, comment models.Comment) (models.Comment, error) {
+	var created models.Comment
+	return created, c.call(ctx, http.MethodPost, "/comments", comment, &created)
+}
+
+// ListComments returns the comments of a task, oldest first.
+func (c *Client) ListComments(ctx context.Context, taskID string) ([]models.Comment, error) {
+	var comments []models.Comment
+	return comments, c.call(ctx, http.MethodGet, resource("tasks", taskID, "comments"), nil, &comments)
+}
+
+// UpdateComment edits a comment of the calling user.
+func (c *Client) UpdateComment(ctx context.Context, id, body string) (models.Comment, error) {
+	var comment models.Comment
+	return comment, c.call(ctx, http.MethodPut, resource("comments", id), models.Comment{Body: body}, &comment)
+}
+
+// DeleteComment hides a comment of the calling user.
+func (c *Client) DeleteComment(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("comments", id), nil, nil)
+}
+
+// ListCommentRevisions returns the previous versions of an edited comment,
+// oldest first.
+func (c *Client) ListCommentRevisions(ctx context.Context, id string) ([]models.CommentRevision, error) {
+	var revisions []models.CommentRevision
+	return revisions, c.call(ctx, http.MethodGet, resource("comments", id, "revisions"), nil, &revisions)
+}
+
+// CreateUser creates a user.
+func (c *Client) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	var created models.User
+	return created, c.call(ctx, http.MethodPost, "/users", user, &created)
+}
+
+// GetUser returns a user.
+func (c *Client) GetUser(ctx context.Context, id string) (models.User, error) {
+	var user models.User
+	return user, c.call(ctx, http.MethodGet, resource("users", id), nil, &user)
+}
+
+// UpdateUser replaces the details of the calling user, such as their time
+// zone.
+func (c *Client) UpdateUser(ctx context.Context, id string, user models.User) (models.User, error) {
+	var updated models.User
+	return updated, c.call(ctx, http.MethodPut, resource("users", id), user, &updated)
+}
+
+// ListUsers returns all the users.
+func (c *Client) ListUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	return users, c.call(ctx, http.MethodGet, "/users", nil, &users)
+}
+
+// StartTimer starts timing a task for the calling user, stopping their
+// running timer if any.
+func (c *Client) StartTimer(ctx context.Context, taskID, note string) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	body := map[string]string{"note": note}
+	return entry, c.call(ctx, http.MethodPost, resource("tasks", taskID, "timer"), body, &entry)
+}
+
+// GetRunningTimer returns the running timer of the calling user.
+func (c *Client) GetRunningTimer(ctx context.Context) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	return entry, c.call(ctx, http.MethodGet, "/timers/current", nil, &entry)
+}
+
+// StopTimer stops the running timer of the calling user, and returns the
+// time entry it made.
+func (c *Client) StopTimer(ctx context.Context) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	return entry, c.call(ctx, http.MethodDelete, "/timers/current", nil, &entry)
+}
+
+// CreateTimeEntry logs time spent on a task.
+func (c *Client) CreateTimeEntry(ctx context.Context, entry models.TimeEntry) (models.TimeEntry, error) {
+	var created models.TimeEntry
+	return created, c.call(ctx, http.MethodPost, "/timeEntries", entry, &created)
+}
+
+// ListTimeEntries returns the time entries of a task.
+func (c *Client) ListTimeEntries(ctx context.Context, taskID string) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	return entries, c.call(ctx, http.MethodGet, resource("tasks", taskID, "timeEntries"), nil, &entries)
+}
+
+// DeleteTimeEntry deletes a time entry.
+func (c *Client) DeleteTimeEntry(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("timeEntries", id), nil, nil)
+}
+
+// TimeReportFilter selects the time entries of the calling user summed by a
+// report
+type TimeReportFilter struct {
+	GroupBy   string // project, user, task or date
+	From, To  string // First and last days, as YYYY-MM-DD
+	ProjectID string
+}
+
+// GetTimeReport returns the time spent per group.
+func (c *Client) GetTimeReport(ctx context.Context, filter TimeReportFilter) ([]models.TimeReportRow, error) {
+	req, _ := newRequest(http.MethodGet, "/reports/time", nil)
+	req.query = url.Values{"groupBy": {filter.GroupBy}}
+	for name, value := range map[string]string{"from": filter.From, "to": filter.To, "projectID": filter.ProjectID} {
+		if value != "" {
+			req.query.Set(name, value)
+		}
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var rows []models.TimeReportRow
+	return rows, decode(resp, &rows)
+}
+
+// auditQuery returns the query parameters of an audit log filter.
+func auditQuery(filter models.AuditFilter) url.Values {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"actorID":    filter.ActorID,
+		"action":     filter.Action,
+		"entityType": filter.EntityType,
+		"entityID":   filter.EntityID,
+		"taskID":     filter.TaskID,
+		"requestID":  filter.RequestID,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.Format(time.RFC3339Nano))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.Format(time.RFC3339Nano))
+	}
+	if filter.BeforeID != 0 {
+		query.Set("before", strconv.FormatInt(filter.BeforeID, 10))
+	}
+	if filter.Limit != 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	return query
+}
+
+// GetAuditLog returns a page of the audit log, newest first. See
+// AuditLog to iterate through the whole log.
+func (c *Client) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	req, _ := newRequest(http.MethodGet, "/audit", nil)
+	req.query = auditQuery(filter)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.AuditEntry
+	return entries, decode(resp, &entries)
+}
+
+// CreateCalendarFeed creates the iCalendar feed of the tasks of the user,
+// replacing the previous one. Calendar apps subscribe to its URL.
+func (c *Client) CreateCalendarFeed(ctx context.Context) (models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	return feed, c.call(ctx, http.MethodPost, "/calendar/feed", nil, &feed)
+}
+
+// DeleteCalendarFeed deletes the iCalendar feed of the user, so its URL
+// stops working.
+func (c *Client) DeleteCalendarFeed(ctx context.Context) error {
+	return c.call(ctx, http.MethodDelete, "/calendar/feed", nil, nil)
+}
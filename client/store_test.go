@@ -0,0 +1,426 @@
+package client
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// memoryStore is an in-memory stand-in for the database, opened with
+// sql.OpenDB. It serves the queries of the endpoints the client tests call:
+// contacts, idempotency keys and the audit log of the tasks of a user.
+// Other queries fail, so a test calling an endpoint the store does not know
+// fails loudly.
+type memoryStore struct {
+	mu       sync.Mutex
+	contacts []models.Contact
+	keys     map[[2]string]*storedKey // By user ID and key
+	audit    []models.AuditEntry      // By ascending ID
+	owners   map[string]string        // Owners of the tasks of the audit log, by task ID
+}
+
+// storedKey is a row of idempotency_keys
+type storedKey struct {
+	fingerprint string
+	status      driver.Value // NULL until the response is stored
+	headers     []byte
+	body        []byte
+	createdAt   time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: map[[2]string]*storedKey{}, owners: map[string]string{}}
+}
+
+// addTask records the owner of a task.
+func (s *memoryStore) addTask(id, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners[id] = userID
+}
+
+// addAudit appends an entry to the audit log, numbering it.
+func (s *memoryStore) addAudit(entry models.AuditEntry) models.AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.ID = int64(len(s.audit) + 1)
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	s.audit = append(s.audit, entry)
+	return entry
+}
+
+// contactCount returns the number of stored contacts.
+func (s *memoryStore) contactCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.contacts)
+}
+
+// result is the outcome of a query: the rows it returned, and the number of
+// rows it changed.
+type result struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// statements are the queries served by the store, by the start of their
+// text with its whitespace collapsed.
+var statements = []struct {
+	prefix string
+	run    func(s *memoryStore, query string, args []driver.Value) (*result, error)
+}{
+	{"INSERT INTO contacts (id, user_id, name, email, phone) VALUES ($1, $2, $3, $4, $5) RETURNING", (*memoryStore).insertContact},
+	{"SELECT id, user_id, name, email, phone, created_at FROM contacts WHERE id = $1 AND user_id = $2", (*memoryStore).getContact},
+	{"SELECT id, user_id, name, email, phone, created_at FROM contacts WHERE user_id = $1 ORDER BY name, id", (*memoryStore).listContacts},
+	{"UPDATE contacts SET name = $1, email = $2, phone = $3 WHERE id = $4 AND user_id = $5 RETURNING", (*memoryStore).updateContact},
+	{"DELETE FROM contacts WHERE id = $1 AND user_id = $2", (*memoryStore).deleteContact},
+	{"INSERT INTO idempotency_keys (user_id, key, fingerprint)", (*memoryStore).claimKey},
+	{"SELECT fingerprint, status_code, headers, body, created_at FROM idempotency_keys WHERE user_id = $1 AND key = $2", (*memoryStore).getKey},
+	{"UPDATE idempotency_keys SET status_code = $1, headers = $2, body = $3", (*memoryStore).completeKey},
+	{"DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND fingerprint = $3 AND status_code IS NULL", (*memoryStore).releaseKey},
+	{"SELECT id, actor_id, action, entity_type, entity_id, task_id, COALESCE(before_data, 'null'), COALESCE(after_data, 'null'), request_id, created_at FROM audit_log", (*memoryStore).queryAudit},
+}
+
+// run runs a query with its arguments.
+func (s *memoryStore) run(query string, args []driver.Value) (*result, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	for _, statement := range statements {
+		if strings.HasPrefix(query, statement.prefix) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return statement.run(s, query, args)
+		}
+	}
+	return nil, fmt.Errorf("memory store: unsupported query %q", query)
+}
+
+var contactColumns = []string{"id", "user_id", "name", "email", "phone", "created_at"}
+
+func contactRow(contact models.Contact) []driver.Value {
+	return []driver.Value{contact.ID, contact.UserID, contact.Name, contact.Email, contact.Phone, contact.CreatedAt}
+}
+
+// findContact returns the index of a contact of a user, or -1.
+func (s *memoryStore) findContact(id, userID driver.Value) int {
+	for i, contact := range s.contacts {
+		if contact.ID == id && contact.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *memoryStore) insertContact(query string, args []driver.Value) (*result, error) {
+	contact := models.Contact{
+		ID:        args[0].(string),
+		UserID:    args[1].(string),
+		Name:      args[2].(string),
+		Email:     args[3].(string),
+		Phone:     args[4].(string),
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	s.contacts = append(s.contacts, contact)
+	return &result{columns: contactColumns, rows: [][]driver.Value{contactRow(contact)}, affected: 1}, nil
+}
+
+func (s *memoryStore) getContact(query string, args []driver.Value) (*result, error) {
+	res := &result{columns: contactColumns}
+	if i := s.findContact(args[0], args[1]); i >= 0 {
+		res.rows = append(res.rows, contactRow(s.contacts[i]))
+	}
+	return res, nil
+}
+
+func (s *memoryStore) listContacts(query string, args []driver.Value) (*result, error) {
+	var contacts []models.Contact
+	for _, contact := range s.contacts {
+		if contact.UserID == args[0] {
+			contacts = append(contacts, contact)
+		}
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].Name != contacts[j].Name {
+			return contacts[i].Name < contacts[j].Name
+		}
+		return contacts[i].ID < contacts[j].ID
+	})
+
+	res := &result{columns: contactColumns}
+	for _, contact := range contacts {
+		res.rows = append(res.rows, contactRow(contact))
+	}
+	return res, nil
+}
+
+func (s *memoryStore) updateContact(query string, args []driver.Value) (*result, error) {
+	res := &result{columns: contactColumns}
+	if i := s.findContact(args[3], args[4]); i >= 0 {
+		contact := &s.contacts[i]
+		contact.Name, contact.Email, contact.Phone = args[0].(string), args[1].(string), args[2].(string)
+		res.rows = append(res.rows, contactRow(*contact))
+		res.affected = 1
+	}
+	return res, nil
+}
+
+func (s *memoryStore) deleteContact(query string, args []driver.Value) (*result, error) {
+	res := &result{}
+	if i := s.findContact(args[0], args[1]); i >= 0 {
+		s.contacts = append(s.contacts[:i], s.contacts[i+1:]...)
+		res.affected = 1
+	}
+	return res, nil
+}
+
+// claimKey inserts a key, or claims it again once it expired.
+func (s *memoryStore) claimKey(query string, args []driver.Value) (*result, error) {
+	id := [2]string{args[0].(string), args[1].(string)}
+	stored, ok := s.keys[id]
+	if ok && !stored.createdAt.Before(args[3].(time.Time)) {
+		return &result{}, nil
+	}
+	s.keys[id] = &storedKey{fingerprint: args[2].(string), createdAt: time.Now()}
+	return &result{affected: 1}, nil
+}
+
+func (s *memoryStore) getKey(query string, args []driver.Value) (*result, error) {
+	res := &result{columns: []string{"fingerprint", "status_code", "headers", "body", "created_at"}}
+	if stored, ok := s.keys[[2]string{args[0].(string), args[1].(string)}]; ok {
+		res.rows = append(res.rows, []driver.Value{stored.fingerprint, stored.status, stored.headers, stored.body, stored.createdAt})
+	}
+	return res, nil
+}
+
+func (s *memoryStore) completeKey(query string, args []driver.Value) (*result, error) {
+	stored, ok := s.keys[[2]string{args[3].(string), args[4].(string)}]
+	if !ok || stored.fingerprint != args[5] || stored.status != nil {
+		return &result{}, nil
+	}
+	stored.status, stored.headers, stored.body = args[0], bytesValue(args[1]), bytesValue(args[2])
+	return &result{affected: 1}, nil
+}
+
+func (s *memoryStore) releaseKey(query string, args []driver.Value) (*result, error) {
+	id := [2]string{args[0].(string), args[1].(string)}
+	stored, ok := s.keys[id]
+	if !ok || stored.fingerprint != args[2] || stored.status != nil {
+		return &result{}, nil
+	}
+	delete(s.keys, id)
+	return &result{affected: 1}, nil
+}
+
+// bytesValue returns a copy of a BYTEA or JSONB argument.
+func bytesValue(value driver.Value) []byte {
+	switch value := value.(type) {
+	case []byte:
+		return append([]byte(nil), value...)
+	case string:
+		return []byte(value)
+	}
+	return nil
+}
+
+// queryAudit lists the audit entries matching the conditions of the query,
+// newest first.
+func (s *memoryStore) queryAudit(query string, args []driver.Value) (*result, error) {
+	_, rest, _ := strings.Cut(query, "FROM audit_log")
+	conds, limit, ok := strings.Cut(rest, " ORDER BY id DESC LIMIT ")
+	if !ok {
+		return nil, fmt.Errorf("memory store: unsupported audit query %q", query)
+	}
+	n, err := argument(limit, args)
+	if err != nil {
+		return nil, err
+	}
+	max := n.(int64)
+
+	res := &result{columns: []string{"id", "actor_id", "action", "entity_type", "entity_id", "task_id", "before_data", "after_data", "request_id", "created_at"}}
+	for i := len(s.audit) - 1; i >= 0 && int64(len(res.rows)) < max; i-- {
+		entry := s.audit[i]
+		match, err := s.matchAudit(entry, strings.TrimPrefix(conds, " WHERE "), args)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		res.rows = append(res.rows, []driver.Value{entry.ID, entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, entry.TaskID,
+			jsonValue(entry.Before), jsonValue(entry.After), entry.RequestID, entry.CreatedAt})
+	}
+	return res, nil
+}
+
+// matchAudit reports whether an entry matches conditions such as
+// "actor_id = $1 AND id < $2", or the condition on the owner of its task.
+func (s *memoryStore) matchAudit(entry models.AuditEntry, conds string, args []driver.Value) (bool, error) {
+	if conds == "" {
+		return true, nil
+	}
+	for _, cond := range strings.Split(conds, " AND ") {
+		if placeholder, ok := strings.CutPrefix(cond, "task_id IN (SELECT id FROM tasks WHERE user_id = "); ok {
+			userID, err := argument(strings.TrimSuffix(placeholder, ")"), args)
+			if err != nil {
+				return false, err
+			}
+			if s.owners[entry.TaskID] != userID {
+				return false, nil
+			}
+			continue
+		}
+
+		fields := strings.Fields(cond)
+		if len(fields) != 3 {
+			return false, fmt.Errorf("memory store: unsupported audit condition %q", cond)
+		}
+		value, err := argument(fields[2], args)
+		if err != nil {
+			return false, err
+		}
+
+		column, op := fields[0], fields[1]
+		var match bool
+		switch {
+		case column == "id" && op == "<":
+			match = entry.ID < value.(int64)
+		case column == "created_at" && op == ">=":
+			match = !entry.CreatedAt.Before(value.(time.Time))
+		case column == "created_at" && op == "<":
+			match = entry.CreatedAt.Before(value.(time.Time))
+		case op == "=":
+			fields := map[string]string{
+				"actor_id":    entry.ActorID,
+				"action":      entry.Action,
+				"entity_type": entry.EntityType,
+				"entity_id":   entry.EntityID,
+				"task_id":     entry.TaskID,
+				"request_id":  entry.RequestID,
+			}
+			field, ok := fields[column]
+			if !ok {
+				return false, fmt.Errorf("memory store: unsupported audit condition %q", cond)
+			}
+			match = field == value
+		default:
+			return false, fmt.Errorf("memory store: unsupported audit condition %q", cond)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// argument returns the argument of a placeholder such as $2.
+func argument(placeholder string, args []driver.Value) (driver.Value, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(placeholder, "$"))
+	if err != nil || n < 1 || n > len(args) {
+		return nil, fmt.Errorf("memory store: invalid placeholder %q", placeholder)
+	}
+	return args[n-1], nil
+}
+
+// jsonValue returns a JSONB column, 'null' when it is NULL.
+func jsonValue(data []byte) []byte {
+	if data == nil {
+		return []byte("null")
+	}
+	return data
+}
+
+// The store implements the database/sql/driver interfaces below, without
+// transactions.
+
+func (s *memoryStore) Connect(ctx context.Context) (driver.Conn, error) {
+	return memoryConn{s}, nil
+}
+
+func (s *memoryStore) Driver() driver.Driver {
+	return memoryDriver{s}
+}
+
+type memoryDriver struct {
+	store *memoryStore
+}
+
+func (d memoryDriver) Open(name string) (driver.Conn, error) {
+	return memoryConn{d.store}, nil
+}
+
+type memoryConn struct {
+	store *memoryStore
+}
+
+func (c memoryConn) Prepare(query string) (driver.Stmt, error) {
+	return memoryStmt{c.store, query}, nil
+}
+
+func (c memoryConn) Close() error {
+	return nil
+}
+
+func (c memoryConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("memory store: transactions are not supported")
+}
+
+type memoryStmt struct {
+	store *memoryStore
+	query string
+}
+
+func (st memoryStmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1, so that the number of arguments is not checked.
+func (st memoryStmt) NumInput() int {
+	return -1
+}
+
+func (st memoryStmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, err := st.store.run(st.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(res.affected), nil
+}
+
+func (st memoryStmt) Query(args []driver.Value) (driver.Rows, error) {
+	res, err := st.store.run(st.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryRows{result: res}, nil
+}
+
+type memoryRows struct {
+	*result
+	next int
+}
+
+func (r *memoryRows) Columns() []string {
+	return r.columns
+}
+
+func (r *memoryRows) Close() error {
+	return nil
+}
+
+func (r *memoryRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,272 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// defaultAuditPageSize is the number of audit entries fetched at once by an
+// AuditIterator without a limit
+const defaultAuditPageSize = 100
+
+// AuditIterator pages through the audit log, newest first:
+//
+//	it := c.AuditLog(models.AuditFilter{TaskID: id})
+//	for it.Next(ctx) {
+//		entry := it.Entry()
+//	}
+//	if err := it.Err(); err != nil {
+type AuditIterator struct {
+	c      *Client
+	filter models.AuditFilter // Limit is the page size, BeforeID the cursor
+	page   []models.AuditEntry
+	entry  models.AuditEntry
+	last   bool // The page is the last one
+	err    error
+}
+
+// AuditLog returns an iterator over the audit entries matching filter,
+// fetched filter.Limit at a time.
+func (c *Client) AuditLog(filter models.AuditFilter) *AuditIterator {
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	return &AuditIterator{c: c, filter: filter}
+}
+
+// Next advances to the next entry, fetching the next page when needed. It
+// returns false at the end of the log or on error.
+func (it *AuditIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+		it.page, it.err = it.c.GetAuditLog(ctx, it.filter)
+		if it.err != nil || len(it.page) == 0 {
+			return false
+		}
+		it.last = len(it.page) < it.filter.Limit
+		it.filter.BeforeID = it.page[len(it.page)-1].ID
+	}
+
+	it.entry, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Entry returns the current entry.
+func (it *AuditIterator) Entry() models.AuditEntry {
+	return it.entry
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *AuditIterator) Err() error {
+	return it.err
+}
+
+// EventStreamReset is the type of the event telling that events may have
+// been missed, and that the tasks should be reloaded
+const EventStreamReset = "stream.reset"
+
+// EventStream reads the events of the tasks of the user
+type EventStream struct {
+	resp    *http.Response
+	scanner *bufio.Scanner
+}
+
+// StreamEvents streams the changes of the tasks of the calling user. A
+// non-zero lastEventID resumes after that event.
+func (c *Client) StreamEvents(ctx context.Context, lastEventID int64) (*EventStream, error) {
+	req, _ := newRequest(http.MethodGet, "/events", nil)
+	if lastEventID != 0 {
+		req.header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{resp: resp, scanner: bufio.NewScanner(resp.Body)}, nil
+}
+
+// Next returns the next event, waiting for it. A stream.reset event has
+// only its type set.
+func (s *EventStream) Next() (models.Event, error) {
+	var eventType, data string
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		switch {
+		case line == "":
+			// End of an event, comments and heartbeats have no data
+			if data == "" {
+				continue
+			}
+			var event models.Event
+			if eventType == EventStreamReset {
+				event.Type = EventStreamReset
+				return event, nil
+			}
+			err := json.Unmarshal([]byte(data), &event)
+			return event, err
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return models.Event{}, err
+	}
+	return models.Event{}, errors.New("task api: event stream closed")
+}
+
+// Close ends the stream.
+func (s *EventStream) Close() error {
+	return s.resp.Body.Close()
+}
+
+// GraphQLError is an error of a GraphQL query
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path"`
+	Extensions map[string]interface{} `json:"extensions"` // Holds the error code
+}
+
+// GraphQLErrors lists the errors of a GraphQL query
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "task api: graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL runs a GraphQL query, and decodes its data into out. Errors of
+// the query are returned as GraphQLErrors, with the data resolved anyway.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body := map[string]interface{}{"query": query, "variables": variables}
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	err := c.call(ctx, http.MethodPost, "/graphql", body, &response)
+	if err != nil {
+		return err
+	}
+
+	if out != nil && len(response.Data) > 0 && string(response.Data) != "null" {
+		err = json.Unmarshal(response.Data, out)
+		if err != nil {
+			return err
+		}
+	}
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+	return nil
+}
+
+// Board commands
+const (
+	BoardSubscribe           = "subscribe"
+	BoardUnsubscribe         = "unsubscribe"
+	BoardMoveTask            = "moveTask"
+	BoardCompleteTask        = "completeTask"
+	BoardReopenTask          = "reopenTask"
+	BoardToggleChecklistItem = "toggleChecklistItem"
+)
+
+// Board messages
+const (
+	BoardResult = "result" // Reply to a successful command
+	BoardError  = "error"  // Reply to a failed command
+	BoardEvent  = "event"  // Change of a followed task
+	BoardReset  = "reset"  // Events may have been missed, reload the board
+)
+
+// BoardCommand is a command sent on a board connection
+type BoardCommand struct {
+	Type      string   `json:"type"`
+	ID        string   `json:"id"` // Echoed in the reply
+	Tasks     []string `json:"tasks,omitempty"`
+	Projects  []string `json:"projects,omitempty"`
+	TaskID    string   `json:"taskID,omitempty"`
+	ProjectID string   `json:"projectID,omitempty"` // Project to move the task to
+	Version   int64    `json:"version,omitempty"`   // Version the move is based on
+	Cascade   bool     `json:"cascade,omitempty"`
+	ItemID    string   `json:"itemID,omitempty"`
+}
+
+// BoardMessage is a message received on a board connection
+type BoardMessage struct {
+	Type          string                `json:"type"`
+	ID            string                `json:"id"` // ID of the command replied to
+	Task          *models.Task          `json:"task"`
+	ChecklistItem *models.ChecklistItem `json:"checklistItem"`
+	Event         *models.Event         `json:"event"`
+	Error         *Error                `json:"error"`
+}
+
+// Board is a WebSocket connection following task boards
+type Board struct {
+	ws *websocket.Conn
+}
+
+// DialBoard opens a connection for live task boards.
+func (c *Client) DialBoard(ctx context.Context) (*Board, error) {
+	u := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/ws"
+
+	// Collect the headers of a request, as edited for the other calls
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userID != "" {
+		req.Header.Set(auth.UserHeader, c.userID)
+	}
+	for _, edit := range c.editors {
+		err := edit(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ws, resp, err := websocket.DefaultDialer.DialContext(ctx, u, req.Header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= 400 {
+			return nil, readError(resp)
+		}
+		return nil, fmt.Errorf("task api: dialing board: %w", err)
+	}
+	return &Board{ws: ws}, nil
+}
+
+// Send sends a command, answered by a message with its ID.
+func (b *Board) Send(cmd BoardCommand) error {
+	return b.ws.WriteJSON(cmd)
+}
+
+// Receive returns the next message, waiting for it.
+func (b *Board) Receive() (BoardMessage, error) {
+	var message BoardMessage
+	err := b.ws.ReadJSON(&message)
+	return message, err
+}
+
+// Close closes the connection.
+func (b *Board) Close() error {
+	return b.ws.Close()
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Formats of task patches
+const (
+	MergePatch = "application/merge-patch+json" // RFC 7396
+	JSONPatch  = "application/json-patch+json"  // RFC 6902
+)
+
+// BatchResult is the outcome of an operation of a batch
+type BatchResult struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op"`
+	ID     string       `json:"id"`     // Task the operation applied to
+	Status int          `json:"status"` // Status the operation would have on its own
+	Task   *models.Task `json:"task"`   // Task after the operation, nil after a deletion
+	Error  *Error       `json:"error"`  // Why the operation failed
+}
+
+// filterQuery returns the query parameters of a task filter.
+func filterQuery(filter models.TaskFilter) url.Values {
+	query := url.Values{}
+	if len(filter.TagIDs) > 0 {
+		query.Set("tags", strings.Join(filter.TagIDs, ","))
+	}
+	if filter.TagMode != "" {
+		query.Set("tagMode", filter.TagMode)
+	}
+	if filter.ProjectID != "" {
+		query.Set("projectID", filter.ProjectID)
+	}
+	if filter.ContactID != "" {
+		query.Set("contactID", filter.ContactID)
+	}
+	if filter.UserID != "" {
+		query.Set("userID", filter.UserID)
+	}
+	return query
+}
+
+// ifMatch sets the If-Match precondition of a request on a task, unless
+// version is zero.
+func ifMatch(req *request, version int64) {
+	if version != 0 {
+		req.header.Set("If-Match", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+}
+
+// listTasks gets a list of tasks matching a filter.
+func (c *Client) listTasks(ctx context.Context, path string, query url.Values) ([]models.Task, error) {
+	req, _ := newRequest(http.MethodGet, path, nil)
+	req.query = query
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	return tasks, decode(resp, &tasks)
+}
+
+// CreateTask creates a task owned by the calling user, and returns it with
+// its generated IDs.
+func (c *Client) CreateTask(ctx context.Context, task models.Task) (models.Task, error) {
+	var created models.Task
+	return created, c.call(ctx, http.MethodPost, "/tasks", task, &created)
+}
+
+// GetTask returns a task with its direct subtasks.
+func (c *Client) GetTask(ctx context.Context, id string) (models.Task, error) {
+	var task models.Task
+	return task, c.call(ctx, http.MethodGet, resource("tasks", id), nil, &task)
+}
+
+// GetTaskWithSubtasks returns a task with its subtasks down to depth
+// levels.
+func (c *Client) GetTaskWithSubtasks(ctx context.Context, id string, depth int) (models.Task, error) {
+	req, _ := newRequest(http.MethodGet, resource("tasks", id), nil)
+	req.query = url.Values{"depth": {strconv.Itoa(depth)}}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return models.Task{}, err
+	}
+	var task models.Task
+	return task, decode(resp, &task)
+}
+
+// ListTasks returns the tasks matching a filter.
+func (c *Client) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	return c.listTasks(ctx, "/tasks", filterQuery(filter))
+}
+
+// ListNextTasks returns the tasks matching a filter in dependency order,
+// only the ones that are not blocked with readyOnly.
+func (c *Client) ListNextTasks(ctx context.Context, filter models.TaskFilter, readyOnly bool) ([]models.Task, error) {
+	query := filterQuery(filter)
+	if readyOnly {
+		query.Set("readyOnly", "true")
+	}
+	return c.listTasks(ctx, "/tasks/next", query)
+}
+
+// ListDueReminders returns the tasks matching a filter that are due.
+func (c *Client) ListDueReminders(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	return c.listTasks(ctx, "/tasks/dueReminders", filterQuery(filter))
+}
+
+// UpdateTask replaces a task, and returns its new version. A non-zero
+// version must match the current one.
+func (c *Client) UpdateTask(ctx context.Context, id string, task models.Task, version int64) (int64, error) {
+	req, err := newRequest(http.MethodPut, resource("tasks", id), task)
+	if err != nil {
+		return 0, err
+	}
+	ifMatch(req, version)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	newVersion, _ := strconv.ParseInt(strings.Trim(resp.Header.Get("ETag"), `"`), 10, 64)
+	return newVersion, decode(resp, nil)
+}
+
+// PatchTask applies a patch in the given format, MergePatch or JSONPatch,
+// to a task. A non-zero version must match the current one.
+func (c *Client) PatchTask(ctx context.Context, id, format string, patch []byte, version int64) (models.Task, error) {
+	req := &request{method: http.MethodPatch, path: resource("tasks", id), header: http.Header{}, body: patch, contentType: format}
+	ifMatch(req, version)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return models.Task{}, err
+	}
+	var task models.Task
+	return task, decode(resp, &task)
+}
+
+// DeleteTask moves a task to the trash. A non-zero version must match the
+// current one.
+func (c *Client) DeleteTask(ctx context.Context, id string, version int64) error {
+	req, _ := newRequest(http.MethodDelete, resource("tasks", id), nil)
+	ifMatch(req, version)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	return decode(resp, nil)
+}
+
+// CompleteTask completes a task, and its subtasks and checklist items with
+// cascade.
+func (c *Client) CompleteTask(ctx context.Context, id string, cascade bool) (models.Task, error) {
+	req, _ := newRequest(http.MethodPost, resource("tasks", id, "complete"), nil)
+	if cascade {
+		req.query = url.Values{"cascade": {"true"}}
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return models.Task{}, err
+	}
+	var task models.Task
+	return task, decode(resp, &task)
+}
+
+// ReopenTask reopens a completed task.
+func (c *Client) ReopenTask(ctx context.Context, id string) (models.Task, error) {
+	var task models.Task
+	return task, c.call(ctx, http.MethodPost, resource("tasks", id, "reopen"), nil, &task)
+}
+
+// ApplyBatch applies a batch of operations to tasks. In atomic mode a
+// failed operation fails the batch, in best effort mode the result of each
+// operation holds its error.
+func (c *Client) ApplyBatch(ctx context.Context, batch models.BatchRequest) ([]BatchResult, error) {
+	var results []BatchResult
+	return results, c.call(ctx, http.MethodPost, "/tasks/batch", batch, &results)
+}
+
+// QuickAdd parses a task written as free text, such as "Call John at 2:30
+// pm on Monday", and creates it unless req.DryRun is set.
+func (c *Client) QuickAdd(ctx context.Context, req models.QuickAddRequest) (models.QuickAddResult, error) {
+	var result models.QuickAddResult
+	return result, c.call(ctx, http.MethodPost, "/tasks/quickAdd", req, &result)
+}
+
+// ListTrash returns the tasks in the trash matching a filter.
+func (c *Client) ListTrash(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	return c.listTasks(ctx, "/tasks/trash", filterQuery(filter))
+}
+
+// RestoreTask moves a task out of the trash.
+func (c *Client) RestoreTask(ctx context.Context, id string) (models.Task, error) {
+	var task models.Task
+	return task, c.call(ctx, http.MethodPost, resource("tasks", "trash", id, "restore"), nil, &task)
+}
+
+// PurgeTask deletes a task in the trash for good.
+func (c *Client) PurgeTask(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, resource("tasks", "trash", id), nil, nil)
+}
+
+// AddDependency blocks a task until the blocking one is completed, and
+// returns the task with its updated status.
+func (c *Client) AddDependency(ctx context.Context, id, blockedByID string) (models.Task, error) {
+	var task models.Task
+	body := map[string]string{"blockedByID": blockedByID}
+	return task, c.call(ctx, http.MethodPost, resource("tasks", id, "dependencies"), body, &task)
+}
+
+// RemoveDependency stops a task from waiting on the blocking one.
+func (c *Client) RemoveDependency(ctx context.Context, id, blockedByID string) (models.Task, error) {
+	var task models.Task
+	body := map[string]string{"blockedByID": blockedByID}
+	return task, c.call(ctx, http.MethodDelete, resource("tasks", id, "dependencies"), body, &task)
+}
+
+// GetTaskActivity returns the comments and changes of a task, oldest
+// first.
+func (c *Client) GetTaskActivity(ctx context.Context, id string) ([]models.Activity, error) {
+	var activity []models.Activity
+	return activity, c.call(ctx, http.MethodGet, resource("tasks", id, "activity"), nil, &activity)
+}
+
+// ListReminders returns the reminders of a task.
+func (c *Client) ListReminders(ctx context.Context, taskID string) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	return reminders, c.call(ctx, http.MethodGet, resource("tasks", taskID, "reminders"), nil, &reminders)
+}
+
+// CreateReminder adds a reminder to a task, at a date in RFC3339 format.
+func (c *Client) CreateReminder(ctx context.Context, taskID, date string) (models.Reminder, error) {
+	var reminder models.Reminder
+	return reminder, c.call(ctx, http.MethodPost, resource("tasks", taskID, "reminders"), models.Reminder{Date: date}, &reminder)
+}
+
+// UpdateReminder changes the date of a reminder.
+func (c *Client) UpdateReminder(ctx context.Context, taskID, reminderID, date string) (models.Reminder, error) {
+	var reminder models.Reminder
+	return reminder, c.call(ctx, http.MethodPut, resource("tasks", taskID, "reminders", reminderID), models.Reminder{Date: date}, &reminder)
+}
+
+// DeleteReminder removes a reminder from a task.
+func (c *Client) DeleteReminder(ctx context.Context, taskID, reminderID string) error {
+	return c.call(ctx, http.MethodDelete, resource("tasks", taskID, "reminders", reminderID), nil, nil)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the content of the config file
+type config struct {
+	Server string `yaml:"server"`          // URL of the API, e.g. http://localhost:8080
+	UserID string `yaml:"userID"`          // Sent as the X-User-ID header
+	Token  string `yaml:"token,omitempty"` // Bearer token for a gateway in front of the API
+}
+
+// defaultConfigPath returns the path of the config file in the user's
+// config directory, e.g. ~/.config/taskctl/config.yaml.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "taskctl.yaml"
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing file is an empty
+// config.
+func loadConfig(path string) (config, error) {
+	var cfg config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	err = yaml.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file at path, readable by the user only as
+// it holds credentials.
+func saveConfig(path string, cfg config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func loginCmd() *cobra.Command {
+	var token string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Save the server and credentials in the config file",
+		Example: `  taskctl login --server http://localhost:8080 --user 42
+  taskctl login --token "$GATEWAY_TOKEN"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return err
+			}
+			if serverFlag != "" {
+				cfg.Server = serverFlag
+			}
+			if userFlag != "" {
+				cfg.UserID = userFlag
+			}
+			if cmd.Flags().Changed("token") {
+				cfg.Token = token
+			}
+			if cfg.Server == "" {
+				return fmt.Errorf("--server is required")
+			}
+			err = saveConfig(configPath, cfg)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved %s\n", configPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&token, "token", "", "bearer token for a gateway in front of the API")
+	return cmd
+}
+
+func tzCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tz [time zone]",
+		Short: "Show or set the time zone of the user",
+		Long: `Shows the time zone of the user, or sets it to an IANA time zone. The server uses it for the dates of
+quick add and of the calendar feed.`,
+		Example: `  taskctl tz
+  taskctl tz Europe/Paris`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			if c.UserID() == "" {
+				return fmt.Errorf("no user configured, run taskctl login --user first")
+			}
+			user, err := c.GetUser(cmd.Context(), c.UserID())
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				user.TimeZone = args[0]
+				user, err = c.UpdateUser(cmd.Context(), user.ID, user)
+				if err != nil {
+					return err
+				}
+			}
+			if user.TimeZone == "" {
+				user.TimeZone = "UTC"
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), user.TimeZone)
+			return nil
+		},
+	}
+}
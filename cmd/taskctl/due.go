@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/quickadd"
+)
+
+// parseDue parses a due date relative to now, in the time zone of now: in
+// RFC3339 format, or as understood by quick add, such as "2026-11-02 14:30",
+// "today 17:00", "tomorrow", "mon 14:30", "2:30pm" or "in 2h". A day
+// without a time is due at 9:00, and a weekday or time of day that has
+// passed means the next one.
+func parseDue(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return quickadd.ParseDue(value, now)
+}
+
+// parseOffset parses a duration like time.ParseDuration, also accepting
+// days, e.g. "15m", "2h30m" or "1d".
+func parseOffset(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,100 @@
+// Command taskctl manages tasks from the terminal through the HTTP API.
+//
+//	taskctl login --server http://localhost:8080 --user 42
+//	taskctl add "Call John" --due "mon 14:30" --remind 15m
+//	taskctl quick "Call John at 2:30 pm on Monday !high"
+//	taskctl ls --overdue
+//	taskctl done 0190b1b4-7c2a-7d4e-9a51-3c6f2e8b1a20
+//
+// Run taskctl completion --help to set up shell completion.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vikash-parashar/task-manager-2/client"
+)
+
+// Global flags
+var (
+	configPath   string
+	serverFlag   string
+	userFlag     string
+	outputFormat string
+)
+
+func main() {
+	err := rootCmd().Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// rootCmd returns the taskctl command with all its subcommands.
+func rootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "taskctl",
+		Short:        "Manage tasks and reminders from the terminal",
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().StringVar(&configPath, "config", defaultConfigPath(), "config file holding the server and credentials")
+	root.PersistentFlags().StringVar(&serverFlag, "server", "", "URL of the API, overriding the config file")
+	root.PersistentFlags().StringVar(&userFlag, "user", "", "ID of the user, overriding the config file")
+	root.PersistentFlags().StringVarP(&outputFormat, "output", "o", formatTable, "output format: table, json or yaml")
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{formatTable, formatJSON, formatYAML}, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(
+		loginCmd(),
+		addCmd(),
+		quickCmd(),
+		lsCmd(),
+		showCmd(),
+		doneCmd(),
+		reopenCmd(),
+		editCmd(),
+		rmCmd(),
+		remindCmd(),
+		tzCmd(),
+	)
+	return root
+}
+
+// newClient returns a client of the API configured by the config file, the
+// environment and the global flags, in increasing order of precedence.
+func newClient() (*client.Client, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if v := os.Getenv("TASKCTL_SERVER"); v != "" {
+		cfg.Server = v
+	}
+	if v := os.Getenv("TASKCTL_USER"); v != "" {
+		cfg.UserID = v
+	}
+	if v := os.Getenv("TASKCTL_TOKEN"); v != "" {
+		cfg.Token = v
+	}
+	if serverFlag != "" {
+		cfg.Server = serverFlag
+	}
+	if userFlag != "" {
+		cfg.UserID = userFlag
+	}
+	if cfg.Server == "" {
+		return nil, fmt.Errorf("no server configured, run taskctl login first")
+	}
+
+	opts := []client.Option{client.WithUserID(cfg.UserID)}
+	if cfg.Token != "" {
+		token := cfg.Token
+		opts = append(opts, client.WithRequestEditor(func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		}))
+	}
+	return client.New(cfg.Server, opts...), nil
+}
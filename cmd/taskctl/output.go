@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// printValue prints v in the output format, using table to print it as a
+// table.
+func printValue(w io.Writer, v interface{}, table func(*tabwriter.Writer)) error {
+	switch outputFormat {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		// Go through JSON so the fields have the names of the API
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var doc interface{}
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		return enc.Encode(doc)
+	default:
+		return fmt.Errorf("unknown output format %q, must be table, json or yaml", outputFormat)
+	}
+}
+
+// printTasks prints tasks, one per row of the table.
+func printTasks(w io.Writer, tasks []models.Task) error {
+	return printValue(w, tasks, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tTITLE\tDUE\tPRIORITY\tSTATUS\tREMINDERS")
+		for _, task := range tasks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", task.ID, task.Title, formatDue(task.DueDateTime),
+				dash(task.Priority), task.Status, len(task.Reminders))
+		}
+	})
+}
+
+// printTask prints the details of a task.
+func printTask(w io.Writer, task models.Task) error {
+	return printValue(w, task, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "ID:\t%s\n", task.ID)
+		fmt.Fprintf(tw, "Title:\t%s\n", task.Title)
+		fmt.Fprintf(tw, "Description:\t%s\n", dash(task.Description))
+		fmt.Fprintf(tw, "Priority:\t%s\n", dash(task.Priority))
+		fmt.Fprintf(tw, "Due:\t%s\n", formatDue(task.DueDateTime))
+		fmt.Fprintf(tw, "Status:\t%s\n", task.Status)
+		fmt.Fprintf(tw, "Project:\t%s\n", dash(task.ProjectID))
+		fmt.Fprintf(tw, "Contact:\t%s\n", dash(task.ContactID))
+		tags := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			tags[i] = tag.Name
+		}
+		fmt.Fprintf(tw, "Tags:\t%s\n", dash(strings.Join(tags, ", ")))
+		for _, reminder := range task.Reminders {
+			fmt.Fprintf(tw, "Reminder:\t%s\t%s\n", formatReminder(reminder.Date), reminder.ID)
+		}
+		fmt.Fprintf(tw, "Version:\t%d\n", task.Version)
+	})
+}
+
+// printQuickAdd prints a task parsed from free text, with how its text was
+// understood.
+func printQuickAdd(w io.Writer, result models.QuickAddResult) error {
+	if outputFormat != formatTable {
+		return printValue(w, result, nil)
+	}
+	if result.Created {
+		err := printTask(w, result.Task)
+		if err != nil {
+			return err
+		}
+	}
+	return printValue(w, result, func(tw *tabwriter.Writer) {
+		if !result.Created {
+			fmt.Fprintf(tw, "Title:\t%s\n", result.Task.Title)
+			fmt.Fprintf(tw, "Due:\t%s\n", formatDue(result.Task.DueDateTime))
+		}
+		fmt.Fprintf(tw, "Time zone:\t%s\n", result.TimeZone)
+		for _, token := range result.Tokens {
+			fmt.Fprintf(tw, "%s:\t%s\t%s\n", token.Kind, token.Text, token.Value)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(tw, "Warning:\t%s\n", warning)
+		}
+	})
+}
+
+// printReminders prints reminders, one per row of the table.
+func printReminders(w io.Writer, reminders []models.Reminder) error {
+	return printValue(w, reminders, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tDATE\tTASK")
+		for _, reminder := range reminders {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", reminder.ID, formatReminder(reminder.Date), reminder.TaskID)
+		}
+	})
+}
+
+// formatDue formats a due date in the local time zone.
+func formatDue(due time.Time) string {
+	if due.IsZero() {
+		return "-"
+	}
+	return due.Local().Format("Mon 2006-01-02 15:04")
+}
+
+// formatReminder formats an RFC3339 reminder date in the local time zone.
+func formatReminder(date string) string {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date
+	}
+	return formatDue(t)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// parseReminder parses a reminder, either as an offset before the due date
+// such as "15m", or as a date like parseDue, and returns its date in
+// RFC3339 format.
+func parseReminder(value string, due, now time.Time) (string, error) {
+	if offset, err := parseOffset(value); err == nil {
+		if due.IsZero() {
+			return "", fmt.Errorf("reminder %q is relative to the due date, but the task has none", value)
+		}
+		return due.Add(-offset).Format(time.RFC3339), nil
+	}
+	date, err := parseDue(value, now)
+	if err != nil {
+		return "", fmt.Errorf("invalid reminder %q, must be an offset such as 15m or a date", value)
+	}
+	return date.Format(time.RFC3339), nil
+}
+
+func remindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remind",
+		Short: "Manage the reminders of a task",
+		Example: `  taskctl remind add 0190b1b4-7c2a-7d4e-9a51-3c6f2e8b1a20 15m
+  taskctl remind ls 0190b1b4-7c2a-7d4e-9a51-3c6f2e8b1a20`,
+	}
+	cmd.AddCommand(remindAddCmd(), remindLsCmd(), remindRmCmd())
+	return cmd
+}
+
+func remindAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "add <task id> <when>...",
+		Short:             "Add reminders, as offsets before the due date or as dates",
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeFirstTaskID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			task, err := c.GetTask(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, value := range args[1:] {
+				date, err := parseReminder(value, task.DueDateTime, now)
+				if err != nil {
+					return err
+				}
+				reminder, err := c.CreateReminder(cmd.Context(), task.ID, date)
+				if err != nil {
+					return err
+				}
+				task.Reminders = append(task.Reminders, reminder)
+			}
+			return printReminders(cmd.OutOrStdout(), task.Reminders)
+		},
+	}
+}
+
+func remindLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "ls <task id>",
+		Aliases:           []string{"list"},
+		Short:             "List the reminders of a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeFirstTaskID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			reminders, err := c.ListReminders(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return printReminders(cmd.OutOrStdout(), reminders)
+		},
+	}
+}
+
+func remindRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <task id> <reminder id>...",
+		Short: "Remove reminders from a task",
+		Args:  cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeTaskIDs(cmd, args, toComplete)
+			}
+			c, err := newClient()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			reminders, err := c.ListReminders(cmd.Context(), args[0])
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			var ids []string
+			for _, reminder := range reminders {
+				ids = append(ids, reminder.ID+"\t"+formatReminder(reminder.Date))
+			}
+			return ids, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			for _, id := range args[1:] {
+				err = c.DeleteReminder(cmd.Context(), args[0], id)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// completeFirstTaskID completes the task ID of commands taking it as their
+// first argument.
+func completeFirstTaskID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeTaskIDs(cmd, args, toComplete)
+}
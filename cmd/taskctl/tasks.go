@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vikash-parashar/task-manager-2/client"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+func addCmd() *cobra.Command {
+	var task models.Task
+	var due string
+	var remind, tags []string
+	cmd := &cobra.Command{
+		Use:   "add <title>",
+		Short: "Create a task",
+		Example: `  taskctl add "Call John" --due "mon 14:30" --remind 15m
+  taskctl add "Send report" --due tomorrow --priority high --remind 1d --remind 1h`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			task.Title = strings.Join(args, " ")
+			if due != "" {
+				task.DueDateTime, err = parseDue(due, now)
+				if err != nil {
+					return err
+				}
+			}
+			for _, value := range remind {
+				date, err := parseReminder(value, task.DueDateTime, now)
+				if err != nil {
+					return err
+				}
+				task.Reminders = append(task.Reminders, models.Reminder{Date: date})
+			}
+
+			created, err := c.CreateTask(cmd.Context(), task)
+			if err != nil {
+				return err
+			}
+			if len(tags) > 0 {
+				created.Tags, err = c.AttachTags(cmd.Context(), created.ID, tags)
+				if err != nil {
+					return err
+				}
+			}
+			return printTask(cmd.OutOrStdout(), created)
+		},
+	}
+	cmd.Flags().StringVar(&due, "due", "", `due date, e.g. "mon 14:30", "tomorrow", "in 2h" or RFC3339`)
+	cmd.Flags().StringArrayVar(&remind, "remind", nil, `reminder, as an offset before the due date such as "15m" or "1d", or a date like --due`)
+	cmd.Flags().StringVarP(&task.Description, "description", "d", "", "description")
+	cmd.Flags().StringVarP(&task.Priority, "priority", "p", "", "priority, e.g. high")
+	cmd.Flags().StringVar(&task.ProjectID, "project", "", "ID of the project")
+	cmd.Flags().StringVar(&task.ContactID, "contact", "", "ID of the contact the task is about")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "IDs of tags to attach")
+	cmd.Flags().StringVar(&task.ParentID, "parent", "", "ID of the parent task")
+	cmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
+	return cmd
+}
+
+func quickCmd() *cobra.Command {
+	var req models.QuickAddRequest
+	cmd := &cobra.Command{
+		Use:   "quick <text>",
+		Short: "Create a task written as free text",
+		Long: `Creates a task written as free text, parsed by the server: its due date, "!high" priority, "#tags",
+"@contact" and "remind me 15m before" reminders are taken out of the title. With --dry-run the task is
+only parsed, to check how the text is understood.`,
+		Example: `  taskctl quick "Call John at 2:30 pm on Monday !high #work remind me 15m before"
+  taskctl quick --dry-run "Pay rent in 3 days"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			req.Text = strings.Join(args, " ")
+			result, err := c.QuickAdd(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return printQuickAdd(cmd.OutOrStdout(), result)
+		},
+	}
+	cmd.Flags().BoolVar(&req.DryRun, "dry-run", false, "only parse the text, without creating the task")
+	cmd.Flags().StringVar(&req.TimeZone, "tz", "", "IANA time zone of the dates, such as Europe/Paris, defaults to the time zone of the user")
+	return cmd
+}
+
+func lsCmd() *cobra.Command {
+	var filter models.TaskFilter
+	var overdue, all bool
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List tasks by due date",
+		Long:    "Lists the open tasks of the user by due date, with --all also the completed ones.",
+		Example: `  taskctl ls --overdue
+  taskctl ls --project 0190b1b4-5a61-7c1e-8d2f-4b7e9c3a1d05 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			filter.UserID = c.UserID()
+			tasks, err := c.ListTasks(cmd.Context(), filter)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			listed := []models.Task{}
+			for _, task := range tasks {
+				if task.Status == models.TaskStatusCompleted && !all {
+					continue
+				}
+				if overdue && (task.Status == models.TaskStatusCompleted || task.DueDateTime.IsZero() || !task.DueDateTime.Before(now)) {
+					continue
+				}
+				listed = append(listed, task)
+			}
+			sort.SliceStable(listed, func(i, j int) bool {
+				a, b := listed[i].DueDateTime, listed[j].DueDateTime
+				if a.IsZero() || b.IsZero() {
+					return !a.IsZero()
+				}
+				return a.Before(b)
+			})
+			return printTasks(cmd.OutOrStdout(), listed)
+		},
+	}
+	cmd.Flags().BoolVar(&overdue, "overdue", false, "only list open tasks past their due date")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "also list completed tasks")
+	cmd.Flags().StringVar(&filter.ProjectID, "project", "", "only list the tasks of a project")
+	cmd.Flags().StringVar(&filter.ContactID, "contact", "", "only list the tasks about a contact")
+	cmd.Flags().StringSliceVar(&filter.TagIDs, "tag", nil, "only list the tasks with these tag IDs")
+	cmd.Flags().StringVar(&filter.TagMode, "tag-mode", "", "and (the default) or or, whether tasks need all or any of the tags")
+	return cmd
+}
+
+func showCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "show <id>",
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			task, err := c.GetTask(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), task)
+		},
+	}
+}
+
+func doneCmd() *cobra.Command {
+	var cascade bool
+	cmd := &cobra.Command{
+		Use:               "done <id>...",
+		Short:             "Complete tasks",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			var tasks []models.Task
+			for _, id := range args {
+				task, err := c.CompleteTask(cmd.Context(), id, cascade)
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, task)
+			}
+			return printTasks(cmd.OutOrStdout(), tasks)
+		},
+	}
+	cmd.Flags().BoolVar(&cascade, "cascade", false, "also complete the subtasks and checklist items")
+	return cmd
+}
+
+func reopenCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "reopen <id>",
+		Short:             "Reopen a completed task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			task, err := c.ReopenTask(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), task)
+		},
+	}
+}
+
+func editCmd() *cobra.Command {
+	var title, description, priority, due, project, contact string
+	var version int64
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Change fields of a task",
+		Long:  "Changes the fields given as flags, and keeps the others. An empty --due, --project or --contact clears it.",
+		Example: `  taskctl edit 0190b1b4-7c2a-7d4e-9a51-3c6f2e8b1a20 --due "fri 10:00" --priority low
+  taskctl edit 0190b1b4-7c2a-7d4e-9a51-3c6f2e8b1a20 --project ""`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			patch := map[string]interface{}{}
+			flags := cmd.Flags()
+			if flags.Changed("title") {
+				patch["title"] = title
+			}
+			if flags.Changed("description") {
+				patch["description"] = description
+			}
+			if flags.Changed("priority") {
+				patch["priority"] = priority
+			}
+			if flags.Changed("due") {
+				patch["dueDateTime"] = nil
+				if due != "" {
+					dueDateTime, err := parseDue(due, time.Now())
+					if err != nil {
+						return err
+					}
+					patch["dueDateTime"] = dueDateTime
+				}
+			}
+			if flags.Changed("project") {
+				patch["projectID"] = project
+			}
+			if flags.Changed("contact") {
+				patch["contactID"] = contact
+			}
+			if len(patch) == 0 {
+				return fmt.Errorf("nothing to change, pass the fields to change as flags")
+			}
+
+			body, err := json.Marshal(patch)
+			if err != nil {
+				return err
+			}
+			task, err := c.PatchTask(cmd.Context(), args[0], client.MergePatch, body, version)
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), task)
+		},
+	}
+	cmd.Flags().StringVar(&title, "title", "", "title")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "description")
+	cmd.Flags().StringVarP(&priority, "priority", "p", "", "priority, e.g. high")
+	cmd.Flags().StringVar(&due, "due", "", `due date, e.g. "mon 14:30", "tomorrow", "in 2h" or RFC3339`)
+	cmd.Flags().StringVar(&project, "project", "", "ID of the project")
+	cmd.Flags().StringVar(&contact, "contact", "", "ID of the contact the task is about")
+	cmd.Flags().Int64Var(&version, "version", 0, "only change the task if it is still at this version")
+	return cmd
+}
+
+func rmCmd() *cobra.Command {
+	var purge bool
+	cmd := &cobra.Command{
+		Use:               "rm <id>...",
+		Short:             "Move tasks to the trash",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				err = c.DeleteTask(cmd.Context(), id, 0)
+				if err != nil {
+					return err
+				}
+				if purge {
+					err = c.PurgeTask(cmd.Context(), id)
+					if err != nil {
+						return err
+					}
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&purge, "purge", false, "delete for good instead of moving to the trash")
+	return cmd
+}
+
+// completeTaskIDs completes the IDs of the open tasks, described by their
+// titles.
+func completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	c, err := newClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tasks, err := c.ListTasks(cmd.Context(), models.TaskFilter{UserID: c.UserID()})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for _, task := range tasks {
+		if task.Status != models.TaskStatusCompleted && strings.HasPrefix(task.ID, toComplete) {
+			ids = append(ids, task.ID+"\t"+task.Title)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
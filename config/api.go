@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// LegacyRoutesDeprecated is when the unversioned routes were superseded by
+// the /v1 routes
+var LegacyRoutesDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// LegacyRoutesSunset is when the unversioned routes will be removed, set by
+// LEGACY_ROUTES_SUNSET
+var LegacyRoutesSunset = getEnvTime("LEGACY_ROUTES_SUNSET", time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC))
@@ -0,0 +1,5 @@
+package config
+
+// MaxBatchOperations is the largest number of operations in a batch, set
+// by MAX_BATCH_OPERATIONS
+var MaxBatchOperations = int(getEnvInt64("MAX_BATCH_OPERATIONS", 100))
@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+// CalendarRefreshInterval is how often calendar apps are asked to fetch the
+// calendar feeds again, set by CALENDAR_REFRESH_INTERVAL
+var CalendarRefreshInterval = getEnvDuration("CALENDAR_REFRESH_INTERVAL", 15*time.Minute)
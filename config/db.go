@@ -27,8 +27,7 @@ var DB *sql.DB
 // @Router /init [get]
 func InitDB() error {
 	var err error
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbname)
-	DB, err = sql.Open("postgres", connStr)
+	DB, err = sql.Open("postgres", connString())
 	if err != nil {
 		panic(err)
 	}
@@ -41,3 +40,8 @@ func InitDB() error {
 	fmt.Println("Connected to the database")
 	return nil
 }
+
+// connString returns the connection string of the database.
+func connString() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbname)
+}
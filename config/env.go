@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// getEnv returns the value of an environment variable, or fallback if unset.
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt64 returns an integer environment variable, or fallback if unset
+// or invalid.
+func getEnvInt64(key string, fallback int64) int64 {
+	value, err := strconv.ParseInt(getEnv(key, ""), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvDuration returns a duration environment variable such as "720h", or
+// fallback if unset or invalid.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvTime returns a time environment variable in RFC3339 format, or
+// fallback if unset or invalid.
+func getEnvTime(key string, fallback time.Time) time.Time {
+	value, err := time.Parse(time.RFC3339, getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/events"
+)
+
+// Events streams the changes of tasks
+var Events *events.Broker
+
+// EventBufferSize is how many of the latest events are kept for clients
+// resuming a stream, set by EVENT_BUFFER_SIZE
+var EventBufferSize = getEnvInt64("EVENT_BUFFER_SIZE", 1000)
+
+// EventHeartbeat is how often an idle event stream sends a heartbeat, set
+// by EVENT_HEARTBEAT
+var EventHeartbeat = getEnvDuration("EVENT_HEARTBEAT", 15*time.Second)
+
+// InitEvents starts receiving the events notified by the database, from
+// this instance of the server and the others.
+func InitEvents() {
+	Events = events.NewBroker(int(EventBufferSize))
+	go Events.Listen(connString())
+}
@@ -0,0 +1,12 @@
+package config
+
+// Limits of GraphQL queries
+var (
+	// GraphQLMaxDepth is the deepest nesting of fields in a query, set by
+	// GRAPHQL_MAX_DEPTH
+	GraphQLMaxDepth = int(getEnvInt64("GRAPHQL_MAX_DEPTH", 8))
+
+	// GraphQLMaxComplexity is the largest estimated number of fields a query
+	// may resolve, set by GRAPHQL_MAX_COMPLEXITY
+	GraphQLMaxComplexity = int(getEnvInt64("GRAPHQL_MAX_COMPLEXITY", 1000))
+)
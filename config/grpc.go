@@ -0,0 +1,4 @@
+package config
+
+// GRPCPort is the port of the gRPC TaskService, set by GRPC_PORT
+var GRPCPort = getEnvInt64("GRPC_PORT", 9090)
@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+// IdempotencyKeyTTL is how long the response to a request with an
+// Idempotency-Key is kept for replay, set by IDEMPOTENCY_KEY_TTL
+var IdempotencyKeyTTL = getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vikash-parashar/task-manager-2/storage"
+)
+
+// Storage holds the content of attachments
+var Storage storage.Store
+
+// MaxAttachmentSize is the largest attachment accepted, in bytes
+var MaxAttachmentSize int64
+
+// InitStorage sets up the attachment storage from the environment.
+// STORAGE_DRIVER selects "local" (the default, under STORAGE_DIR) or "s3",
+// configured by the S3_* variables.
+func InitStorage() error {
+	MaxAttachmentSize = getEnvInt64("MAX_ATTACHMENT_SIZE", 10<<20)
+
+	var err error
+	switch driver := getEnv("STORAGE_DRIVER", "local"); driver {
+	case "local":
+		Storage, err = storage.NewLocal(getEnv("STORAGE_DIR", "./uploads"))
+	case "s3":
+		Storage, err = storage.NewS3(context.Background(), storage.S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", "localhost:9000"),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Bucket:          getEnv("S3_BUCKET", "task-manager-two"),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			UseSSL:          getEnv("S3_USE_SSL", "false") == "true",
+		})
+	default:
+		return fmt.Errorf("unknown storage driver %q", driver)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Attachment storage ready")
+	return nil
+}
@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+// TrashRetention is how long deleted tasks stay in the trash before being
+// purged, set by TRASH_RETENTION
+var TrashRetention = getEnvDuration("TRASH_RETENTION", 30*24*time.Hour)
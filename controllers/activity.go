@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// recordActivity adds an entry to the activity feed of a task.
+func recordActivity(q queryer, taskID, actorID, activityType, field, oldValue, newValue string) error {
+	_, err := q.Exec("INSERT INTO task_activity (task_id, actor_id, type, field, old_value, new_value) VALUES ($1, $2, $3, $4, $5, $6)",
+		taskID, actorID, activityType, field, oldValue, newValue)
+	return err
+}
+
+// recordTaskChanges adds an ActivityChanged entry for every field that
+// differs between two versions of a task.
+func recordTaskChanges(q queryer, actorID string, before, after models.Task) error {
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"priority", before.Priority, after.Priority},
+		{"dueDateTime", formatTime(before.DueDateTime), formatTime(after.DueDateTime)},
+		{"projectID", before.ProjectID, after.ProjectID},
+		{"parentID", before.ParentID, after.ParentID},
+		{"notifyMethod", before.NotifyMethod, after.NotifyMethod},
+		{"estimateSeconds", strconv.FormatInt(before.EstimateSeconds, 10), strconv.FormatInt(after.EstimateSeconds, 10)},
+		{"recurrence", before.Recurrence, after.Recurrence},
+		{"reminders", reminderDates(before.Reminders), reminderDates(after.Reminders)},
+	}
+
+	for _, field := range fields {
+		if field.before == field.after {
+			continue
+		}
+		err := recordActivity(q, before.ID, actorID, models.ActivityChanged, field.name, field.before, field.after)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// reminderDates summarizes reminders as their sorted, comma separated dates.
+func reminderDates(reminders []models.Reminder) string {
+	dates := make([]string, len(reminders))
+	for i, reminder := range reminders {
+		dates[i] = reminder.Date
+	}
+	sort.Strings(dates)
+	return strings.Join(dates, ", ")
+}
+
+// @Summary Get the activity of a task
+// @Description Retrieves the comments and changes of a task in a single feed, oldest first
+// @ID get-task-activity
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Activity "Successfully retrieved activity"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/activity [get]
+func GetTaskActivity(taskID string) ([]models.Activity, error) {
+	err := checkTaskExists(config.DB, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query("SELECT actor_id, type, field, old_value, new_value, created_at FROM task_activity WHERE task_id = $1 ORDER BY id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feed []models.Activity
+	for rows.Next() {
+		var activity models.Activity
+		err := rows.Scan(&activity.ActorID, &activity.Type, &activity.Field, &activity.OldValue, &activity.NewValue, &activity.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		feed = append(feed, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	comments, err := GetComments(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		feed = append(feed, models.Activity{
+			Type:      models.ActivityComment,
+			ActorID:   comments[i].AuthorID,
+			CreatedAt: comments[i].CreatedAt,
+			Comment:   &comments[i],
+		})
+	}
+
+	sort.SliceStable(feed, func(i, j int) bool {
+		return feed[i].CreatedAt.Before(feed[j].CreatedAt)
+	})
+
+	return feed, nil
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrAttachmentNotFound = newError(KindNotFound, "attachment_not_found", "attachment not found")
+	ErrAttachmentTooLarge = newError(KindTooLarge, "attachment_too_large", "attachment exceeds the maximum size")
+	ErrChecksumMismatch   = newError(KindValidation, "checksum_mismatch", "attachment content does not match its checksum")
+	ErrFilenameRequired   = newError(KindValidation, "filename_required", "attachment filename is required")
+)
+
+// MaxFilenameLength is the longest filename kept, in characters, as allowed
+// by its column.
+const MaxFilenameLength = 255
+
+// sniffLen is the number of bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+// byteCounter counts the bytes written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// @Summary Upload an attachment
+// @Description Stores a file attached to a task. The content type is sniffed from the content
+// @Description and the content is verified against the SHA-256 checksum when one is given.
+// @ID create-attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Attachment "Successfully uploaded attachment"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/attachments [post]
+func CreateAttachment(ctx context.Context, taskID, filename string, content io.Reader, checksum string) (models.Attachment, error) {
+	filename = filepath.Base(filepath.Clean("/" + filename))
+	if filename == "/" || filename == "." {
+		return models.Attachment{}, ErrFilenameRequired
+	}
+	// Long names keep their end, with the extension, cut between characters
+	if runes := []rune(filename); len(runes) > MaxFilenameLength {
+		filename = string(runes[len(runes)-MaxFilenameLength:])
+	}
+
+	err := checkTaskExists(config.DB, taskID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return models.Attachment{}, err
+	}
+	head = head[:n]
+
+	attachment := models.Attachment{
+		ID:          models.NewID(),
+		TaskID:      taskID,
+		Filename:    filename,
+		ContentType: http.DetectContentType(head),
+		UploadedBy:  auth.UserID(ctx),
+	}
+	attachment.StorageKey = taskID + "/" + attachment.ID
+
+	// Hash and count the content while it streams to the storage. Reading one
+	// byte past the limit tells an oversized upload from one of exactly the
+	// maximum size.
+	hash := sha256.New()
+	var size byteCounter
+	body := io.MultiReader(bytes.NewReader(head), content)
+	body = io.TeeReader(io.LimitReader(body, config.MaxAttachmentSize+1), io.MultiWriter(hash, &size))
+
+	err = config.Storage.Put(ctx, attachment.StorageKey, body, -1, attachment.ContentType)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment.Size = int64(size)
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	switch {
+	case attachment.Size > config.MaxAttachmentSize:
+		err = ErrAttachmentTooLarge
+	case checksum != "" && !strings.EqualFold(checksum, attachment.Checksum):
+		err = ErrChecksumMismatch
+	default:
+		err = insertAttachment(ctx, &attachment)
+	}
+	if err != nil {
+		deleteBlobs(ctx, []string{attachment.StorageKey})
+		return models.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// insertAttachment stores the metadata of an uploaded attachment and records
+// it in the audit log.
+func insertAttachment(ctx context.Context, attachment *models.Attachment) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(`
+		INSERT INTO attachments (id, task_id, filename, content_type, size, checksum, uploaded_by, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		attachment.ID, attachment.TaskID, attachment.Filename, attachment.ContentType, attachment.Size,
+		attachment.Checksum, attachment.UploadedBy, attachment.StorageKey)
+	err = row.Scan(&attachment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(ctx, tx, models.AuditCreate, models.AuditEntityAttachment, attachment.ID, attachment.TaskID, nil, attachment)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const attachmentColumns = "id, task_id, filename, content_type, size, checksum, uploaded_by, created_at, storage_key"
+
+func scanAttachment(row scanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.Filename, &attachment.ContentType, &attachment.Size,
+		&attachment.Checksum, &attachment.UploadedBy, &attachment.CreatedAt, &attachment.StorageKey)
+	return attachment, err
+}
+
+func getAttachments(q queryer, taskID string) ([]models.Attachment, error) {
+	rows, err := q.Query("SELECT "+attachmentColumns+" FROM attachments WHERE task_id = $1 ORDER BY created_at", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// @Summary Download an attachment
+// @Description Retrieves the metadata and content of an attachment
+// @ID download-attachment
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Success 200 {file} file "Attachment content"
+// @Failure 404 {object} string "Attachment not found"
+// @Router /attachments/{id} [get]
+func OpenAttachment(ctx context.Context, id string) (models.Attachment, io.ReadCloser, error) {
+	attachment, err := scanAttachment(config.DB.QueryRow("SELECT "+attachmentColumns+" FROM attachments WHERE id = $1", id))
+	if err != nil {
+		return models.Attachment{}, nil, notFound(err, ErrAttachmentNotFound)
+	}
+
+	content, err := config.Storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// @Summary Delete an attachment
+// @Description Removes an attachment and its content
+// @ID delete-attachment
+// @Produce json
+// @Param id path string true "Attachment ID"
+// @Success 200 {string} string "Successfully deleted attachment"
+// @Failure 500 {object} string "Internal server error"
+// @Router /attachments/{id} [delete]
+func DeleteAttachment(ctx context.Context, id string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	attachment, err := scanAttachment(tx.QueryRow("DELETE FROM attachments WHERE id = $1 RETURNING "+attachmentColumns, id))
+	if err != nil {
+		return notFound(err, ErrAttachmentNotFound)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditDelete, models.AuditEntityAttachment, id, attachment.TaskID, attachment, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	deleteBlobs(ctx, []string{attachment.StorageKey})
+	return nil
+}
+
+// deleteBlobs removes blobs from the storage once their metadata is gone.
+// Failures only leave unreferenced blobs behind, so they are logged.
+func deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		err := config.Storage.Delete(ctx, key)
+		if err != nil {
+			log.Printf("Error deleting attachment blob %s: %v", key, err)
+		}
+	}
+}
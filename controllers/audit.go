@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Number of audit entries returned by GetAuditLog
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+// auditedTask holds the task columns recorded in the audit log. Relations
+// such as reminders are audited on their own.
+type auditedTask struct {
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	Priority        string     `json:"priority"`
+	DueDateTime     time.Time  `json:"dueDateTime"`
+	UserID          string     `json:"userID"`
+	ProjectID       string     `json:"projectID"`
+	ParentID        string     `json:"parentID"`
+	Status          string     `json:"status"`
+	CompletedAt     *time.Time `json:"completedAt"`
+	EstimateSeconds int64      `json:"estimateSeconds"`
+	Recurrence      string     `json:"recurrence"`
+	NotifyMethod    string     `json:"notifyMethod"`
+	NotifyStatus    string     `json:"notifyStatus"`
+	NotifyMessage   string     `json:"notifyMessage"`
+}
+
+// auditedStatus holds the columns changed by completing, reopening or
+// blocking a task.
+type auditedStatus struct {
+	Status      string     `json:"status"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+// auditedDeletion holds the column changed by moving a task to the trash
+// or out of it.
+type auditedDeletion struct {
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+// statusChange is a task whose status changed.
+type statusChange struct {
+	taskID        string
+	before, after auditedStatus
+}
+
+func newAuditedTask(task models.Task) *auditedTask {
+	return &auditedTask{
+		Title:           task.Title,
+		Description:     task.Description,
+		Priority:        task.Priority,
+		DueDateTime:     task.DueDateTime.UTC(),
+		UserID:          task.UserID,
+		ProjectID:       task.ProjectID,
+		ParentID:        task.ParentID,
+		Status:          task.Status,
+		CompletedAt:     task.CompletedAt,
+		EstimateSeconds: task.EstimateSeconds,
+		Recurrence:      task.Recurrence,
+		NotifyMethod:    task.NotifyMethod,
+		NotifyStatus:    task.NotifyStatus,
+		NotifyMessage:   task.NotifyMessage,
+	}
+}
+
+// auditDiff encodes the before and after versions of an entity, either of
+// which may be nil. When both are given only the fields that differ are
+// kept, and changed is false if there are none.
+func auditDiff(before, after interface{}) (beforeJSON, afterJSON []byte, changed bool, err error) {
+	if before != nil {
+		beforeJSON, err = json.Marshal(before)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+	if after != nil {
+		afterJSON, err = json.Marshal(after)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+	if before == nil || after == nil {
+		return beforeJSON, afterJSON, true, nil
+	}
+
+	var beforeFields, afterFields map[string]json.RawMessage
+	err = json.Unmarshal(beforeJSON, &beforeFields)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = json.Unmarshal(afterJSON, &afterFields)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	for field, value := range beforeFields {
+		if bytes.Equal(value, afterFields[field]) {
+			delete(beforeFields, field)
+			delete(afterFields, field)
+		}
+	}
+	if len(beforeFields) == 0 && len(afterFields) == 0 {
+		return nil, nil, false, nil
+	}
+
+	beforeJSON, err = json.Marshal(beforeFields)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	afterJSON, err = json.Marshal(afterFields)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return beforeJSON, afterJSON, true, nil
+}
+
+// recordAudit appends an entry to the audit log, attributed to the user and
+// request of ctx. It must run in the transaction of the mutation so that
+// both are committed or rolled back together. Updates that change nothing
+// are not recorded.
+func recordAudit(ctx context.Context, q queryer, action, entityType, entityID, taskID string, before, after interface{}) error {
+	beforeJSON, afterJSON, changed, err := auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %v", err)
+	}
+	if !changed {
+		return nil
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO audit_log (actor_id, action, entity_type, entity_id, task_id, before_data, after_data, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		auth.UserID(ctx), action, entityType, entityID, taskID, nullJSON(beforeJSON), nullJSON(afterJSON), middleware.GetReqID(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+// nullJSON stores empty JSON as NULL.
+func nullJSON(data []byte) interface{} {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+// auditTask records a change of the columns of a task.
+func auditTask(ctx context.Context, q queryer, action string, before, after *models.Task) error {
+	var taskID string
+	var beforeFields, afterFields interface{}
+	if before != nil {
+		taskID = before.ID
+		beforeFields = newAuditedTask(*before)
+	}
+	if after != nil {
+		taskID = after.ID
+		afterFields = newAuditedTask(*after)
+	}
+	return recordAudit(ctx, q, action, models.AuditEntityTask, taskID, taskID, beforeFields, afterFields)
+}
+
+// auditReminders records the reminders of a task that were removed, added
+// or moved to another date between two versions of its reminder list.
+func auditReminders(ctx context.Context, q queryer, taskID string, before, after []models.Reminder) error {
+	remaining := make(map[string]models.Reminder, len(after))
+	for _, reminder := range after {
+		remaining[reminder.ID] = reminder
+	}
+
+	for _, old := range before {
+		old.TaskID = taskID
+		reminder, ok := remaining[old.ID]
+		if !ok {
+			err := recordAudit(ctx, q, models.AuditDelete, models.AuditEntityReminder, old.ID, taskID, old, nil)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		delete(remaining, old.ID)
+
+		reminder.TaskID = taskID
+		err := recordAudit(ctx, q, models.AuditUpdate, models.AuditEntityReminder, old.ID, taskID, old, reminder)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate over after rather than the map to keep the request order
+	for _, reminder := range after {
+		if _, ok := remaining[reminder.ID]; !ok {
+			continue
+		}
+		reminder.TaskID = taskID
+		err := recordAudit(ctx, q, models.AuditCreate, models.AuditEntityReminder, reminder.ID, taskID, nil, reminder)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// @Summary Query the audit log
+// @Description Lists the recorded mutations of the tasks of the calling user and of what belongs to them, most recent first
+// @ID get-audit-log
+// @Produce json
+// @Success 200 {array} models.AuditEntry "Audit entries"
+// @Failure 500 {object} string "Internal server error"
+// @Router /audit [get]
+func GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries of the tasks of the user, in the trash or not. Entries of
+	// tasks purged from the trash are no longer listed.
+	conds := []string{"task_id IN (SELECT id FROM tasks WHERE user_id = $1)"}
+	args := []interface{}{userID}
+	for _, cond := range []struct {
+		column string
+		value  interface{}
+		set    bool
+	}{
+		{"actor_id =", filter.ActorID, filter.ActorID != ""},
+		{"action =", filter.Action, filter.Action != ""},
+		{"entity_type =", filter.EntityType, filter.EntityType != ""},
+		{"entity_id =", filter.EntityID, filter.EntityID != ""},
+		{"task_id =", filter.TaskID, filter.TaskID != ""},
+		{"request_id =", filter.RequestID, filter.RequestID != ""},
+		{"created_at >=", filter.From.UTC(), !filter.From.IsZero()},
+		{"created_at <", filter.To.UTC(), !filter.To.IsZero()},
+		{"id <", filter.BeforeID, filter.BeforeID > 0},
+	} {
+		if cond.set {
+			args = append(args, cond.value)
+			conds = append(conds, cond.column+" $"+strconv.Itoa(len(args)))
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+	if limit > MaxAuditLimit {
+		limit = MaxAuditLimit
+	}
+	args = append(args, limit)
+
+	rows, err := config.DB.Query(`
+		SELECT id, actor_id, action, entity_type, entity_id, task_id, COALESCE(before_data, 'null'), COALESCE(after_data, 'null'), request_id, created_at
+		FROM audit_log`+where(conds)+`
+		ORDER BY id DESC LIMIT $`+strconv.Itoa(len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after []byte
+		err := rows.Scan(&entry.ID, &entry.ActorID, &entry.Action, &entry.EntityType, &entry.EntityID, &entry.TaskID,
+			&before, &after, &entry.RequestID, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// BatchResult is the outcome of an operation of a batch
+type BatchResult struct {
+	ID   string       // Task the operation applied to
+	Task *models.Task // Task after the operation, nil after a deletion
+	Err  error
+}
+
+// BatchError is returned when an operation of an atomic batch fails, and
+// none of them is applied.
+type BatchError struct {
+	Index int // Index of the failed operation
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// @Summary Apply a batch of operations to tasks
+// @Description Creates, updates, deletes and completes tasks. In atomic mode all operations are applied in a single
+// @Description transaction, in best effort mode each one is applied on its own and fails alone.
+// @ID batch-tasks
+// @Accept json
+// @Produce json
+// @Param batch body models.BatchRequest true "Operations"
+// @Success 200 {array} BatchResult "Result of each operation"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/batch [post]
+func ApplyBatch(ctx context.Context, batch models.BatchRequest) ([]BatchResult, error) {
+	if batch.Mode == "" {
+		batch.Mode = models.BatchModeAtomic
+	}
+	err := validateBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(batch.Operations))
+	if batch.Mode == models.BatchModeAtomic {
+		for i, op := range batch.Operations {
+			if op.TaskErr != nil {
+				return nil, &BatchError{Index: i, Err: operationError(i, op.TaskErr)}
+			}
+		}
+
+		tx, err := config.DB.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		for i, op := range batch.Operations {
+			results[i], err = applyOperation(ctx, tx, op)
+			if err != nil {
+				return nil, &BatchError{Index: i, Err: operationError(i, err)}
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i, op := range batch.Operations {
+			results[i] = applyOperationTx(ctx, op)
+			if results[i].Err != nil {
+				results[i].Err = operationError(i, results[i].Err)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// validateBatch checks the mode and the shape of the operations of a batch,
+// the tasks being validated as they are saved.
+func validateBatch(batch models.BatchRequest) error {
+	verr := &ValidationError{}
+
+	if batch.Mode != models.BatchModeAtomic && batch.Mode != models.BatchModeBestEffort {
+		verr.Add("mode", "must be %q or %q", models.BatchModeAtomic, models.BatchModeBestEffort)
+	}
+	switch {
+	case len(batch.Operations) == 0:
+		verr.Add("operations", "must not be empty")
+	case len(batch.Operations) > config.MaxBatchOperations:
+		verr.Add("operations", "must have at most %d operations", config.MaxBatchOperations)
+	}
+
+	for i, op := range batch.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		switch op.Op {
+		case models.BatchCreate:
+		case models.BatchUpdate, models.BatchDelete, models.BatchComplete:
+			if op.ID == "" {
+				verr.Add(field+".id", "is required")
+			}
+		default:
+			verr.Add(field+".op", "must be one of %q, %q, %q or %q", models.BatchCreate, models.BatchUpdate, models.BatchDelete, models.BatchComplete)
+			continue
+		}
+		if (op.Op == models.BatchCreate || op.Op == models.BatchUpdate) && op.Task == nil && op.TaskErr == nil {
+			verr.Add(field+".task", "is required")
+		}
+	}
+
+	return verr.Err()
+}
+
+// applyOperation applies an operation of a batch within tx. The task is read
+// back in tx, as it is right after the operation: a later operation of the
+// batch may delete it.
+func applyOperation(ctx context.Context, tx *sql.Tx, op models.BatchOperation) (BatchResult, error) {
+	id, err := applyOperationTo(ctx, tx, op)
+	if err != nil {
+		return BatchResult{ID: id}, err
+	}
+	result := BatchResult{ID: id}
+	if op.Op != models.BatchDelete {
+		task, err := getTask(tx, id, DefaultSubtaskDepth)
+		if err != nil {
+			return result, err
+		}
+		result.Task = &task
+	}
+	return result, nil
+}
+
+// applyOperationTo applies an operation of a batch within tx, and returns
+// the ID of the task it applied to.
+func applyOperationTo(ctx context.Context, tx *sql.Tx, op models.BatchOperation) (string, error) {
+	switch op.Op {
+	case models.BatchCreate:
+		task := *op.Task
+		task.UserID = auth.UserID(ctx)
+		return createTask(ctx, tx, task)
+	case models.BatchUpdate:
+		task := *op.Task
+		// Like for single updates, only the version of the operation is a
+		// precondition
+		task.Version = op.Version
+		return op.ID, updateTask(ctx, tx, op.ID, task)
+	case models.BatchDelete:
+		return op.ID, deleteTask(ctx, tx, op.ID, op.Version)
+	case models.BatchComplete:
+		return op.ID, completeTask(ctx, tx, op.ID, op.Cascade)
+	}
+	return "", fmt.Errorf("unknown batch operation %q", op.Op)
+}
+
+// applyOperationTx applies an operation of a batch in its own transaction.
+func applyOperationTx(ctx context.Context, op models.BatchOperation) BatchResult {
+	if op.TaskErr != nil {
+		return BatchResult{ID: op.ID, Err: op.TaskErr}
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return BatchResult{ID: op.ID, Err: err}
+	}
+	defer tx.Rollback()
+
+	result, err := applyOperation(ctx, tx, op)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		return BatchResult{ID: result.ID, Err: err}
+	}
+	return result
+}
+
+// operationError locates the invalid fields of the task of an operation
+// within the batch, e.g. operations[2].task.title, or operations[2].task
+// for the task as a whole.
+func operationError(index int, err error) error {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	located := &ValidationError{}
+	for _, field := range validationErr.Fields {
+		path := fmt.Sprintf("operations[%d].task", index)
+		if field.Field != "" {
+			path += "." + field.Field
+		}
+		located.Add(path, "%s", field.Message)
+	}
+	return located
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/ical"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var ErrCalendarFeedNotFound = newError(KindNotFound, "calendar_feed_not_found", "calendar feed not found")
+
+// CreateCalendarFeed creates the calendar feed of the calling user, with a
+// new secret token. A feed created before is replaced, so its URL stops
+// working.
+func CreateCalendarFeed(ctx context.Context) (models.CalendarFeed, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+	feed := models.CalendarFeed{UserID: userID, Token: base64.RawURLEncoding.EncodeToString(secret)}
+
+	err = config.DB.QueryRow(`
+		INSERT INTO calendar_feeds (user_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+		RETURNING created_at`,
+		userID, calendarTokenHash(feed.Token)).Scan(&feed.CreatedAt)
+	if err != nil {
+		return models.CalendarFeed{}, fmt.Errorf("failed to create calendar feed: %v", err)
+	}
+	return feed, nil
+}
+
+// DeleteCalendarFeed deletes the calendar feed of the calling user, so its
+// URL stops working.
+func DeleteCalendarFeed(ctx context.Context) error {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := config.DB.Exec("DELETE FROM calendar_feeds WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// GetCalendarFeed returns the iCalendar data of the feed with the given
+// token: the tasks of its user with a due date, as the components asked
+// for, with the dates in the time zone of the user.
+func GetCalendarFeed(token string, components []string) ([]byte, error) {
+	for i, name := range components {
+		components[i] = strings.ToUpper(strings.TrimSpace(name))
+		if components[i] != ical.Event && components[i] != ical.Todo {
+			return nil, fieldError("components", "must be VEVENT, VTODO or both")
+		}
+	}
+
+	var userID string
+	err := config.DB.QueryRow("SELECT user_id FROM calendar_feeds WHERE token_hash = $1", calendarTokenHash(token)).Scan(&userID)
+	if err != nil {
+		return nil, notFound(err, ErrCalendarFeedNotFound)
+	}
+
+	loc := time.UTC
+	user, err := GetUser(userID)
+	if err != nil && err != ErrUserNotFound {
+		return nil, err
+	}
+	if user.TimeZone != "" {
+		if userLoc, err := loadLocation(user.TimeZone); err == nil {
+			loc = userLoc
+		}
+	}
+
+	tasks, err := queryTasks(config.DB, "SELECT "+taskColumns+" FROM tasks WHERE user_id = $1 AND deleted_at IS NULL ORDER BY due_date_time, id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.Feed(tasks, ical.FeedOptions{
+		Name:            "Tasks",
+		Location:        loc,
+		Components:      components,
+		RefreshInterval: config.CalendarRefreshInterval,
+	})
+	var buf bytes.Buffer
+	err = cal.Encode(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// calendarTokenHash returns the hex encoded SHA-256 of a feed token, which
+// is what is stored.
+func calendarTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
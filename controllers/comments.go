@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// MaxCommentLength is the maximum length of a comment body, in characters.
+const MaxCommentLength = 10000
+
+var (
+	ErrCommentNotFound  = newError(KindNotFound, "comment_not_found", "comment not found")
+	ErrCommentBody      = newError(KindValidation, "invalid_comment_body", "comment body is required and must be at most 10000 characters")
+	ErrNotCommentAuthor = newError(KindForbidden, "not_comment_author", "only the author can change a comment")
+)
+
+// mentionPattern matches @username, but not e-mail addresses.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.-]+)`)
+
+// parseMentions returns the usernames mentioned in a comment body.
+func parseMentions(body string) []string {
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// A mention ending a sentence should not include the full stop
+		username := strings.TrimRight(match[1], ".")
+		if username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+	return uniqueStrings(usernames)
+}
+
+// saveMentions resolves the mentions of a comment and stores them. It
+// returns the users that were not mentioned before.
+func saveMentions(q queryer, commentID, body string) ([]models.User, error) {
+	users, err := getUsersByUsername(q, parseMentions(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var added []models.User
+	for _, user := range users {
+		res, err := q.Exec("INSERT INTO comment_mentions (comment_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", commentID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added = append(added, user)
+		}
+	}
+
+	return added, nil
+}
+
+func getMentions(q queryer, commentID string) ([]string, error) {
+	rows, err := q.Query("SELECT user_id FROM comment_mentions WHERE comment_id = $1 ORDER BY user_id", commentID)
+	if err != nil {
+		return nil, err
+	}
+	return scanIDs(rows)
+}
+
+const commentColumns = "id, task_id, author_id, body, created_at, updated_at"
+
+func scanComment(row scanner) (models.Comment, error) {
+	var comment models.Comment
+	var updatedAt sql.NullTime
+	err := row.Scan(&comment.ID, &comment.TaskID, &comment.AuthorID, &comment.Body, &comment.CreatedAt, &updatedAt)
+	if updatedAt.Valid {
+		comment.UpdatedAt = &updatedAt.Time
+	}
+	return comment, err
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return ErrCommentBody
+	}
+	return nil
+}
+
+// @Summary Create a new comment
+// @Description Posts a comment on a task and resolves its @mentions
+// @ID create-comment
+// @Accept json
+// @Produce json
+// @Param comment body models.Comment true "Comment details"
+// @Success 200 {object} models.Comment "Successfully created comment"
+// @Failure 500 {object} string "Internal server error"
+// @Router /comments [post]
+func CreateComment(ctx context.Context, comment models.Comment) (models.Comment, []models.User, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	err = validateCommentBody(comment.Body)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	defer tx.Rollback()
+
+	err = checkTaskExists(tx, comment.TaskID)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	row := tx.QueryRow("INSERT INTO comments (id, task_id, author_id, body) VALUES ($1, $2, $3, $4) RETURNING "+commentColumns,
+		models.NewID(), comment.TaskID, userID, comment.Body)
+	comment, err = scanComment(row)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	mentioned, err := saveMentions(tx, comment.ID, comment.Body)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	comment.Mentions, err = getMentions(tx, comment.ID)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	return comment, mentioned, tx.Commit()
+}
+
+// @Summary Get the comments of a task
+// @Description Retrieves the comments of a task, oldest first. Deleted comments are left out.
+// @ID get-comments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Comment "Successfully retrieved comments"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/comments [get]
+func GetComments(taskID string) ([]models.Comment, error) {
+	return getComments(config.DB, taskID)
+}
+
+func getComments(q queryer, taskID string) ([]models.Comment, error) {
+	rows, err := q.Query("SELECT "+commentColumns+" FROM comments WHERE task_id = $1 AND deleted_at IS NULL ORDER BY created_at", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range comments {
+		comments[i].Mentions, err = getMentions(q, comments[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return comments, nil
+}
+
+// getCommentForChange locks a comment that the caller is about to change,
+// checking that the caller wrote it.
+func getCommentForChange(ctx context.Context, q queryer, id string) (models.Comment, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	row := q.QueryRow("SELECT "+commentColumns+" FROM comments WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id)
+	comment, err := scanComment(row)
+	if err != nil {
+		return models.Comment{}, notFound(err, ErrCommentNotFound)
+	}
+	if comment.AuthorID != userID {
+		return models.Comment{}, ErrNotCommentAuthor
+	}
+	return comment, nil
+}
+
+// @Summary Update a comment
+// @Description Edits the body of a comment, keeping the previous version in its history
+// @ID update-comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param comment body models.Comment true "Updated comment"
+// @Success 200 {object} models.Comment "Successfully updated comment"
+// @Failure 500 {object} string "Internal server error"
+// @Router /comments/{id} [put]
+func UpdateComment(ctx context.Context, id, body string) (models.Comment, []models.User, error) {
+	err := validateCommentBody(body)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	defer tx.Rollback()
+
+	comment, err := getCommentForChange(ctx, tx, id)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	_, err = tx.Exec("INSERT INTO comment_revisions (id, comment_id, body, edited_by) VALUES ($1, $2, $3, $4)",
+		models.NewID(), id, comment.Body, auth.UserID(ctx))
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	row := tx.QueryRow("UPDATE comments SET body = $1, updated_at = NOW() WHERE id = $2 RETURNING "+commentColumns, body, id)
+	comment, err = scanComment(row)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	// Mentions removed from the body are dropped, new ones are notified
+	_, err = tx.Exec("DELETE FROM comment_mentions WHERE comment_id = $1 AND user_id NOT IN (SELECT id FROM users WHERE LOWER(username) = ANY($2))",
+		id, lowerArray(parseMentions(body)))
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	mentioned, err := saveMentions(tx, id, body)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+	comment.Mentions, err = getMentions(tx, id)
+	if err != nil {
+		return models.Comment{}, nil, err
+	}
+
+	return comment, mentioned, tx.Commit()
+}
+
+// @Summary Delete a comment
+// @Description Hides a comment from the task, its history is kept
+// @ID delete-comment
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {string} string "Successfully deleted comment"
+// @Failure 500 {object} string "Internal server error"
+// @Router /comments/{id} [delete]
+func DeleteComment(ctx context.Context, id string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = getCommentForChange(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE comments SET deleted_at = $1 WHERE id = $2", time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// @Summary Get the history of a comment
+// @Description Retrieves the previous versions of an edited comment, oldest first
+// @ID get-comment-revisions
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {array} models.CommentRevision "Successfully retrieved revisions"
+// @Failure 500 {object} string "Internal server error"
+// @Router /comments/{id}/revisions [get]
+func GetCommentRevisions(id string) ([]models.CommentRevision, error) {
+	var exists bool
+	err := config.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM comments WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCommentNotFound
+	}
+
+	rows, err := config.DB.Query("SELECT id, comment_id, body, edited_by, edited_at FROM comment_revisions WHERE comment_id = $1 ORDER BY edited_at", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.CommentRevision
+	for rows.Next() {
+		var revision models.CommentRevision
+		err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Body, &revision.EditedBy, &revision.EditedAt)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
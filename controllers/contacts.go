@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrContactNotFound = newError(KindNotFound, "contact_not_found", "contact not found")
+)
+
+// Longest values accepted for contact fields, as allowed by their columns
+const (
+	MaxContactNameLength  = 255
+	MaxContactEmailLength = 255
+	MaxContactPhoneLength = 50
+)
+
+const contactColumns = "id, user_id, name, email, phone, created_at"
+
+func scanContact(row scanner) (models.Contact, error) {
+	var contact models.Contact
+	err := row.Scan(&contact.ID, &contact.UserID, &contact.Name, &contact.Email, &contact.Phone, &contact.CreatedAt)
+	return contact, err
+}
+
+// validateContact checks the fields of a contact before it is saved. It
+// returns a *ValidationError listing every invalid field.
+func validateContact(contact *models.Contact) error {
+	verr := &ValidationError{}
+
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Email = strings.TrimSpace(contact.Email)
+	contact.Phone = strings.TrimSpace(contact.Phone)
+
+	switch {
+	case contact.Name == "":
+		verr.Add("name", "is required")
+	case utf8.RuneCountInString(contact.Name) > MaxContactNameLength:
+		verr.Add("name", "must be at most %d characters", MaxContactNameLength)
+	}
+	if contact.Email != "" {
+		if _, err := mail.ParseAddress(contact.Email); err != nil {
+			verr.Add("email", "must be an email address")
+		} else if utf8.RuneCountInString(contact.Email) > MaxContactEmailLength {
+			verr.Add("email", "must be at most %d characters", MaxContactEmailLength)
+		}
+	}
+	if utf8.RuneCountInString(contact.Phone) > MaxContactPhoneLength {
+		verr.Add("phone", "must be at most %d characters", MaxContactPhoneLength)
+	}
+
+	return verr.Err()
+}
+
+// checkContact reports an invalid contactID field unless the contact of a
+// task exists and belongs to the user. Tasks without a contact pass.
+func checkContact(q queryer, userID, contactID string) error {
+	if contactID == "" {
+		return nil
+	}
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM contacts WHERE id = $1 AND user_id = $2)", contactID, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fieldError("contactID", "does not exist")
+	}
+	return nil
+}
+
+// @Summary Create a new contact
+// @Description Adds a new contact to the database
+// @ID create-contact
+// @Accept json
+// @Produce json
+// @Param contact body models.Contact true "Contact details"
+// @Success 201 {object} models.Contact "Successfully created contact"
+// @Failure 500 {object} string "Internal server error"
+// @Router /contacts [post]
+func CreateContact(contact models.Contact) (models.Contact, error) {
+	err := validateContact(&contact)
+	if err != nil {
+		return models.Contact{}, err
+	}
+	contact.ID = models.NewID()
+
+	row := config.DB.QueryRow("INSERT INTO contacts (id, user_id, name, email, phone) VALUES ($1, $2, $3, $4, $5) RETURNING "+contactColumns,
+		contact.ID, contact.UserID, contact.Name, contact.Email, contact.Phone)
+	return scanContact(row)
+}
+
+// @Summary Get a contact by ID
+// @Description Retrieves a contact from the database by ID
+// @ID get-contact
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Success 200 {object} models.Contact "Successfully retrieved contact"
+// @Failure 404 {object} string "Contact not found"
+// @Router /contacts/{id} [get]
+func GetContact(ctx context.Context, id string) (models.Contact, error) {
+	contact, err := scanContact(config.DB.QueryRow("SELECT "+contactColumns+" FROM contacts WHERE id = $1 AND user_id = $2", id, auth.UserID(ctx)))
+	return contact, notFound(err, ErrContactNotFound)
+}
+
+// GetContactsByIDs returns the contacts of the user with the given IDs, keyed
+// by ID. Missing contacts and contacts of other users are left out.
+func GetContactsByIDs(ctx context.Context, ids []string) (map[string]models.Contact, error) {
+	list, err := queryContacts("SELECT "+contactColumns+" FROM contacts WHERE id = ANY($1) AND user_id = $2", pq.Array(ids), auth.UserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make(map[string]models.Contact, len(list))
+	for _, contact := range list {
+		contacts[contact.ID] = contact
+	}
+	return contacts, nil
+}
+
+// @Summary Get all contacts
+// @Description Retrieves the contacts of a user by name
+// @ID get-all-contacts
+// @Produce json
+// @Success 200 {array} models.Contact "Successfully retrieved contacts"
+// @Failure 500 {object} string "Internal server error"
+// @Router /contacts [get]
+func GetAllContacts(userID string) ([]models.Contact, error) {
+	return queryContacts("SELECT "+contactColumns+" FROM contacts WHERE user_id = $1 ORDER BY name, id", userID)
+}
+
+// GetContactsPage returns up to limit contacts of a user in order of
+// creation, after the contact with the given ID if any.
+func GetContactsPage(userID, after string, limit int) ([]models.Contact, error) {
+	return queryContacts("SELECT "+contactColumns+" FROM contacts WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3", userID, after, limit)
+}
+
+func queryContacts(query string, args ...interface{}) ([]models.Contact, error) {
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []models.Contact
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// @Summary Update a contact by ID
+// @Description Updates the name, email and phone of a contact
+// @ID update-contact
+// @Accept json
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Param contact body models.Contact true "Updated contact details"
+// @Success 200 {object} models.Contact "Successfully updated contact"
+// @Failure 500 {object} string "Internal server error"
+// @Router /contacts/{id} [put]
+func UpdateContact(ctx context.Context, id string, updatedContact models.Contact) (models.Contact, error) {
+	err := validateContact(&updatedContact)
+	if err != nil {
+		return models.Contact{}, err
+	}
+
+	row := config.DB.QueryRow("UPDATE contacts SET name = $1, email = $2, phone = $3 WHERE id = $4 AND user_id = $5 RETURNING "+contactColumns,
+		updatedContact.Name, updatedContact.Email, updatedContact.Phone, id, auth.UserID(ctx))
+	contact, err := scanContact(row)
+	return contact, notFound(err, ErrContactNotFound)
+}
+
+// @Summary Delete a contact by ID
+// @Description Removes a contact, its tasks are kept without a contact
+// @ID delete-contact
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Success 200 {string} string "Successfully deleted contact"
+// @Failure 500 {object} string "Internal server error"
+// @Router /contacts/{id} [delete]
+func DeleteContact(ctx context.Context, id string) error {
+	res, err := config.DB.Exec("DELETE FROM contacts WHERE id = $1 AND user_id = $2", id, auth.UserID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
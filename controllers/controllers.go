@@ -1,45 +1,129 @@
 package controllers
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/vikash-parashar/task-manager-2/auth"
 	"github.com/vikash-parashar/task-manager-2/config"
 	"github.com/vikash-parashar/task-manager-2/models"
 )
 
+var (
+	ErrTaskNotFound        = newError(KindNotFound, "task_not_found", "task not found")
+	ErrVersionMismatch     = newError(KindPrecondition, "version_mismatch", "the task has been changed since it was read")
+	ErrInvalidReminderDate = newError(KindValidation, "invalid_reminder_date", "reminder date must be in RFC3339 format")
+)
+
 // @Summary Create a new task
-// @Description Adds a new task to the database
+// @Description Adds a new task to the database. The IDs of the task and its reminders are generated.
 // @ID create-task
 // @Accept json
 // @Produce json
 // @Param task body models.Task true "Task details"
-// @Success 200 {string} string "Successfully created task"
+// @Success 201 {object} models.Task "Successfully created task"
 // @Failure 500 {object} string "Internal server error"
-// @Router /tasks/create [post]
-func CreateTask(task models.Task) error {
+// @Router /tasks [post]
+func CreateTask(ctx context.Context, task models.Task) (models.Task, error) {
 	tx, err := config.DB.Begin()
 	if err != nil {
-		return err
+		return models.Task{}, err
 	}
 	defer tx.Rollback()
 
+	id, err := createTask(ctx, tx, task)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(id, DefaultSubtaskDepth)
+}
+
+// createTask inserts a new task and its reminders with generated IDs, and
+// returns the ID of the task.
+func createTask(ctx context.Context, tx *sql.Tx, task models.Task) (string, error) {
+	task.ID = models.NewID()
+	for i := range task.Reminders {
+		task.Reminders[i].ID = models.NewID()
+	}
+
+	// Fill in the defaults of the project the task belongs to, which must be
+	// a project of the caller
+	if task.ProjectID != "" {
+		project, err := getProject(tx, auth.UserID(ctx), task.ProjectID)
+		if errors.Is(err, ErrProjectNotFound) {
+			return "", fieldError("projectID", "does not exist")
+		}
+		if err != nil {
+			return "", err
+		}
+		err = applyProjectDefaults(project, &task)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err := validateTask(&task)
+	if err != nil {
+		return "", err
+	}
+
+	if task.ParentID != "" {
+		err = checkParent(tx, task.ID, task.ParentID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = checkContact(tx, auth.UserID(ctx), task.ContactID)
+	if err != nil {
+		return "", err
+	}
+
 	// Insert task
-	_, err = tx.Exec("INSERT INTO tasks (id, title, description, priority, due_date_time) VALUES ($1, $2, $3, $4, $5)",
-		task.ID, task.Title, task.Description, task.Priority, task.DueDateTime)
+	_, err = tx.Exec(`INSERT INTO tasks (id, title, description, priority, due_date_time, user_id, project_id, contact_id, notify_method, notify_status, notify_message, parent_id, estimate_seconds, recurrence)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, NULLIF($12, ''), $13, $14)`,
+		task.ID, task.Title, task.Description, task.Priority, task.DueDateTime, task.UserID, task.ProjectID, task.ContactID,
+		task.NotifyMethod, task.NotifyStatus, task.NotifyMessage, task.ParentID, task.EstimateSeconds, task.Recurrence)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Insert reminders
 	for _, reminder := range task.Reminders {
 		_, err = tx.Exec("INSERT INTO reminders (id, date, task_id) VALUES ($1, $2, $3)", reminder.ID, reminder.Date, task.ID)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return tx.Commit()
+	err = recordActivity(tx, task.ID, auth.UserID(ctx), models.ActivityCreated, "", "", "")
+	if err != nil {
+		return "", err
+	}
+
+	created, err := scanTask(tx.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = $1", task.ID))
+	if err != nil {
+		return "", err
+	}
+	err = auditTask(ctx, tx, models.AuditCreate, nil, &created)
+	if err != nil {
+		return "", err
+	}
+	err = auditReminders(ctx, tx, task.ID, nil, task.Reminders)
+	if err != nil {
+		return "", err
+	}
+
+	return task.ID, nil
 }
 
 // @Summary Get a task by ID
@@ -47,39 +131,38 @@ func CreateTask(task models.Task) error {
 // @ID get-task
 // @Produce json
 // @Param id path string true "Task ID"
+// @Param depth query int false "Levels of subtasks to include"
 // @Success 200 {object} models.Task "Successfully retrieved task"
 // @Failure 404 {object} string "Task not found"
 // @Failure 500 {object} string "Internal server error"
-// @Router /tasks/get/{id} [get]
-func GetTask(id string) (models.Task, error) {
-	var task models.Task
+// @Router /tasks/{id} [get]
+func GetTask(id string, depth int) (models.Task, error) {
+	return getTask(config.DB, id, depth)
+}
 
-	row := config.DB.QueryRow("SELECT id, title, description, priority, due_date_time FROM tasks WHERE id = $1", id)
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Priority, &task.DueDateTime)
+// getTask reads a task with its relations and subtasks down to depth levels.
+func getTask(q queryer, id string, depth int) (models.Task, error) {
+	row := q.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL", id)
+	task, err := scanTask(row)
 	if err != nil {
-		return models.Task{}, err
+		return models.Task{}, notFound(err, ErrTaskNotFound)
 	}
 
-	rows, err := config.DB.Query("SELECT id, date FROM reminders WHERE task_id = $1", id)
+	err = loadTaskRelations(q, &task)
 	if err != nil {
 		return models.Task{}, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var reminder models.Reminder
-		err := rows.Scan(&reminder.ID, &reminder.Date)
-		if err != nil {
-			return models.Task{}, err
-		}
-		task.Reminders = append(task.Reminders, reminder)
+	err = loadSubtasks(q, &task, depth)
+	if err != nil {
+		return models.Task{}, err
 	}
 
 	return task, nil
 }
 
 // @Summary Update a task by ID
-// @Description Updates an existing task in the database
+// @Description Updates an existing task in the database. A non-zero Version must match the current one.
 // @ID update-task
 // @Accept json
 // @Produce json
@@ -87,121 +170,255 @@ func GetTask(id string) (models.Task, error) {
 // @Param task body models.Task true "Updated task details"
 // @Success 200 {string} string "Successfully updated task"
 // @Failure 500 {object} string "Internal server error"
-// @Router /tasks/update/{id} [put]
-func UpdateTask(id string, updatedTask models.Task) error {
+// @Router /tasks/{id} [put]
+func UpdateTask(ctx context.Context, id string, updatedTask models.Task) error {
 	tx, err := config.DB.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Update task
-	_, err = tx.Exec("UPDATE tasks SET title = $1, description = $2, priority = $3, due_date_time = $4 WHERE id = $5",
-		updatedTask.Title, updatedTask.Description, updatedTask.Priority, updatedTask.DueDateTime, id)
+	err = updateTask(ctx, tx, id, updatedTask)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateTask replaces a task, checking its version unless it is zero.
+func updateTask(ctx context.Context, tx *sql.Tx, id string, updatedTask models.Task) error {
+	before, err := lockTask(tx, id)
+	if err != nil {
+		return err
+	}
+	if updatedTask.Version != 0 && updatedTask.Version != before.Version {
+		return ErrVersionMismatch
+	}
+
+	return saveTask(ctx, tx, before, updatedTask)
+}
+
+// checkTaskExists returns ErrTaskNotFound unless the task exists out of
+// the trash.
+func checkTaskExists(q queryer, id string) error {
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
 	if err != nil {
 		return err
 	}
+	if !exists {
+		return ErrTaskNotFound
+	}
+	return nil
+}
 
-	// Delete existing reminders
-	_, err = tx.Exec("DELETE FROM reminders WHERE task_id = $1", id)
+// lockTask reads a task out of the trash with its reminders, and locks it
+// until the end of the transaction.
+func lockTask(tx *sql.Tx, id string) (models.Task, error) {
+	task, err := scanTask(tx.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id))
+	if err != nil {
+		return models.Task{}, notFound(err, ErrTaskNotFound)
+	}
+	task.Reminders, err = getReminders(tx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	return task, nil
+}
+
+// saveTask replaces the editable fields and the reminders of a task read by
+// lockTask, and records what changed.
+func saveTask(ctx context.Context, tx *sql.Tx, before, updatedTask models.Task) error {
+	id := before.ID
+	err := validateTask(&updatedTask)
 	if err != nil {
 		return err
 	}
 
-	// Insert updated reminders
-	for _, reminder := range updatedTask.Reminders {
-		_, err = tx.Exec("INSERT INTO reminders (id, date, task_id) VALUES ($1, $2, $3)", reminder.ID, reminder.Date, id)
+	if updatedTask.ParentID != "" {
+		err = checkParent(tx, id, updatedTask.ParentID)
 		if err != nil {
 			return err
 		}
 	}
 
-	return tx.Commit()
+	if updatedTask.ProjectID != "" && updatedTask.ProjectID != before.ProjectID {
+		err = checkProject(tx, auth.UserID(ctx), updatedTask.ProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if updatedTask.ContactID != before.ContactID {
+		err = checkContact(tx, auth.UserID(ctx), updatedTask.ContactID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update task
+	_, err = tx.Exec(`UPDATE tasks SET title = $1, description = $2, priority = $3, due_date_time = $4, project_id = NULLIF($5, ''),
+		contact_id = NULLIF($6, ''), notify_method = $7, notify_status = $8, notify_message = $9, parent_id = NULLIF($10, ''),
+		estimate_seconds = $11, recurrence = $12 WHERE id = $13`,
+		updatedTask.Title, updatedTask.Description, updatedTask.Priority, updatedTask.DueDateTime, updatedTask.ProjectID,
+		updatedTask.ContactID, updatedTask.NotifyMethod, updatedTask.NotifyStatus, updatedTask.NotifyMessage, updatedTask.ParentID,
+		updatedTask.EstimateSeconds, updatedTask.Recurrence, id)
+	if err != nil {
+		return err
+	}
+
+	// Apply the reminder changes, keeping the IDs of existing reminders
+	existing := make(map[string]models.Reminder, len(before.Reminders))
+	for _, reminder := range before.Reminders {
+		existing[reminder.ID] = reminder
+	}
+	for i, reminder := range updatedTask.Reminders {
+		old, ok := existing[reminder.ID]
+		switch {
+		case !ok:
+			// IDs of new reminders are always generated
+			reminder.ID = models.NewID()
+			updatedTask.Reminders[i].ID = reminder.ID
+			_, err = tx.Exec("INSERT INTO reminders (id, date, task_id) VALUES ($1, $2, $3)", reminder.ID, reminder.Date, id)
+		case old.Date != reminder.Date:
+			_, err = tx.Exec("UPDATE reminders SET date = $1 WHERE id = $2", reminder.Date, reminder.ID)
+		}
+		if err != nil {
+			return err
+		}
+		delete(existing, reminder.ID)
+	}
+	for reminderID := range existing {
+		_, err = tx.Exec("DELETE FROM reminders WHERE id = $1", reminderID)
+		if err != nil {
+			return err
+		}
+	}
+
+	updatedTask.ID = id
+	err = recordTaskChanges(tx, auth.UserID(ctx), before, updatedTask)
+	if err != nil {
+		return err
+	}
+
+	after, err := scanTask(tx.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = $1", id))
+	if err != nil {
+		return err
+	}
+	err = auditTask(ctx, tx, models.AuditUpdate, &before, &after)
+	if err != nil {
+		return err
+	}
+	return auditReminders(ctx, tx, id, before.Reminders, updatedTask.Reminders)
 }
 
 // @Summary Delete a task by ID
-// @Description Removes a task from the database by ID
+// @Description Moves a task and its subtasks to the trash. A non-zero version must match the current one.
 // @ID delete-task
 // @Produce json
 // @Param id path string true "Task ID"
 // @Success 200 {string} string "Successfully deleted task"
+// @Failure 404 {object} string "Task not found"
 // @Failure 500 {object} string "Internal server error"
-// @Router /tasks/delete/{id} [delete]
-func DeleteTask(id string) error {
+// @Router /tasks/{id} [delete]
+func DeleteTask(ctx context.Context, id string, version int64) error {
 	tx, err := config.DB.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
+	defer tx.Rollback()
 
-	// Defer a function to handle transaction finalization
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback() // Ignore error; panicking
-			panic(p)          // Re-panic
-		} else if err != nil {
-			_ = tx.Rollback() // Ignore error; already set
-		} else {
-			err = tx.Commit() // If commit is successful, set the error to nil
-		}
-	}()
-
-	// Delete associated reminders
-	_, err = tx.Exec("DELETE FROM reminders WHERE task_id = $1", id)
+	err = deleteTask(ctx, tx, id, version)
 	if err != nil {
-		return fmt.Errorf("failed to delete associated reminders: %v", err)
+		return err
 	}
 
-	// Delete task
-	_, err = tx.Exec("DELETE FROM tasks WHERE id = $1", id)
+	err = tx.Commit()
 	if err != nil {
-		return fmt.Errorf("failed to delete task: %v", err)
+		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
 
 	return nil
 }
 
-// @Summary Get all tasks
-// @Description Retrieves a list of all tasks from the database
-// @ID get-all-tasks
-// @Produce json
-// @Success 200 {array} models.Task "Successfully retrieved tasks"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/getAll [get]
-func GetAllTasks() ([]models.Task, error) {
-	var tasks []models.Task
-
-	rows, err := config.DB.Query("SELECT id, title, description, priority, due_date_time FROM tasks")
+// deleteTask moves a task and its subtasks to the trash, checking its
+// version unless it is zero.
+func deleteTask(ctx context.Context, tx *sql.Tx, id string, version int64) error {
+	var current int64
+	err := tx.QueryRow("SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&current)
 	if err != nil {
-		return nil, err
+		return notFound(err, ErrTaskNotFound)
+	}
+	if version != 0 && version != current {
+		return ErrVersionMismatch
 	}
-	defer rows.Close()
 
+	// Subtasks go to the trash with their parent, at the same time so they
+	// can be restored together. The time is in UTC, like the retention
+	// deadline of the trash.
+	rows, err := tx.Query("UPDATE tasks SET deleted_at = "+nowUTC+" WHERE id IN ("+subtreeQuery+") AND deleted_at IS NULL RETURNING id, deleted_at", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %v", err)
+	}
+	var deleted []string
+	var deletedAt time.Time
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Priority, &task.DueDateTime)
+		var taskID string
+		err := rows.Scan(&taskID, &deletedAt)
 		if err != nil {
-			return nil, err
+			rows.Close()
+			return fmt.Errorf("failed to delete task: %v", err)
 		}
-
-		remindersRows, err := config.DB.Query("SELECT id, date FROM reminders WHERE task_id = $1", task.ID)
+		deleted = append(deleted, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to delete task: %v", err)
+	}
+	for _, taskID := range deleted {
+		err = recordActivity(tx, taskID, auth.UserID(ctx), models.ActivityDeleted, "", "", "")
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to record activity: %v", err)
 		}
-		defer remindersRows.Close()
-
-		for remindersRows.Next() {
-			var reminder models.Reminder
-			err := remindersRows.Scan(&reminder.ID, &reminder.Date)
-			if err != nil {
-				return nil, err
-			}
-			task.Reminders = append(task.Reminders, reminder)
+		err = recordAudit(ctx, tx, models.AuditDelete, models.AuditEntityTask, taskID, taskID,
+			auditedDeletion{}, auditedDeletion{DeletedAt: &deletedAt})
+		if err != nil {
+			return err
 		}
+	}
 
-		tasks = append(tasks, task)
+	// Tasks in the trash no longer block the tasks waiting on them
+	err = refreshDependents(ctx, tx, deleted)
+	if err != nil {
+		return fmt.Errorf("failed to unblock dependent tasks: %v", err)
 	}
 
-	return tasks, nil
+	return nil
+}
+
+// GetTaskVersion returns the current version of a task, to check
+// preconditions without loading the whole task.
+func GetTaskVersion(id string) (int64, error) {
+	var version int64
+	err := config.DB.QueryRow("SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL", id).Scan(&version)
+	return version, notFound(err, ErrTaskNotFound)
+}
+
+// @Summary Get all tasks
+// @Description Retrieves a list of all tasks from the database
+// @ID get-all-tasks
+// @Produce json
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Success 200 {array} models.Task "Successfully retrieved tasks"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks [get]
+func GetAllTasks(filter models.TaskFilter) ([]models.Task, error) {
+	conds, args := taskFilterConds(filter, nil)
+	return queryTasks(config.DB, "SELECT "+taskColumns+" FROM tasks"+where(conds), args...)
 }
 
 // @Summary Get tasks with due reminders
@@ -209,44 +426,15 @@ func GetAllTasks() ([]models.Task, error) {
 // @ID get-tasks-with-due-reminders
 // @Produce json
 // @Param currentTime query string true "Current time in RFC3339 format"
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
 // @Success 200 {array} models.Task "Successfully retrieved tasks with due reminders"
 // @Failure 500 {object} string "Internal server error"
 // @Router /tasks/dueReminders [get]
-func GetTasksWithDueReminders(currentTime time.Time) ([]models.Task, error) {
-
-	rows, err := config.DB.Query("SELECT id, title, description, priority, due_date_time FROM tasks WHERE due_date_time <= $1", currentTime)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var tasks []models.Task
-
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Priority, &task.DueDateTime)
-		if err != nil {
-			return nil, err
-		}
-
-		// Query reminders for the task
-		remindersRows, err := config.DB.Query("SELECT id, date FROM reminders WHERE task_id = $1", task.ID)
-		if err != nil {
-			return nil, err
-		}
-		defer remindersRows.Close()
-
-		for remindersRows.Next() {
-			var reminder models.Reminder
-			err := remindersRows.Scan(&reminder.ID, &reminder.Date)
-			if err != nil {
-				return nil, err
-			}
-			task.Reminders = append(task.Reminders, reminder)
-		}
-
-		tasks = append(tasks, task)
-	}
-
-	return tasks, nil
+func GetTasksWithDueReminders(currentTime time.Time, filter models.TaskFilter) ([]models.Task, error) {
+	conds, args := taskFilterConds(filter, []interface{}{currentTime})
+	conds = append([]string{"due_date_time <= $1"}, conds...)
+	return queryTasks(config.DB, "SELECT "+taskColumns+" FROM tasks"+where(conds), args...)
 }
@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrDependencyNotFound = newError(KindNotFound, "dependency_not_found", "dependency not found")
+	ErrDependencyCycle    = newError(KindConflict, "dependency_cycle", "the dependency would create a cycle")
+	ErrTaskBlocked        = newError(KindConflict, "task_blocked", "the task is blocked by incomplete dependencies")
+)
+
+// @Summary Add a dependency
+// @Description Declares that a task is blocked by another one
+// @ID add-dependency
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the blocked task"
+// @Success 200 {object} models.Task "Task with its updated status"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/dependencies [post]
+func AddDependency(ctx context.Context, taskID, blockedByID string) (models.Task, error) {
+	if taskID == blockedByID {
+		return models.Task{}, ErrDependencyCycle
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	// Serialize dependency changes so two concurrent inserts cannot close a
+	// cycle that neither of them sees on its own
+	_, err = tx.Exec("LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE")
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var found int
+	err = tx.QueryRow("SELECT COUNT(*) FROM tasks WHERE id IN ($1, $2) AND deleted_at IS NULL", taskID, blockedByID).Scan(&found)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if found != 2 {
+		return models.Task{}, ErrTaskNotFound
+	}
+
+	// The edge closes a cycle if the blocking task already waits on the
+	// blocked one, directly or through other tasks
+	var cycle bool
+	err = tx.QueryRow(`
+		WITH RECURSIVE blockers AS (
+			SELECT blocked_by_id AS id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT task_dependencies.blocked_by_id FROM task_dependencies JOIN blockers ON task_dependencies.task_id = blockers.id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE id = $2)`, blockedByID, taskID).Scan(&cycle)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if cycle {
+		return models.Task{}, ErrDependencyCycle
+	}
+
+	_, err = tx.Exec("INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", taskID, blockedByID)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = refreshBlockedStatus(ctx, tx, []string{taskID})
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(taskID, DefaultSubtaskDepth)
+}
+
+// @Summary Remove a dependency
+// @Description Removes a dependency between two tasks
+// @ID remove-dependency
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the blocked task"
+// @Success 200 {object} models.Task "Task with its updated status"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/dependencies [delete]
+func RemoveDependency(ctx context.Context, taskID, blockedByID string) (models.Task, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2", taskID, blockedByID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.Task{}, ErrDependencyNotFound
+	}
+
+	err = refreshBlockedStatus(ctx, tx, []string{taskID})
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(taskID, DefaultSubtaskDepth)
+}
+
+// refreshBlockedStatus sets the given tasks to blocked while any of their
+// dependencies is incomplete, and back to open otherwise, recording the
+// changes in the audit log. Completed tasks are left alone, and tasks in the
+// trash no longer block anything.
+func refreshBlockedStatus(ctx context.Context, q queryer, taskIDs []string) error {
+	rows, err := q.Query(`
+		UPDATE tasks SET status = refreshed.status
+		FROM (
+			SELECT id, status AS old_status, CASE
+				WHEN EXISTS (
+					SELECT 1 FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocked_by_id
+					WHERE task_dependencies.task_id = candidates.id AND blockers.status <> $2 AND blockers.deleted_at IS NULL
+				) THEN $3 ELSE $4 END AS status
+			FROM tasks AS candidates
+			WHERE id = ANY($1) AND status <> $2
+		) AS refreshed
+		WHERE tasks.id = refreshed.id AND refreshed.status <> refreshed.old_status
+		RETURNING tasks.id, refreshed.old_status, refreshed.status`,
+		pq.Array(taskIDs), models.TaskStatusCompleted, models.TaskStatusBlocked, models.TaskStatusOpen)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var changes []statusChange
+	for rows.Next() {
+		var change statusChange
+		err := rows.Scan(&change.taskID, &change.before.Status, &change.after.Status)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, change := range changes {
+		err := recordAudit(ctx, q, models.AuditUpdate, models.AuditEntityTask, change.taskID, change.taskID, change.before, change.after)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refreshDependents refreshes the status of every task blocked by one of
+// the given tasks.
+func refreshDependents(ctx context.Context, q queryer, blockerIDs []string) error {
+	rows, err := q.Query("SELECT DISTINCT task_id FROM task_dependencies WHERE blocked_by_id = ANY($1)", pq.Array(blockerIDs))
+	if err != nil {
+		return err
+	}
+	dependents, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+
+	if len(dependents) == 0 {
+		return nil
+	}
+	return refreshBlockedStatus(ctx, q, dependents)
+}
+
+func getBlockedBy(q queryer, taskID string) ([]string, error) {
+	rows, err := q.Query(`
+		SELECT blocked_by_id FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.blocked_by_id
+		WHERE task_id = $1 AND tasks.deleted_at IS NULL ORDER BY blocked_by_id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return scanIDs(rows)
+}
+
+// @Summary Get the next tasks
+// @Description Lists incomplete tasks in dependency order, so every task comes after the tasks blocking it
+// @ID get-next-tasks
+// @Produce json
+// @Param readyOnly query bool false "Only list tasks that are not blocked"
+// @Success 200 {array} models.Task "Tasks in dependency order"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/next [get]
+func GetNextTasks(filter models.TaskFilter, readyOnly bool) ([]models.Task, error) {
+	conds, args := taskFilterConds(filter, []interface{}{models.TaskStatusCompleted})
+	conds = append([]string{"status <> $1"}, conds...)
+	tasks, err := queryTasks(config.DB, "SELECT "+taskColumns+" FROM tasks"+where(conds), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := orderByDependencies(tasks)
+	if !readyOnly {
+		return ordered, nil
+	}
+
+	var ready []models.Task
+	for _, task := range ordered {
+		if task.Status == models.TaskStatusOpen {
+			ready = append(ready, task)
+		}
+	}
+	return ready, nil
+}
+
+// orderByDependencies sorts tasks topologically so that each task comes
+// after the tasks in the list blocking it. Tasks that are free to start are
+// picked by earliest due date first.
+func orderByDependencies(tasks []models.Task) []models.Task {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+
+	// Count, for each task, the blockers that are part of the list
+	pending := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, task := range tasks {
+		for _, blockerID := range task.BlockedBy {
+			if j, ok := index[blockerID]; ok {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	before := func(a, b int) bool {
+		if !tasks[a].DueDateTime.Equal(tasks[b].DueDateTime) {
+			return tasks[a].DueDateTime.Before(tasks[b].DueDateTime)
+		}
+		return tasks[a].ID < tasks[b].ID
+	}
+
+	var ready []int
+	for i := range tasks {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]models.Task, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool { return before(ready[a], ready[b]) })
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, tasks[next])
+
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	// Tasks caught in a cycle never become ready, list them last
+	for i := range tasks {
+		if pending[i] > 0 {
+			ordered = append(ordered, tasks[i])
+		}
+	}
+
+	return ordered
+}
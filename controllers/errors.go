@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/storage"
+)
+
+// ErrorKind classifies the errors returned by the controllers
+type ErrorKind int
+
+// Error kinds
+const (
+	KindInternal     ErrorKind = iota
+	KindNotFound               // The entity does not exist
+	KindConflict               // The request conflicts with the current state
+	KindValidation             // The input is invalid
+	KindUnauthorized           // The request does not identify the user
+	KindForbidden              // The user may not perform the action
+	KindPrecondition           // A precondition of the request does not hold
+	KindTooLarge               // The input exceeds a size limit
+	KindUnsupported            // The input is in an unsupported format
+)
+
+// Error is an error returned by the controllers, with its kind and a
+// stable code clients can rely on.
+type Error struct {
+	Kind    ErrorKind
+	Code    string // e.g. task_not_found
+	Message string
+}
+
+func newError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is lets not found errors match sql.ErrNoRows, so callers checking for a
+// missing row also catch them.
+func (e *Error) Is(target error) bool {
+	return e.Kind == KindNotFound && target == sql.ErrNoRows
+}
+
+var (
+	ErrNotFound     = newError(KindNotFound, "not_found", "not found")
+	ErrUnauthorized = newError(KindUnauthorized, "unauthorized", "the request must identify the user with the X-User-ID header")
+)
+
+// notFound replaces sql.ErrNoRows with the not found error of an entity.
+func notFound(err error, entityErr *Error) error {
+	if err == sql.ErrNoRows {
+		return entityErr
+	}
+	return err
+}
+
+// requireUser returns the ID of the calling user, or ErrUnauthorized for
+// anonymous requests.
+func requireUser(ctx context.Context) (string, error) {
+	userID := auth.UserID(ctx)
+	if userID == "" {
+		return "", ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// Classify returns the controller error behind err. Missing rows and
+// objects are reported as ErrNotFound, invalid fields as a validation
+// error, and other errors as nil.
+func Classify(err error) *Error {
+	var domainErr *Error
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &domainErr):
+		return domainErr
+	case errors.As(err, &validationErr):
+		return newError(KindValidation, "invalid_fields", validationErr.Error())
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, storage.ErrNotFound):
+		return ErrNotFound
+	}
+	return nil
+}
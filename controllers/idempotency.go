@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// MaxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrInvalidIdempotencyKey    = newError(KindValidation, "invalid_idempotency_key", fmt.Sprintf("the Idempotency-Key must have 1 to %d characters", MaxIdempotencyKeyLength))
+	ErrIdempotencyKeyReused     = newError(KindConflict, "idempotency_key_reused", "the Idempotency-Key was already used for a different request")
+	ErrIdempotencyKeyInProgress = newError(KindConflict, "idempotency_key_in_progress", "a request with this Idempotency-Key is still in progress")
+)
+
+// claimAttempts is how many times BeginIdempotentRequest tries to claim a
+// key released by failed requests in the meantime.
+const claimAttempts = 3
+
+// BeginIdempotentRequest claims an Idempotency-Key of the calling user for
+// the request with the given fingerprint. It returns nil if the request
+// should run, or the stored response if it already ran. Keys older than
+// config.IdempotencyKeyTTL are expired and claimed again, like keys
+// released by a failed request.
+func BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*models.IdempotentResponse, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	userID := auth.UserID(ctx)
+
+	for attempt := 0; attempt < claimAttempts; attempt++ {
+		claimed, err := claimIdempotencyKey(userID, key, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		stored, err := getIdempotencyKey(userID, key)
+		if err == sql.ErrNoRows {
+			// Released by a failed request in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %v", err)
+		}
+
+		if stored.Fingerprint != fingerprint {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if stored.Response == nil {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return stored.Response, nil
+	}
+	return nil, ErrIdempotencyKeyInProgress
+}
+
+// claimIdempotencyKey stores a key for the request with the given
+// fingerprint, unless the key is already stored and not expired. Times are
+// in UTC.
+func claimIdempotencyKey(userID, key, fingerprint string) (bool, error) {
+	res, err := config.DB.Exec(`
+		INSERT INTO idempotency_keys (user_id, key, fingerprint) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, key) DO UPDATE
+			SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, headers = NULL, body = NULL, created_at = `+nowUTC+`
+			WHERE idempotency_keys.created_at < $4`,
+		userID, key, fingerprint, time.Now().UTC().Add(-config.IdempotencyKeyTTL))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %v", err)
+	}
+	n, err := res.RowsAffected()
+	return err == nil && n == 1, nil
+}
+
+// CompleteIdempotentRequest stores the response to the request that claimed
+// an Idempotency-Key, to be replayed on retries.
+func CompleteIdempotentRequest(ctx context.Context, key, fingerprint string, response models.IdempotentResponse) error {
+	headers, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+
+	_, err = config.DB.Exec(`
+		UPDATE idempotency_keys SET status_code = $1, headers = $2, body = $3
+		WHERE user_id = $4 AND key = $5 AND fingerprint = $6 AND status_code IS NULL`,
+		response.Status, headers, response.Body, auth.UserID(ctx), key, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %v", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey gives up a claimed Idempotency-Key without storing
+// a response, so that a retry runs the request again.
+func ReleaseIdempotencyKey(ctx context.Context, key, fingerprint string) error {
+	_, err := config.DB.Exec("DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND fingerprint = $3 AND status_code IS NULL",
+		auth.UserID(ctx), key, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %v", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes the Idempotency-Keys created before the given
+// time, and returns how many were deleted.
+func PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	res, err := config.DB.Exec("DELETE FROM idempotency_keys WHERE created_at < $1", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %v", err)
+	}
+	return res.RowsAffected()
+}
+
+func getIdempotencyKey(userID, key string) (models.IdempotencyKey, error) {
+	stored := models.IdempotencyKey{UserID: userID, Key: key}
+	var status sql.NullInt64
+	var headers, body []byte
+	err := config.DB.QueryRow("SELECT fingerprint, status_code, headers, body, created_at FROM idempotency_keys WHERE user_id = $1 AND key = $2",
+		userID, key).Scan(&stored.Fingerprint, &status, &headers, &body, &stored.CreatedAt)
+	if err != nil {
+		return models.IdempotencyKey{}, err
+	}
+
+	if status.Valid {
+		stored.Response = &models.IdempotentResponse{Status: int(status.Int64), Body: body}
+		if len(headers) > 0 {
+			err = json.Unmarshal(headers, &stored.Response.Header)
+			if err != nil {
+				return models.IdempotencyKey{}, err
+			}
+		}
+	}
+	return stored, nil
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// The functions below load the relations of many tasks at once, for the
+// GraphQL dataloaders. Tasks are returned without their relations.
+
+// GetTasksPage returns up to limit tasks out of the trash matching filter,
+// in order of creation, after the task with the given ID if any.
+func GetTasksPage(filter models.TaskFilter, after string, limit int) ([]models.Task, error) {
+	conds, args := taskFilterConds(filter, nil)
+	if after != "" {
+		args = append(args, after)
+		conds = append(conds, fmt.Sprintf("id > $%d", len(args)))
+	}
+	args = append(args, limit)
+	return scanTasks(config.DB, "SELECT "+taskColumns+" FROM tasks"+where(conds)+fmt.Sprintf(" ORDER BY id LIMIT $%d", len(args)), args...)
+}
+
+// GetTasksByIDs returns the tasks out of the trash with the given IDs,
+// keyed by ID.
+func GetTasksByIDs(ids []string) (map[string]models.Task, error) {
+	list, err := scanTasks(config.DB, "SELECT "+taskColumns+" FROM tasks WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[string]models.Task, len(list))
+	for _, task := range list {
+		tasks[task.ID] = task
+	}
+	return tasks, nil
+}
+
+// GetRemindersByTaskIDs returns the reminders of the given tasks by date,
+// keyed by task ID.
+func GetRemindersByTaskIDs(taskIDs []string) (map[string][]models.Reminder, error) {
+	rows, err := config.DB.Query("SELECT id, date, task_id FROM reminders WHERE task_id = ANY($1) ORDER BY date, id", pq.Array(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := make(map[string][]models.Reminder, len(taskIDs))
+	for rows.Next() {
+		var reminder models.Reminder
+		err := rows.Scan(&reminder.ID, &reminder.Date, &reminder.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		reminders[reminder.TaskID] = append(reminders[reminder.TaskID], reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+// GetTagsByTaskIDs returns the tags of the given tasks by name, keyed by
+// task ID.
+func GetTagsByTaskIDs(taskIDs []string) (map[string][]models.Tag, error) {
+	rows, err := config.DB.Query(`
+		SELECT task_tags.task_id, tags.id, tags.name, tags.color, tags.user_id, tags.workspace_id
+		FROM tags JOIN task_tags ON task_tags.tag_id = tags.id
+		WHERE task_tags.task_id = ANY($1)
+		ORDER BY LOWER(tags.name)`, pq.Array(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]models.Tag, len(taskIDs))
+	for rows.Next() {
+		var taskID string
+		var tag models.Tag
+		err := rows.Scan(&taskID, &tag.ID, &tag.Name, &tag.Color, &tag.UserID, &tag.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetCommentsByTaskIDs returns the comments of the given tasks with their
+// mentions, oldest first, keyed by task ID.
+func GetCommentsByTaskIDs(taskIDs []string) (map[string][]models.Comment, error) {
+	rows, err := config.DB.Query("SELECT "+commentColumns+" FROM comments WHERE task_id = ANY($1) AND deleted_at IS NULL ORDER BY created_at, id",
+		pq.Array(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Comment
+	var commentIDs []string
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, comment)
+		commentIDs = append(commentIDs, comment.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	mentions := make(map[string][]string, len(list))
+	rows, err = config.DB.Query("SELECT comment_id, user_id FROM comment_mentions WHERE comment_id = ANY($1) ORDER BY user_id", pq.Array(commentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var commentID, userID string
+		err := rows.Scan(&commentID, &userID)
+		if err != nil {
+			return nil, err
+		}
+		mentions[commentID] = append(mentions[commentID], userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string][]models.Comment, len(taskIDs))
+	for _, comment := range list {
+		comment.Mentions = mentions[comment.ID]
+		comments[comment.TaskID] = append(comments[comment.TaskID], comment)
+	}
+	return comments, nil
+}
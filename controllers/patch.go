@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Patch formats accepted by PatchTask, as media types
+const (
+	MergePatch = "application/merge-patch+json" // RFC 7396
+	JSONPatch  = "application/json-patch+json"  // RFC 6902
+)
+
+var (
+	ErrPatchFormat   = newError(KindUnsupported, "unsupported_patch_format", "patch must be a JSON merge patch or a JSON patch")
+	ErrInvalidPatch  = newError(KindValidation, "invalid_patch", "invalid patch")
+	ErrPatchTest     = newError(KindConflict, "patch_test_failed", "a test operation of the patch failed")
+	ErrReadOnlyField = newError(KindValidation, "read_only_field", "field cannot be changed by a patch")
+)
+
+// patchableFields are the JSON fields of a task that a patch can change.
+// Reminders are patched as a list, by index.
+var patchableFields = map[string]bool{
+	"title":           true,
+	"description":     true,
+	"priority":        true,
+	"dueDateTime":     true,
+	"projectID":       true,
+	"contactID":       true,
+	"parentID":        true,
+	"notifyMethod":    true,
+	"notifyStatus":    true,
+	"notifyMessage":   true,
+	"estimateSeconds": true,
+	"recurrence":      true,
+	"reminders":       true,
+}
+
+// @Summary Patch a task by ID
+// @Description Applies a JSON merge patch or a JSON patch to a task, atomically. A non-zero version must match the current one.
+// @ID patch-task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task "Patched task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id} [patch]
+func PatchTask(ctx context.Context, id, format string, patch []byte, version int64) (models.Task, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockTask(tx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if version != 0 && version != before.Version {
+		return models.Task{}, ErrVersionMismatch
+	}
+
+	// The patch applies to the task as GetTask returns it, with reminders
+	// in the same order
+	doc, err := json.Marshal(before)
+	if err != nil {
+		return models.Task{}, err
+	}
+	patched, err := applyPatch(format, doc, patch)
+	if err != nil {
+		return models.Task{}, err
+	}
+	err = checkPatchedFields(doc, patched)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var updatedTask models.Task
+	err = json.Unmarshal(patched, &updatedTask)
+	if err != nil {
+		return models.Task{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	err = saveTask(ctx, tx, before, updatedTask)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(id, DefaultSubtaskDepth)
+}
+
+// applyPatch applies a patch in the given format to a JSON document.
+func applyPatch(format string, doc, patch []byte) ([]byte, error) {
+	var patched []byte
+	var err error
+	switch format {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatch:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = operations.Apply(doc)
+		}
+	default:
+		return nil, ErrPatchFormat
+	}
+
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return nil, ErrPatchTest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return patched, nil
+}
+
+// checkPatchedFields verifies that a patch only changed patchableFields.
+func checkPatchedFields(doc, patched []byte) error {
+	var before, after map[string]interface{}
+	err := json.Unmarshal(doc, &before)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(patched, &after)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	for field, value := range after {
+		if !patchableFields[field] && !reflect.DeepEqual(value, before[field]) {
+			return fmt.Errorf("%w: %s", ErrReadOnlyField, field)
+		}
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok && !patchableFields[field] {
+			return fmt.Errorf("%w: %s", ErrReadOnlyField, field)
+		}
+	}
+	return nil
+}
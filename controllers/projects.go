@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrProjectNameRequired = newError(KindValidation, "project_name_required", "project name is required")
+	ErrProjectNotFound     = newError(KindNotFound, "project_not_found", "project not found")
+	ErrInvalidOffset       = newError(KindValidation, "invalid_reminder_offset", "reminder offsets must be positive durations such as 15m or 1h")
+)
+
+const projectColumns = "id, name, description, user_id, archived, position, created_at, " +
+	"default_priority, default_notify_method, default_reminder_offsets"
+
+func scanProject(row scanner) (models.Project, error) {
+	var project models.Project
+	err := row.Scan(&project.ID, &project.Name, &project.Description, &project.UserID, &project.Archived, &project.Position,
+		&project.CreatedAt, &project.DefaultPriority, &project.DefaultNotifyMethod, pq.Array(&project.DefaultReminderOffsets))
+	return project, err
+}
+
+func validateProject(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ErrProjectNameRequired
+	}
+	// Tasks created in the project get the defaults, which must pass the
+	// validation of tasks
+	if utf8.RuneCountInString(project.DefaultPriority) > MaxPriorityLength {
+		return fieldError("defaultPriority", fmt.Sprintf("must be at most %d characters", MaxPriorityLength))
+	}
+	switch project.DefaultNotifyMethod {
+	case "", models.NotifyMethodEmail, models.NotifyMethodPush:
+	default:
+		return fieldError("defaultNotifyMethod", fmt.Sprintf("must be %q, %q or empty", models.NotifyMethodEmail, models.NotifyMethodPush))
+	}
+	if project.DefaultReminderOffsets == nil {
+		project.DefaultReminderOffsets = []string{}
+	}
+	for _, offset := range project.DefaultReminderOffsets {
+		d, err := time.ParseDuration(offset)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%w, got %q", ErrInvalidOffset, offset)
+		}
+	}
+	return nil
+}
+
+// applyProjectDefaults fills in the fields the task leaves empty from the
+// project defaults. A task that sends an empty reminder list keeps it empty,
+// only a task without any reminders gets the default ones.
+func applyProjectDefaults(project models.Project, task *models.Task) error {
+	if task.Priority == "" {
+		task.Priority = project.DefaultPriority
+	}
+	if task.NotifyMethod == "" {
+		task.NotifyMethod = project.DefaultNotifyMethod
+	}
+
+	if task.Reminders == nil && !task.DueDateTime.IsZero() {
+		for _, offset := range project.DefaultReminderOffsets {
+			d, err := time.ParseDuration(offset)
+			if err != nil {
+				return err
+			}
+			task.Reminders = append(task.Reminders, models.Reminder{
+				ID:     models.NewID(),
+				Date:   task.DueDateTime.Add(-d).UTC().Format(time.RFC3339),
+				TaskID: task.ID,
+			})
+		}
+	}
+
+	return nil
+}
+
+// @Summary Create a new project
+// @Description Adds a new project to the database, placed after the user's other projects
+// @ID create-project
+// @Accept json
+// @Produce json
+// @Param project body models.Project true "Project details"
+// @Success 200 {object} models.Project "Successfully created project"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects [post]
+func CreateProject(ctx context.Context, project models.Project) (models.Project, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Project{}, err
+	}
+	err = validateProject(&project)
+	if err != nil {
+		return models.Project{}, err
+	}
+	project.ID = models.NewID()
+	project.UserID = userID
+
+	row := config.DB.QueryRow(`
+		INSERT INTO projects (id, name, description, user_id, position, default_priority, default_notify_method, default_reminder_offsets)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM projects WHERE user_id = $4), $5, $6, $7)
+		RETURNING `+projectColumns,
+		project.ID, project.Name, project.Description, project.UserID,
+		project.DefaultPriority, project.DefaultNotifyMethod, pq.Array(project.DefaultReminderOffsets))
+	return scanProject(row)
+}
+
+// @Summary Get a project by ID
+// @Description Retrieves a project from the database by ID
+// @ID get-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project "Successfully retrieved project"
+// @Failure 404 {object} string "Project not found"
+// @Router /projects/{id} [get]
+func GetProject(ctx context.Context, id string) (models.Project, error) {
+	return getProject(config.DB, auth.UserID(ctx), id)
+}
+
+// getProject reads a project of the given user. Projects of other users are
+// not found.
+func getProject(q queryer, userID, id string) (models.Project, error) {
+	project, err := scanProject(q.QueryRow("SELECT "+projectColumns+" FROM projects WHERE id = $1 AND user_id = $2", id, userID))
+	return project, notFound(err, ErrProjectNotFound)
+}
+
+// checkProject reports an invalid projectID field unless the project of a
+// task is a project of the given user.
+func checkProject(q queryer, userID, projectID string) error {
+	_, err := getProject(q, userID, projectID)
+	if errors.Is(err, ErrProjectNotFound) {
+		return fieldError("projectID", "does not exist")
+	}
+	return err
+}
+
+// @Summary Get all projects
+// @Description Retrieves the projects of a user in their saved order
+// @ID get-all-projects
+// @Produce json
+// @Param archived query bool false "List archived projects instead of active ones"
+// @Success 200 {array} models.Project "Successfully retrieved projects"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects [get]
+func GetAllProjects(ctx context.Context, archived bool) ([]models.Project, error) {
+	rows, err := config.DB.Query("SELECT "+projectColumns+" FROM projects WHERE user_id = $1 AND archived = $2 ORDER BY position, created_at",
+		auth.UserID(ctx), archived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// @Summary Update a project by ID
+// @Description Updates the name, description and task defaults of a project
+// @ID update-project
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param project body models.Project true "Updated project details"
+// @Success 200 {object} models.Project "Successfully updated project"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects/{id} [put]
+func UpdateProject(ctx context.Context, id string, updatedProject models.Project) (models.Project, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Project{}, err
+	}
+	err = validateProject(&updatedProject)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	row := config.DB.QueryRow(`
+		UPDATE projects SET name = $1, description = $2, default_priority = $3, default_notify_method = $4, default_reminder_offsets = $5
+		WHERE id = $6 AND user_id = $7
+		RETURNING `+projectColumns,
+		updatedProject.Name, updatedProject.Description, updatedProject.DefaultPriority, updatedProject.DefaultNotifyMethod,
+		pq.Array(updatedProject.DefaultReminderOffsets), id, userID)
+	project, err := scanProject(row)
+	return project, notFound(err, ErrProjectNotFound)
+}
+
+// @Summary Archive or unarchive a project
+// @Description Archived projects are hidden from the default project listing
+// @ID archive-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project "Successfully archived project"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects/{id}/archive [post]
+func SetProjectArchived(ctx context.Context, id string, archived bool) (models.Project, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	row := config.DB.QueryRow("UPDATE projects SET archived = $1 WHERE id = $2 AND user_id = $3 RETURNING "+projectColumns, archived, id, userID)
+	project, err := scanProject(row)
+	return project, notFound(err, ErrProjectNotFound)
+}
+
+// @Summary Reorder projects
+// @Description Sets the position of each project to its index in the given list
+// @ID reorder-projects
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Project "Projects in their new order"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects/order [put]
+func ReorderProjects(ctx context.Context, projectIDs []string) ([]models.Project, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for position, id := range uniqueStrings(projectIDs) {
+		res, err := tx.Exec("UPDATE projects SET position = $1 WHERE id = $2 AND user_id = $3", position, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return GetAllProjects(ctx, false)
+}
+
+// @Summary Delete a project by ID
+// @Description Removes a project, its tasks are kept without a project
+// @ID delete-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {string} string "Successfully deleted project"
+// @Failure 500 {object} string "Internal server error"
+// @Router /projects/{id} [delete]
+func DeleteProject(ctx context.Context, id string) error {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := config.DB.Exec("DELETE FROM projects WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx, so the helpers below
+// can run inside or outside a transaction.
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// taskColumns lists the task columns read by scanTask, in order.
+const taskColumns = "id, title, description, priority, due_date_time, COALESCE(user_id, ''), COALESCE(project_id, ''), COALESCE(contact_id, ''), " +
+	"notify_method, notify_status, notify_message, COALESCE(parent_id, ''), status, completed_at, estimate_seconds, deleted_at, version, updated_at, recurrence"
+
+func scanTask(row scanner) (models.Task, error) {
+	var task models.Task
+	var completedAt, deletedAt sql.NullTime
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Priority, &task.DueDateTime, &task.UserID, &task.ProjectID, &task.ContactID,
+		&task.NotifyMethod, &task.NotifyStatus, &task.NotifyMessage, &task.ParentID, &task.Status, &completedAt, &task.EstimateSeconds, &deletedAt,
+		&task.Version, &task.UpdatedAt, &task.Recurrence)
+	if completedAt.Valid {
+		task.CompletedAt = &completedAt.Time
+	}
+	if deletedAt.Valid {
+		task.DeletedAt = &deletedAt.Time
+	}
+	return task, err
+}
+
+// queryTasks runs a query selecting taskColumns and loads the relations of
+// every task found.
+func queryTasks(q queryer, query string, args ...interface{}) ([]models.Task, error) {
+	tasks, err := scanTasks(q, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relations are loaded once the task rows are closed, as a transaction
+	// can only run one query at a time
+	for i := range tasks {
+		err := loadTaskRelations(q, &tasks[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
+
+// scanTasks runs a query selecting taskColumns, without loading the
+// relations of the tasks.
+func scanTasks(q queryer, query string, args ...interface{}) ([]models.Task, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// loadTaskRelations fills in the reminders, tags, checklist, progress,
+// dependencies, attachments and time spent of a task. Subtasks are loaded
+// separately by loadSubtasks.
+func loadTaskRelations(q queryer, task *models.Task) error {
+	var err error
+
+	task.Reminders, err = getReminders(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.Tags, err = getTaskTags(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.Checklist, err = getChecklist(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.Progress, err = getProgress(q, *task)
+	if err != nil {
+		return err
+	}
+
+	task.BlockedBy, err = getBlockedBy(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.Attachments, err = getAttachments(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.TimeSpentSeconds, err = getTimeSpent(q, task.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getReminders(q queryer, taskID string) ([]models.Reminder, error) {
+	rows, err := q.Query("SELECT id, date FROM reminders WHERE task_id = $1 ORDER BY date, id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []models.Reminder
+	for rows.Next() {
+		reminder := models.Reminder{TaskID: taskID}
+		err := rows.Scan(&reminder.ID, &reminder.Date)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+// scanIDs reads a single column of IDs and closes the rows.
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// taskFilterConds returns the SQL conditions matching filter, which only
+// select tasks out of the trash. Placeholders are numbered after the given
+// args, which are returned extended with the filter's own values.
+func taskFilterConds(filter models.TaskFilter, args []interface{}) ([]string, []interface{}) {
+	conds := []string{"deleted_at IS NULL"}
+
+	if len(filter.TagIDs) > 0 {
+		args = append(args, pq.Array(filter.TagIDs))
+		tagsArg := len(args)
+
+		if filter.TagMode == models.TagModeOr {
+			conds = append(conds, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag_id = ANY($%d))", tagsArg))
+		} else {
+			args = append(args, len(uniqueStrings(filter.TagIDs)))
+			conds = append(conds, fmt.Sprintf(
+				"(SELECT COUNT(DISTINCT tag_id) FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag_id = ANY($%d)) = $%d", tagsArg, len(args)))
+		}
+	}
+
+	if filter.ProjectID != "" {
+		args = append(args, filter.ProjectID)
+		conds = append(conds, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	if filter.ContactID != "" {
+		args = append(args, filter.ContactID)
+		conds = append(conds, fmt.Sprintf("contact_id = $%d", len(args)))
+	}
+
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	return conds, args
+}
+
+// where joins conditions into a WHERE clause, or returns "" if there are none.
+func where(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
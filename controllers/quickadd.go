@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+	"github.com/vikash-parashar/task-manager-2/quickadd"
+)
+
+// QuickAdd parses a task written as free text, such as "Call John at 2:30
+// pm on Monday !high #work", and creates it unless on a dry run. Dates are
+// in the time zone of the request, or else of the calling user.
+//
+// Tags are matched by name among the private tags of the user, and created
+// when missing. The contact is the one mentioned with @name or, without a
+// mention, the only contact whose name appears in the title. On a dry run,
+// what would fail the creation is listed in the warnings.
+func QuickAdd(ctx context.Context, req models.QuickAddRequest) (models.QuickAddResult, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return models.QuickAddResult{}, fieldError("text", "is required")
+	}
+	userID := auth.UserID(ctx)
+	loc, err := quickAddLocation(userID, req.TimeZone)
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+
+	parsed := quickadd.Parse(req.Text, time.Now().In(loc))
+	result := models.QuickAddResult{TimeZone: loc.String(), Tokens: parsed.Tokens, Warnings: []string{}}
+	if result.Tokens == nil {
+		result.Tokens = []models.QuickAddToken{}
+	}
+	task := models.Task{Title: parsed.Title, Priority: parsed.Priority, UserID: userID}
+	if parsed.Due.IsZero() {
+		for _, token := range parsed.Tokens {
+			if token.Kind == models.QuickAddReminder {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%q is ignored, the task has no due date", token.Text))
+			}
+		}
+	} else {
+		task.DueDateTime = parsed.Due.UTC()
+		for _, offset := range parsed.Reminders {
+			task.Reminders = append(task.Reminders, models.Reminder{Date: parsed.Due.Add(-offset).UTC().Format(time.RFC3339)})
+		}
+	}
+
+	newTags, err := resolveQuickAddTags(ctx, &result, &task, req.DryRun)
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+	err = resolveQuickAddContact(&result, &task)
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+
+	if req.DryRun {
+		check := task
+		check.Reminders = append([]models.Reminder(nil), task.Reminders...)
+		var verr *ValidationError
+		if err := validateTask(&check); errors.As(err, &verr) {
+			for _, field := range verr.Fields {
+				result.Warnings = append(result.Warnings, field.Field+" "+field.Message)
+			}
+		}
+		result.Task = task
+		return result, nil
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+	defer tx.Rollback()
+
+	for _, tag := range newTags {
+		_, err = tx.Exec("INSERT INTO tags (id, name, color, user_id, workspace_id) VALUES ($1, $2, $3, $4, $5)",
+			tag.ID, tag.Name, tag.Color, tag.UserID, tag.WorkspaceID)
+		if isUniqueViolation(err) {
+			return models.QuickAddResult{}, ErrTagExists
+		}
+		if err != nil {
+			return models.QuickAddResult{}, err
+		}
+	}
+
+	id, err := createTask(ctx, tx, task)
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+	for _, tag := range task.Tags {
+		_, err = tx.Exec("INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2)", id, tag.ID)
+		if err != nil {
+			return models.QuickAddResult{}, err
+		}
+		err = auditTaskTag(ctx, tx, models.AuditCreate, id, tag)
+		if err != nil {
+			return models.QuickAddResult{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+
+	result.Task, err = GetTask(id, DefaultSubtaskDepth)
+	result.Created = true
+	return result, err
+}
+
+// quickAddLocation returns the time zone of a quick-add request: the one
+// it names, or else the one of the user, or else UTC.
+func quickAddLocation(userID, zone string) (*time.Location, error) {
+	if zone == "" && userID != "" {
+		user, err := GetUser(userID)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+		zone = user.TimeZone
+	}
+	return loadLocation(zone)
+}
+
+// resolveQuickAddTags adds the #tags of a quick-add text to the task, and
+// returns the tags to create as they do not exist yet. New tags only get an
+// ID when they are to be created, not on a dry run.
+func resolveQuickAddTags(ctx context.Context, result *models.QuickAddResult, task *models.Task, dryRun bool) ([]models.Tag, error) {
+	tags, err := GetAllTags(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.Tag, len(tags))
+	for _, tag := range tags {
+		byName[strings.ToLower(tag.Name)] = tag
+	}
+
+	var newTags []models.Tag
+	added := map[string]bool{}
+	for i, token := range result.Tokens {
+		if token.Kind != models.QuickAddTag {
+			continue
+		}
+		key := strings.ToLower(token.Value)
+		tag, ok := byName[key]
+		if !ok {
+			tag = models.Tag{Name: token.Value, UserID: task.UserID}
+			err = normalizeTag(&tag)
+			if err != nil {
+				return nil, err
+			}
+			if dryRun {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("tag #%s does not exist yet, it will be created", tag.Name))
+			} else {
+				tag.ID = models.NewID()
+			}
+			newTags = append(newTags, tag)
+			byName[key] = tag
+		}
+		result.Tokens[i].ID = tag.ID
+		if !added[key] {
+			task.Tags = append(task.Tags, tag)
+			added[key] = true
+		}
+	}
+	return newTags, nil
+}
+
+// resolveQuickAddContact sets the contact of the task to the one mentioned
+// in a quick-add text or, without a mention, to the only contact whose name
+// appears in the title.
+func resolveQuickAddContact(result *models.QuickAddResult, task *models.Task) error {
+	contacts, err := GetAllContacts(task.UserID)
+	if err != nil {
+		return err
+	}
+
+	mentioned := false
+	for i, token := range result.Tokens {
+		if token.Kind != models.QuickAddContact {
+			continue
+		}
+		mentioned = true
+		var matches []models.Contact
+		for _, contact := range contacts {
+			words := nameWords(contact.Name)
+			mention := normalizeName(token.Value)
+			if len(words) > 0 && (mention == strings.Join(words, "") || mention == words[0]) {
+				matches = append(matches, contact)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("no contact is named %q", token.Value))
+		case len(matches) > 1:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d contacts are named %q", len(matches), token.Value))
+		case task.ContactID != "":
+			result.Warnings = append(result.Warnings, fmt.Sprintf("@%s is ignored, a task has a single contact", token.Value))
+		default:
+			task.ContactID = matches[0].ID
+			result.Tokens[i].Value = matches[0].Name
+			result.Tokens[i].ID = matches[0].ID
+		}
+	}
+	if mentioned {
+		return nil
+	}
+
+	// Without a mention, look for the full names in the title, or else for
+	// the first names
+	title := strings.Fields(task.Title)
+	titleWords := make([]string, len(title))
+	for i, word := range title {
+		titleWords[i] = normalizeName(word)
+	}
+	type match struct {
+		contact models.Contact
+		text    string
+	}
+	var full, first []match
+	for _, contact := range contacts {
+		words := nameWords(contact.Name)
+		if len(words) == 0 {
+			continue
+		}
+		if i := indexWords(titleWords, words); i >= 0 {
+			full = append(full, match{contact, strings.Join(title[i:i+len(words)], " ")})
+		} else if i := indexWords(titleWords, words[:1]); i >= 0 {
+			first = append(first, match{contact, title[i]})
+		}
+	}
+	matches := full
+	if len(matches) == 0 {
+		matches = first
+	}
+	switch len(matches) {
+	case 0:
+	case 1:
+		task.ContactID = matches[0].contact.ID
+		result.Tokens = append(result.Tokens, models.QuickAddToken{
+			Text:  matches[0].text,
+			Kind:  models.QuickAddContact,
+			Value: matches[0].contact.Name,
+			ID:    matches[0].contact.ID,
+		})
+	default:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the title names %d contacts, mention one with @name", len(matches)))
+	}
+	return nil
+}
+
+// nameWords returns the normalized words of a name.
+func nameWords(name string) []string {
+	var words []string
+	for _, word := range strings.Fields(name) {
+		if word = normalizeName(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// normalizeName lower-cases a name and drops everything but letters and
+// digits, so that "@john.smith" matches "John Smith".
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// indexWords returns the index of the first occurrence of words in s, or -1.
+func indexWords(s, words []string) int {
+	for i := 0; i+len(words) <= len(s); i++ {
+		found := true
+		for j, word := range words {
+			if s[i+j] != word {
+				found = false
+				break
+			}
+		}
+		if found {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrReminderNotFound  = newError(KindNotFound, "reminder_not_found", "reminder not found")
+	ErrReminderInPast    = newError(KindValidation, "reminder_in_past", "reminder date must be in the future")
+	ErrDuplicateReminder = newError(KindConflict, "duplicate_reminder", "the task already has a reminder at this date")
+)
+
+// parseReminderDate parses an RFC3339 reminder date and returns it in the
+// form stored in the database, in UTC.
+func parseReminderDate(value string) (string, time.Time, error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: %q", ErrInvalidReminderDate, value)
+	}
+	date = date.UTC()
+	return date.Format(time.RFC3339), date, nil
+}
+
+// checkReminder validates the date of a reminder added to or moved within
+// a task read by lockTask, and returns it in stored form.
+func checkReminder(task models.Task, reminderID, value string) (string, error) {
+	date, t, err := parseReminderDate(value)
+	if err != nil {
+		return "", err
+	}
+	if !t.After(time.Now()) {
+		return "", ErrReminderInPast
+	}
+	for _, reminder := range task.Reminders {
+		if reminder.ID != reminderID && reminder.Date == date {
+			return "", fmt.Errorf("%w: %s", ErrDuplicateReminder, date)
+		}
+	}
+	return date, nil
+}
+
+// recordReminderChange records a change of the reminders of a task read by
+// lockTask in its activity feed and the audit log.
+func recordReminderChange(ctx context.Context, tx *sql.Tx, task models.Task, action string, before, after *models.Reminder) error {
+	reminders, err := getReminders(tx, task.ID)
+	if err != nil {
+		return err
+	}
+	err = recordActivity(tx, task.ID, auth.UserID(ctx), models.ActivityChanged, "reminders", reminderDates(task.Reminders), reminderDates(reminders))
+	if err != nil {
+		return err
+	}
+
+	var entityID string
+	var beforeFields, afterFields interface{}
+	if before != nil {
+		entityID, beforeFields = before.ID, before
+	}
+	if after != nil {
+		entityID, afterFields = after.ID, after
+	}
+	return recordAudit(ctx, tx, action, models.AuditEntityReminder, entityID, task.ID, beforeFields, afterFields)
+}
+
+// findReminder returns the reminder of a task read by lockTask with the
+// given ID, or ErrReminderNotFound.
+func findReminder(task models.Task, reminderID string) (models.Reminder, error) {
+	for _, reminder := range task.Reminders {
+		if reminder.ID == reminderID {
+			return reminder, nil
+		}
+	}
+	return models.Reminder{}, ErrReminderNotFound
+}
+
+// @Summary Get the reminders of a task
+// @Description Lists the reminders of a task by date
+// @ID get-reminders
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Reminder "Reminders of the task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/reminders [get]
+func GetReminders(taskID string) ([]models.Reminder, error) {
+	err := checkTaskExists(config.DB, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getReminders(config.DB, taskID)
+}
+
+// @Summary Add a reminder
+// @Description Adds a reminder to a task, at a future date no other reminder of the task has
+// @ID create-reminder
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminder body models.Reminder true "Reminder date"
+// @Success 201 {object} models.Reminder "Created reminder"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/reminders [post]
+func CreateReminder(ctx context.Context, taskID string, reminder models.Reminder) (models.Reminder, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	defer tx.Rollback()
+
+	task, err := lockTask(tx, taskID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	reminder.Date, err = checkReminder(task, "", reminder.Date)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	reminder.ID = models.NewID()
+	reminder.TaskID = taskID
+
+	_, err = tx.Exec("INSERT INTO reminders (id, date, task_id) VALUES ($1, $2, $3)", reminder.ID, reminder.Date, taskID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	err = recordReminderChange(ctx, tx, task, models.AuditCreate, nil, &reminder)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	return reminder, nil
+}
+
+// @Summary Update a reminder
+// @Description Moves a reminder of a task to another future date no other reminder of the task has
+// @ID update-reminder
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Param reminder body models.Reminder true "Reminder date"
+// @Success 200 {object} models.Reminder "Updated reminder"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/reminders/{reminderID} [put]
+func UpdateReminder(ctx context.Context, taskID, reminderID string, reminder models.Reminder) (models.Reminder, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	defer tx.Rollback()
+
+	task, err := lockTask(tx, taskID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	before, err := findReminder(task, reminderID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	reminder.Date, err = checkReminder(task, reminderID, reminder.Date)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	reminder.ID = reminderID
+	reminder.TaskID = taskID
+	if reminder.Date == before.Date {
+		return reminder, nil
+	}
+
+	_, err = tx.Exec("UPDATE reminders SET date = $1 WHERE id = $2", reminder.Date, reminderID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	err = recordReminderChange(ctx, tx, task, models.AuditUpdate, &before, &reminder)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	return reminder, nil
+}
+
+// @Summary Delete a reminder
+// @Description Removes a reminder from a task
+// @ID delete-reminder
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Success 200 {string} string "Successfully deleted reminder"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/reminders/{reminderID} [delete]
+func DeleteReminder(ctx context.Context, taskID, reminderID string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	task, err := lockTask(tx, taskID)
+	if err != nil {
+		return err
+	}
+	before, err := findReminder(task, reminderID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM reminders WHERE id = $1", reminderID)
+	if err != nil {
+		return err
+	}
+
+	err = recordReminderChange(ctx, tx, task, models.AuditDelete, &before, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FireDueReminders marks the reminders dated in (from, to] of the tasks out
+// of the trash as fired, and notifies an EventReminderFired event for each.
+// A reminder fires once per date, even if checked again. Reminders with a
+// date that is not in RFC3339 format never fire.
+func FireDueReminders(from, to time.Time) ([]models.Event, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		WITH fired AS (
+			INSERT INTO fired_reminders (reminder_id, date)
+			SELECT reminders.id, reminders.date FROM reminders JOIN tasks ON tasks.id = reminders.task_id
+			WHERE tasks.deleted_at IS NULL AND reminder_date(reminders.date) > $1 AND reminder_date(reminders.date) <= $2
+			ON CONFLICT DO NOTHING
+			RETURNING reminder_id
+		)
+		SELECT reminders.id, reminders.task_id, COALESCE(tasks.user_id, ''), COALESCE(tasks.project_id, ''), tasks.version, nextval('task_events_id_seq')
+		FROM fired JOIN reminders ON reminders.id = fired.reminder_id JOIN tasks ON tasks.id = reminders.task_id
+		ORDER BY reminders.date, reminders.id`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fire reminders: %v", err)
+	}
+	var fired []models.Event
+	for rows.Next() {
+		event := models.Event{Type: models.EventReminderFired, OccurredAt: to}
+		err := rows.Scan(&event.ReminderID, &event.TaskID, &event.UserID, &event.ProjectID, &event.Version, &event.ID)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to fire reminders: %v", err)
+		}
+		fired = append(fired, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fire reminders: %v", err)
+	}
+
+	for _, event := range fired {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec("SELECT pg_notify($1, $2)", models.EventChannel, string(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to notify fired reminder: %v", err)
+		}
+	}
+
+	return fired, tx.Commit()
+}
@@ -0,0 +1,533 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Levels of subtasks returned by GetTask
+const (
+	DefaultSubtaskDepth = 1
+	MaxSubtaskDepth     = 10
+)
+
+var (
+	ErrChecklistItemNotFound = newError(KindNotFound, "checklist_item_not_found", "checklist item not found")
+	ErrParentNotFound        = newError(KindValidation, "parent_not_found", "parent task not found")
+	ErrParentCycle           = newError(KindValidation, "parent_cycle", "a task cannot be a subtask of itself or of its own subtasks")
+	ErrChecklistText         = newError(KindValidation, "invalid_checklist_text", "checklist item text is required and must be at most 255 characters")
+	ErrChecklistMismatch     = newError(KindValidation, "checklist_mismatch", "checklist items do not belong to the task")
+)
+
+// subtreeQuery selects the ID of task $1 and of all its descendants that
+// are not in the trash.
+const subtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE id = $1
+		UNION ALL
+		SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id WHERE tasks.deleted_at IS NULL
+	)
+	SELECT id FROM subtree`
+
+// fullSubtreeQuery selects the ID of task $1 and of all its descendants,
+// including the ones in the trash.
+const fullSubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE id = $1
+		UNION ALL
+		SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+	)
+	SELECT id FROM subtree`
+
+// checkParent verifies that parentID exists out of the trash and is not
+// taskID itself or one of its descendants.
+func checkParent(q queryer, taskID, parentID string) error {
+	if parentID == taskID {
+		return ErrParentCycle
+	}
+
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", parentID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrParentNotFound
+	}
+
+	var isDescendant bool
+	err = q.QueryRow("SELECT EXISTS (SELECT 1 FROM ("+subtreeQuery+") AS descendants WHERE id = $2)", taskID, parentID).Scan(&isDescendant)
+	if err != nil {
+		return err
+	}
+	if isDescendant {
+		return ErrParentCycle
+	}
+
+	return nil
+}
+
+// loadSubtasks fills in the subtasks of a task, down to depth levels.
+func loadSubtasks(q queryer, task *models.Task, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	subtasks, err := queryTasks(q, "SELECT "+taskColumns+" FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY due_date_time, title", task.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range subtasks {
+		err := loadSubtasks(q, &subtasks[i], depth-1)
+		if err != nil {
+			return err
+		}
+	}
+	task.Subtasks = subtasks
+
+	return nil
+}
+
+// getProgress returns the share of checklist items and direct subtasks of
+// the task that are done. A task without either is done once completed.
+func getProgress(q queryer, task models.Task) (float64, error) {
+	var items, doneItems, subtasks, doneSubtasks int
+	err := q.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM checklist_items WHERE task_id = $1),
+			(SELECT COUNT(*) FROM checklist_items WHERE task_id = $1 AND done),
+			(SELECT COUNT(*) FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL AND status = $2)`,
+		task.ID, models.TaskStatusCompleted).Scan(&items, &doneItems, &subtasks, &doneSubtasks)
+	if err != nil {
+		return 0, err
+	}
+
+	total := items + subtasks
+	if total == 0 {
+		if task.Status == models.TaskStatusCompleted {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return float64(doneItems+doneSubtasks) / float64(total), nil
+}
+
+// @Summary Complete a task
+// @Description Marks a task as completed, and optionally its subtasks and checklist items
+// @ID complete-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param cascade query bool false "Also complete subtasks and checklist items"
+// @Success 200 {object} models.Task "Successfully completed task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/complete [post]
+func CompleteTask(ctx context.Context, id string, cascade bool) (models.Task, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	err = completeTask(ctx, tx, id, cascade)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(id, DefaultSubtaskDepth)
+}
+
+// completeTask marks a task as completed, with its subtasks and checklist
+// items if cascade is set.
+func completeTask(ctx context.Context, tx *sql.Tx, id string, cascade bool) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		return notFound(err, ErrTaskNotFound)
+	}
+	if status == models.TaskStatusBlocked {
+		return ErrTaskBlocked
+	}
+
+	ids := "SELECT $1::VARCHAR"
+	if cascade {
+		ids = subtreeQuery
+	}
+
+	// Only tasks that were not completed yet are returned
+	rows, err := tx.Query(`
+		UPDATE tasks SET status = $2, completed_at = NOW()
+		FROM (SELECT id, status AS old_status FROM tasks WHERE id IN (`+ids+`) AND status <> $2) AS previous
+		WHERE tasks.id = previous.id
+		RETURNING tasks.id, previous.old_status, tasks.completed_at`,
+		id, models.TaskStatusCompleted)
+	if err != nil {
+		return err
+	}
+	var completed []string
+	var changes []statusChange
+	for rows.Next() {
+		change := statusChange{after: auditedStatus{Status: models.TaskStatusCompleted}}
+		var completedAt time.Time
+		err := rows.Scan(&change.taskID, &change.before.Status, &completedAt)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		change.after.CompletedAt = &completedAt
+		completed = append(completed, change.taskID)
+		changes = append(changes, change)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if cascade {
+		rows, err := tx.Query("UPDATE checklist_items SET done = TRUE WHERE task_id IN ("+subtreeQuery+") AND NOT done RETURNING id, task_id, text, done, position", id)
+		if err != nil {
+			return err
+		}
+		var checked []models.ChecklistItem
+		for rows.Next() {
+			item, err := scanChecklistItem(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			checked = append(checked, item)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, item := range checked {
+			before := item
+			before.Done = false
+			err = recordAudit(ctx, tx, models.AuditUpdate, models.AuditEntityChecklistItem, item.ID, item.TaskID, before, item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, change := range changes {
+		err = recordActivity(tx, change.taskID, auth.UserID(ctx), models.ActivityCompleted, "", "", "")
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, tx, models.AuditComplete, models.AuditEntityTask, change.taskID, change.taskID, change.before, change.after)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Unblock the tasks that were waiting on the completed ones
+	err = refreshDependents(ctx, tx, completed)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// @Summary Reopen a task
+// @Description Marks a completed task as open again
+// @ID reopen-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task "Successfully reopened task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/reopen [post]
+func ReopenTask(ctx context.Context, id string) (models.Task, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	var before auditedStatus
+	err = tx.QueryRow("SELECT status, completed_at FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&before.Status, &before.CompletedAt)
+	if err != nil {
+		return models.Task{}, notFound(err, ErrTaskNotFound)
+	}
+	if before.Status != models.TaskStatusCompleted {
+		return GetTask(id, DefaultSubtaskDepth)
+	}
+
+	_, err = tx.Exec("UPDATE tasks SET status = $1, completed_at = NULL WHERE id = $2", models.TaskStatusOpen, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = recordActivity(tx, id, auth.UserID(ctx), models.ActivityReopened, "", "", "")
+	if err != nil {
+		return models.Task{}, err
+	}
+	err = recordAudit(ctx, tx, models.AuditReopen, models.AuditEntityTask, id, id, before, auditedStatus{Status: models.TaskStatusOpen})
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	// The task may itself be blocked, and blocks its dependents again
+	err = refreshBlockedStatus(ctx, tx, []string{id})
+	if err != nil {
+		return models.Task{}, err
+	}
+	err = refreshDependents(ctx, tx, []string{id})
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(id, DefaultSubtaskDepth)
+}
+
+func getChecklist(q queryer, taskID string) ([]models.ChecklistItem, error) {
+	rows, err := q.Query("SELECT id, task_id, text, done, position FROM checklist_items WHERE task_id = $1 ORDER BY position", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ChecklistItem
+	for rows.Next() {
+		var item models.ChecklistItem
+		err := rows.Scan(&item.ID, &item.TaskID, &item.Text, &item.Done, &item.Position)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func scanChecklistItem(row scanner) (models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := row.Scan(&item.ID, &item.TaskID, &item.Text, &item.Done, &item.Position)
+	return item, err
+}
+
+func validateChecklistItem(item *models.ChecklistItem) error {
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" || len(item.Text) > 255 {
+		return ErrChecklistText
+	}
+	return nil
+}
+
+// @Summary Add a checklist item
+// @Description Appends an item to the checklist of a task
+// @ID create-checklist-item
+// @Accept json
+// @Produce json
+// @Param item body models.ChecklistItem true "Checklist item details"
+// @Success 200 {object} models.ChecklistItem "Successfully created checklist item"
+// @Failure 500 {object} string "Internal server error"
+// @Router /checklistItems [post]
+func CreateChecklistItem(ctx context.Context, item models.ChecklistItem) (models.ChecklistItem, error) {
+	err := validateChecklistItem(&item)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+	defer tx.Rollback()
+
+	err = checkTaskExists(tx, item.TaskID)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	row := tx.QueryRow(`
+		INSERT INTO checklist_items (id, task_id, text, done, position)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE task_id = $2))
+		RETURNING id, task_id, text, done, position`,
+		models.NewID(), item.TaskID, item.Text, item.Done)
+	created, err := scanChecklistItem(row)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	err = recordAudit(ctx, tx, models.AuditCreate, models.AuditEntityChecklistItem, created.ID, created.TaskID, nil, created)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	return created, tx.Commit()
+}
+
+// getChecklistItemForUpdate reads a checklist item and locks it until the
+// end of the transaction.
+func getChecklistItemForUpdate(tx *sql.Tx, id string) (models.ChecklistItem, error) {
+	row := tx.QueryRow("SELECT id, task_id, text, done, position FROM checklist_items WHERE id = $1 FOR UPDATE", id)
+	item, err := scanChecklistItem(row)
+	return item, notFound(err, ErrChecklistItemNotFound)
+}
+
+// updateChecklistItem sets the text and state of a checklist item in a
+// transaction and records the change in the audit log.
+func updateChecklistItem(ctx context.Context, id string, update func(item *models.ChecklistItem)) (models.ChecklistItem, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := getChecklistItemForUpdate(tx, id)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+	item := before
+	update(&item)
+
+	_, err = tx.Exec("UPDATE checklist_items SET text = $1, done = $2 WHERE id = $3", item.Text, item.Done, id)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	err = recordAudit(ctx, tx, models.AuditUpdate, models.AuditEntityChecklistItem, id, item.TaskID, before, item)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	return item, tx.Commit()
+}
+
+// @Summary Update a checklist item
+// @Description Updates the text and state of a checklist item
+// @ID update-checklist-item
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Param item body models.ChecklistItem true "Updated checklist item"
+// @Success 200 {object} models.ChecklistItem "Successfully updated checklist item"
+// @Failure 500 {object} string "Internal server error"
+// @Router /checklistItems/{id} [put]
+func UpdateChecklistItem(ctx context.Context, id string, updatedItem models.ChecklistItem) (models.ChecklistItem, error) {
+	err := validateChecklistItem(&updatedItem)
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	return updateChecklistItem(ctx, id, func(item *models.ChecklistItem) {
+		item.Text = updatedItem.Text
+		item.Done = updatedItem.Done
+	})
+}
+
+// @Summary Toggle a checklist item
+// @Description Flips a checklist item between done and not done
+// @ID toggle-checklist-item
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Success 200 {object} models.ChecklistItem "Successfully toggled checklist item"
+// @Failure 500 {object} string "Internal server error"
+// @Router /checklistItems/{id}/toggle [post]
+func ToggleChecklistItem(ctx context.Context, id string) (models.ChecklistItem, error) {
+	return updateChecklistItem(ctx, id, func(item *models.ChecklistItem) {
+		item.Done = !item.Done
+	})
+}
+
+// @Summary Reorder a checklist
+// @Description Sets the position of each item of a task's checklist to its index in the given list
+// @ID reorder-checklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.ChecklistItem "Checklist in its new order"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/checklist/order [put]
+func ReorderChecklist(ctx context.Context, taskID string, itemIDs []string) ([]models.ChecklistItem, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := getChecklist(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for position, id := range uniqueStrings(itemIDs) {
+		res, err := tx.Exec("UPDATE checklist_items SET position = $1 WHERE id = $2 AND task_id = $3", position, id, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, ErrChecklistMismatch
+		}
+	}
+
+	items, err := getChecklist(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the items that moved are recorded
+	previous := make(map[string]models.ChecklistItem, len(before))
+	for _, item := range before {
+		previous[item.ID] = item
+	}
+	for _, item := range items {
+		err = recordAudit(ctx, tx, models.AuditUpdate, models.AuditEntityChecklistItem, item.ID, taskID, previous[item.ID], item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return items, tx.Commit()
+}
+
+// @Summary Delete a checklist item
+// @Description Removes an item from the checklist of a task
+// @ID delete-checklist-item
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Success 200 {string} string "Successfully deleted checklist item"
+// @Failure 500 {object} string "Internal server error"
+// @Router /checklistItems/{id} [delete]
+func DeleteChecklistItem(ctx context.Context, id string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow("DELETE FROM checklist_items WHERE id = $1 RETURNING id, task_id, text, done, position", id)
+	item, err := scanChecklistItem(row)
+	if err != nil {
+		return notFound(err, ErrChecklistItemNotFound)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditDelete, models.AuditEntityChecklistItem, id, item.TaskID, item, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,498 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// DefaultTagColor is used for tags created without a color.
+const DefaultTagColor = "#9e9e9e"
+
+var (
+	ErrTagNotFound     = newError(KindNotFound, "tag_not_found", "tag not found")
+	ErrTagNameRequired = newError(KindValidation, "tag_name_required", "tag name is required")
+	ErrInvalidTagColor = newError(KindValidation, "invalid_tag_color", "tag color must be a hex color such as #ff9800")
+	ErrTagExists       = newError(KindConflict, "tag_exists", "a tag with this name already exists")
+	ErrTagScope        = newError(KindValidation, "tag_scope_mismatch", "tags belong to different users or workspaces")
+	ErrWorkspace       = newError(KindForbidden, "workspace_forbidden", "the workspace is not the one of the request")
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// uniqueViolation is the Postgres error code for a unique constraint violation.
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// tagScope returns the condition matching the tags the caller can use, its
+// private tags and the tags of the workspace of the request, with its
+// arguments numbered from n.
+func tagScope(ctx context.Context, n int) (string, []interface{}) {
+	cond := fmt.Sprintf("((tags.workspace_id = '' AND tags.user_id = $%d) OR (tags.workspace_id <> '' AND tags.workspace_id = $%d))", n, n+1)
+	return cond, []interface{}{auth.UserID(ctx), auth.WorkspaceID(ctx)}
+}
+
+func normalizeTag(tag *models.Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return ErrTagNameRequired
+	}
+	if tag.Color == "" {
+		tag.Color = DefaultTagColor
+	}
+	if !tagColorPattern.MatchString(tag.Color) {
+		return ErrInvalidTagColor
+	}
+	tag.Color = strings.ToLower(tag.Color)
+	return nil
+}
+
+// @Summary Create a new tag
+// @Description Adds a new tag to the database
+// @ID create-tag
+// @Accept json
+// @Produce json
+// @Param tag body models.Tag true "Tag details"
+// @Success 200 {object} models.Tag "Successfully created tag"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tags [post]
+func CreateTag(ctx context.Context, tag models.Tag) (models.Tag, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	if tag.WorkspaceID != "" && tag.WorkspaceID != auth.WorkspaceID(ctx) {
+		return models.Tag{}, ErrWorkspace
+	}
+	err = normalizeTag(&tag)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	tag.UserID = userID
+	tag.ID = models.NewID()
+
+	_, err = config.DB.Exec("INSERT INTO tags (id, name, color, user_id, workspace_id) VALUES ($1, $2, $3, $4, $5)",
+		tag.ID, tag.Name, tag.Color, tag.UserID, tag.WorkspaceID)
+	if isUniqueViolation(err) {
+		return models.Tag{}, ErrTagExists
+	}
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// @Summary Get a tag by ID
+// @Description Retrieves a tag from the database by ID
+// @ID get-tag
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.Tag "Successfully retrieved tag"
+// @Failure 404 {object} string "Tag not found"
+// @Router /tags/{id} [get]
+func GetTag(ctx context.Context, id string) (models.Tag, error) {
+	return getTag(ctx, config.DB, id)
+}
+
+// getTag reads a tag the caller can use, see tagScope.
+func getTag(ctx context.Context, q queryer, id string) (models.Tag, error) {
+	var tag models.Tag
+	scope, args := tagScope(ctx, 2)
+	row := q.QueryRow("SELECT id, name, color, user_id, workspace_id FROM tags WHERE id = $1 AND "+scope, append([]interface{}{id}, args...)...)
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.UserID, &tag.WorkspaceID)
+	if err != nil {
+		return models.Tag{}, notFound(err, ErrTagNotFound)
+	}
+	return tag, nil
+}
+
+// @Summary Get all tags
+// @Description Retrieves the tags of a workspace, or the private tags of the user
+// @ID get-all-tags
+// @Produce json
+// @Param workspaceID query string false "Workspace ID"
+// @Success 200 {array} models.Tag "Successfully retrieved tags"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tags [get]
+func GetAllTags(ctx context.Context, workspaceID string) ([]models.Tag, error) {
+	var rows *sql.Rows
+	var err error
+	if workspaceID != "" {
+		if workspaceID != auth.WorkspaceID(ctx) {
+			return nil, ErrWorkspace
+		}
+		rows, err = config.DB.Query("SELECT id, name, color, user_id, workspace_id FROM tags WHERE workspace_id = $1 ORDER BY LOWER(name)", workspaceID)
+	} else {
+		rows, err = config.DB.Query("SELECT id, name, color, user_id, workspace_id FROM tags WHERE user_id = $1 AND workspace_id = '' ORDER BY LOWER(name)", auth.UserID(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+// auditTaskTag records a tag attached to a task, or detached from it with
+// models.AuditDelete.
+func auditTaskTag(ctx context.Context, q queryer, action, taskID string, tag models.Tag) error {
+	if action == models.AuditDelete {
+		return recordAudit(ctx, q, action, models.AuditEntityTag, tag.ID, taskID, tag, nil)
+	}
+	return recordAudit(ctx, q, action, models.AuditEntityTag, tag.ID, taskID, nil, tag)
+}
+
+func scanTags(rows *sql.Rows) ([]models.Tag, error) {
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		err := rows.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.UserID, &tag.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// @Summary Update a tag by ID
+// @Description Renames or recolors an existing tag
+// @ID update-tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param tag body models.Tag true "Updated tag details"
+// @Success 200 {object} models.Tag "Successfully updated tag"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tags/{id} [put]
+func UpdateTag(ctx context.Context, id string, updatedTag models.Tag) (models.Tag, error) {
+	_, err := requireUser(ctx)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	err = normalizeTag(&updatedTag)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	scope, args := tagScope(ctx, 4)
+	res, err := config.DB.Exec("UPDATE tags SET name = $1, color = $2 WHERE id = $3 AND "+scope,
+		append([]interface{}{updatedTag.Name, updatedTag.Color, id}, args...)...)
+	if isUniqueViolation(err) {
+		return models.Tag{}, ErrTagExists
+	}
+	if err != nil {
+		return models.Tag{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.Tag{}, ErrTagNotFound
+	}
+
+	return GetTag(ctx, id)
+}
+
+// @Summary Delete a tag by ID
+// @Description Removes a tag and detaches it from all tasks
+// @ID delete-tag
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {string} string "Successfully deleted tag"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tags/{id} [delete]
+func DeleteTag(ctx context.Context, id string) error {
+	_, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tag, err := getTag(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("DELETE FROM task_tags WHERE tag_id = $1 RETURNING task_id", id)
+	if err != nil {
+		return err
+	}
+	taskIDs, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+	for _, taskID := range taskIDs {
+		err = auditTaskTag(ctx, tx, models.AuditDelete, taskID, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM tags WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// @Summary Merge tags
+// @Description Moves every task of the source tags onto the target tag and deletes the source tags
+// @ID merge-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Target tag ID"
+// @Success 200 {object} models.Tag "Successfully merged tags"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tags/{id}/merge [post]
+func MergeTags(ctx context.Context, targetID string, sourceIDs []string) (models.Tag, error) {
+	_, err := requireUser(ctx)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Tag{}, err
+	}
+	defer tx.Rollback()
+
+	target, err := getTag(ctx, tx, targetID)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	var sources []string
+	sourceTags := make(map[string]models.Tag)
+	for _, id := range uniqueStrings(sourceIDs) {
+		if id == targetID {
+			continue
+		}
+		source, err := getTag(ctx, tx, id)
+		if err != nil {
+			return models.Tag{}, err
+		}
+		if source.WorkspaceID != target.WorkspaceID ||
+			(target.WorkspaceID == "" && source.UserID != target.UserID) {
+			return models.Tag{}, ErrTagScope
+		}
+		sources = append(sources, id)
+		sourceTags[id] = source
+	}
+
+	rows, err := tx.Query(`
+		INSERT INTO task_tags (task_id, tag_id)
+		SELECT DISTINCT task_id, $1 FROM task_tags WHERE tag_id = ANY($2)
+		ON CONFLICT DO NOTHING
+		RETURNING task_id`, targetID, pq.Array(sources))
+	if err != nil {
+		return models.Tag{}, err
+	}
+	taskIDs, err := scanIDs(rows)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	for _, taskID := range taskIDs {
+		err = auditTaskTag(ctx, tx, models.AuditCreate, taskID, target)
+		if err != nil {
+			return models.Tag{}, err
+		}
+	}
+
+	// The associations of the source tags are removed first to be recorded
+	rows, err = tx.Query("DELETE FROM task_tags WHERE tag_id = ANY($1) RETURNING task_id, tag_id", pq.Array(sources))
+	if err != nil {
+		return models.Tag{}, err
+	}
+	type association struct{ taskID, tagID string }
+	var removed []association
+	for rows.Next() {
+		var a association
+		err := rows.Scan(&a.taskID, &a.tagID)
+		if err != nil {
+			rows.Close()
+			return models.Tag{}, err
+		}
+		removed = append(removed, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.Tag{}, err
+	}
+	for _, a := range removed {
+		err = auditTaskTag(ctx, tx, models.AuditDelete, a.taskID, sourceTags[a.tagID])
+		if err != nil {
+			return models.Tag{}, err
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM tags WHERE id = ANY($1)", pq.Array(sources))
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return target, tx.Commit()
+}
+
+// @Summary Attach tags to a task
+// @Description Adds tags to a task, ignoring tags already attached. Private tags must belong to the owner of the task.
+// @ID attach-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Tag "Tags of the task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/tags [post]
+func AttachTags(ctx context.Context, taskID string, tagIDs []string) ([]models.Tag, error) {
+	_, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ownerID, err := getTaskOwner(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tagID := range uniqueStrings(tagIDs) {
+		tag, err := getTaskTag(ctx, tx, ownerID, tagID)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec("INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", taskID, tagID)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		err = auditTaskTag(ctx, tx, models.AuditCreate, taskID, tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := getTaskTags(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, tx.Commit()
+}
+
+// @Summary Detach tags from a task
+// @Description Removes tags from a task. Private tags must belong to the owner of the task.
+// @ID detach-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Tag "Tags of the task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/tags [delete]
+func DetachTags(ctx context.Context, taskID string, tagIDs []string) ([]models.Tag, error) {
+	_, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ownerID, err := getTaskOwner(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs = uniqueStrings(tagIDs)
+	for _, tagID := range tagIDs {
+		_, err := getTaskTag(ctx, tx, ownerID, tagID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(`
+		DELETE FROM task_tags USING tags
+		WHERE task_tags.tag_id = tags.id AND task_tags.task_id = $1 AND task_tags.tag_id = ANY($2)
+		RETURNING tags.id, tags.name, tags.color, tags.user_id, tags.workspace_id`,
+		taskID, pq.Array(tagIDs))
+	if err != nil {
+		return nil, err
+	}
+	detached, err := scanTags(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range detached {
+		err = auditTaskTag(ctx, tx, models.AuditDelete, taskID, tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := getTaskTags(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, tx.Commit()
+}
+
+// getTaskOwner returns the owner of a task out of the trash.
+func getTaskOwner(q queryer, taskID string) (string, error) {
+	var ownerID string
+	err := q.QueryRow("SELECT COALESCE(user_id, '') FROM tasks WHERE id = $1 AND deleted_at IS NULL", taskID).Scan(&ownerID)
+	return ownerID, notFound(err, ErrTaskNotFound)
+}
+
+// getTaskTag reads a tag the caller can use on a task of ownerID: a tag of
+// the workspace, or a private tag of the owner.
+func getTaskTag(ctx context.Context, q queryer, ownerID, tagID string) (models.Tag, error) {
+	tag, err := getTag(ctx, q, tagID)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	if tag.WorkspaceID == "" && tag.UserID != ownerID {
+		return models.Tag{}, ErrTagScope
+	}
+	return tag, nil
+}
+
+func getTaskTags(q queryer, taskID string) ([]models.Tag, error) {
+	rows, err := q.Query(`
+		SELECT tags.id, tags.name, tags.color, tags.user_id, tags.workspace_id
+		FROM tags JOIN task_tags ON task_tags.tag_id = tags.id
+		WHERE task_tags.task_id = $1
+		ORDER BY LOWER(tags.name)`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
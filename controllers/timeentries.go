@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrTimeEntryNotFound = newError(KindNotFound, "time_entry_not_found", "time entry not found")
+	ErrTimerRunning      = newError(KindConflict, "timer_running", "a timer is already running, stop it first")
+	ErrNoTimerRunning    = newError(KindNotFound, "no_timer_running", "no timer is running")
+	ErrInvalidTimeEntry  = newError(KindValidation, "invalid_time_entry", "time entry needs a start time and either an end time after it or a positive duration")
+	ErrInvalidGroupBy    = newError(KindValidation, "invalid_group_by", "groupBy must be project, user, task or date")
+)
+
+// MaxTimeEntryNoteLength is the longest note of a time entry, as allowed by
+// its column
+const MaxTimeEntryNoteLength = 255
+
+// nowUTC is the current time in UTC, as stored in the time_entries table.
+const nowUTC = "(NOW() AT TIME ZONE 'UTC')"
+
+// durationExpr computes the seconds spent on a time entry, counting running
+// timers up to now.
+const durationExpr = "EXTRACT(EPOCH FROM (COALESCE(time_entries.ended_at, " + nowUTC + ") - time_entries.started_at))::BIGINT"
+
+const timeEntryColumns = "id, task_id, user_id, started_at, ended_at, " + durationExpr + ", note, manual"
+
+func scanTimeEntry(row scanner) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	var endedAt sql.NullTime
+	err := row.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &entry.StartedAt, &endedAt, &entry.DurationSeconds, &entry.Note, &entry.Manual)
+	if endedAt.Valid {
+		entry.EndedAt = &endedAt.Time
+	}
+	return entry, err
+}
+
+// validateNote checks the note of a time entry.
+func validateNote(note string) error {
+	if utf8.RuneCountInString(note) > MaxTimeEntryNoteLength {
+		return fieldError("note", fmt.Sprintf("must be at most %d characters", MaxTimeEntryNoteLength))
+	}
+	return nil
+}
+
+func getTimeSpent(q queryer, taskID string) (int64, error) {
+	var seconds int64
+	err := q.QueryRow("SELECT COALESCE(SUM("+durationExpr+"), 0) FROM time_entries WHERE task_id = $1", taskID).Scan(&seconds)
+	return seconds, err
+}
+
+// @Summary Start a timer
+// @Description Starts tracking the time the calling user spends on a task
+// @ID start-timer
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TimeEntry "Running timer"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/timer [post]
+func StartTimer(ctx context.Context, taskID, note string) (models.TimeEntry, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+	err = validateNote(note)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	err = checkTaskExists(config.DB, taskID)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	row := config.DB.QueryRow("INSERT INTO time_entries (id, task_id, user_id, started_at, note) VALUES ($1, $2, $3, "+nowUTC+", $4) RETURNING "+timeEntryColumns,
+		models.NewID(), taskID, userID, note)
+	entry, err := scanTimeEntry(row)
+	if isUniqueViolation(err) {
+		return models.TimeEntry{}, ErrTimerRunning
+	}
+	return entry, err
+}
+
+// @Summary Stop the running timer
+// @Description Stops the running timer of the calling user
+// @ID stop-timer
+// @Produce json
+// @Success 200 {object} models.TimeEntry "Stopped time entry"
+// @Failure 500 {object} string "Internal server error"
+// @Router /timers/current [delete]
+func StopTimer(ctx context.Context) (models.TimeEntry, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	row := config.DB.QueryRow("UPDATE time_entries SET ended_at = "+nowUTC+" WHERE user_id = $1 AND ended_at IS NULL RETURNING "+timeEntryColumns,
+		userID)
+	entry, err := scanTimeEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TimeEntry{}, ErrNoTimerRunning
+	}
+	return entry, err
+}
+
+// @Summary Get the running timer
+// @Description Retrieves the running timer of the calling user
+// @ID get-running-timer
+// @Produce json
+// @Success 200 {object} models.TimeEntry "Running timer"
+// @Failure 500 {object} string "Internal server error"
+// @Router /timers/current [get]
+func GetRunningTimer(ctx context.Context) (models.TimeEntry, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	row := config.DB.QueryRow("SELECT "+timeEntryColumns+" FROM time_entries WHERE user_id = $1 AND ended_at IS NULL", userID)
+	entry, err := scanTimeEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TimeEntry{}, ErrNoTimerRunning
+	}
+	return entry, err
+}
+
+// @Summary Add a time entry
+// @Description Records time spent on a task without a timer, from a start time and either an end time or a duration
+// @ID create-time-entry
+// @Accept json
+// @Produce json
+// @Param entry body models.TimeEntry true "Time entry details"
+// @Success 200 {object} models.TimeEntry "Successfully created time entry"
+// @Failure 500 {object} string "Internal server error"
+// @Router /timeEntries [post]
+func CreateTimeEntry(ctx context.Context, entry models.TimeEntry) (models.TimeEntry, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	if entry.StartedAt.IsZero() {
+		return models.TimeEntry{}, ErrInvalidTimeEntry
+	}
+	if entry.EndedAt == nil {
+		if entry.DurationSeconds <= 0 {
+			return models.TimeEntry{}, ErrInvalidTimeEntry
+		}
+		endedAt := entry.StartedAt.Add(time.Duration(entry.DurationSeconds) * time.Second)
+		entry.EndedAt = &endedAt
+	}
+	if !entry.EndedAt.After(entry.StartedAt) {
+		return models.TimeEntry{}, ErrInvalidTimeEntry
+	}
+	err = validateNote(entry.Note)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	err = checkTaskExists(config.DB, entry.TaskID)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	row := config.DB.QueryRow(`
+		INSERT INTO time_entries (id, task_id, user_id, started_at, ended_at, note, manual)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE) RETURNING `+timeEntryColumns,
+		models.NewID(), entry.TaskID, userID, entry.StartedAt.UTC(), entry.EndedAt.UTC(), entry.Note)
+	return scanTimeEntry(row)
+}
+
+// @Summary Get the time entries of a task
+// @Description Retrieves the time entries of a task, most recent first
+// @ID get-time-entries
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.TimeEntry "Successfully retrieved time entries"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/{id}/timeEntries [get]
+func GetTimeEntries(taskID string) ([]models.TimeEntry, error) {
+	rows, err := config.DB.Query("SELECT "+timeEntryColumns+" FROM time_entries WHERE task_id = $1 ORDER BY started_at DESC", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// @Summary Delete a time entry
+// @Description Removes a time entry of the calling user
+// @ID delete-time-entry
+// @Produce json
+// @Param id path string true "Time entry ID"
+// @Success 200 {string} string "Successfully deleted time entry"
+// @Failure 500 {object} string "Internal server error"
+// @Router /timeEntries/{id} [delete]
+func DeleteTimeEntry(ctx context.Context, id string) error {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := config.DB.Exec("DELETE FROM time_entries WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+// @Summary Get a time report
+// @Description Sums the time entries of the calling user started in a date range by project, user, task or date
+// @ID get-time-report
+// @Produce json
+// @Param groupBy query string true "project, user, task or date"
+// @Success 200 {array} models.TimeReportRow "Time spent per group"
+// @Failure 500 {object} string "Internal server error"
+// @Router /reports/time [get]
+func GetTimeReport(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var key, name string
+	switch filter.GroupBy {
+	case models.GroupByProject:
+		key, name = "COALESCE(tasks.project_id, '')", "COALESCE(MAX(projects.name), '')"
+	case models.GroupByUser:
+		key, name = "time_entries.user_id", "''"
+	case models.GroupByTask:
+		key, name = "time_entries.task_id", "MAX(tasks.title)"
+	case models.GroupByDate:
+		key, name = "TO_CHAR(time_entries.started_at, 'YYYY-MM-DD')", "''"
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+
+	// Projects of other users are left unnamed
+	conds := []string{"time_entries.user_id = $1", "time_entries.started_at >= $2", "time_entries.started_at < $3", "tasks.deleted_at IS NULL"}
+	args := []interface{}{userID, filter.From.UTC(), filter.To.UTC()}
+	if filter.ProjectID != "" {
+		args = append(args, filter.ProjectID)
+		conds = append(conds, fmt.Sprintf("tasks.project_id = $%d", len(args)))
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+key+`, `+name+`, SUM(`+durationExpr+`), COUNT(*)
+		FROM time_entries
+		JOIN tasks ON tasks.id = time_entries.task_id
+		LEFT JOIN projects ON projects.id = tasks.project_id AND projects.user_id = $1`+
+		where(conds)+`
+		GROUP BY 1
+		ORDER BY 1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []models.TimeReportRow
+	for rows.Next() {
+		var row models.TimeReportRow
+		err := rows.Scan(&row.Key, &row.Name, &row.Seconds, &row.Entries)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, row)
+	}
+
+	return report, rows.Err()
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrNotInTrash    = newError(KindNotFound, "task_not_in_trash", "task not found in the trash")
+	ErrParentInTrash = newError(KindConflict, "parent_in_trash", "the parent task is in the trash, restore it first")
+)
+
+// @Summary Get the trash
+// @Description Lists the tasks in the trash, most recently deleted first
+// @ID get-trash
+// @Produce json
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Success 200 {array} models.Task "Tasks in the trash"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/trash [get]
+func GetTrash(filter models.TaskFilter) ([]models.Task, error) {
+	conds, args := taskFilterConds(filter, nil)
+	// Swap the condition hiding the trash for its opposite
+	conds[0] = "deleted_at IS NOT NULL"
+	return queryTasks(config.DB, "SELECT "+taskColumns+" FROM tasks"+where(conds)+" ORDER BY deleted_at DESC, id", args...)
+}
+
+// @Summary Restore a task
+// @Description Moves a task out of the trash, with the subtasks deleted along with it
+// @ID restore-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task "Restored task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/trash/{id}/restore [post]
+func RestoreTask(ctx context.Context, id string) (models.Task, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return models.Task{}, err
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	var parentInTrash bool
+	err = tx.QueryRow(`
+		SELECT tasks.deleted_at, parents.deleted_at IS NOT NULL
+		FROM tasks LEFT JOIN tasks AS parents ON parents.id = tasks.parent_id
+		WHERE tasks.id = $1 AND tasks.deleted_at IS NOT NULL
+		FOR UPDATE OF tasks`, id).Scan(&deletedAt, &parentInTrash)
+	if err != nil {
+		return models.Task{}, notFound(err, ErrNotInTrash)
+	}
+	if parentInTrash {
+		return models.Task{}, ErrParentInTrash
+	}
+
+	// Subtasks deleted on their own before stay in the trash
+	rows, err := tx.Query("UPDATE tasks SET deleted_at = NULL WHERE id IN ("+fullSubtreeQuery+") AND deleted_at = $2 RETURNING id", id, deletedAt)
+	if err != nil {
+		return models.Task{}, err
+	}
+	restored, err := scanIDs(rows)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	for _, taskID := range restored {
+		err = recordActivity(tx, taskID, auth.UserID(ctx), models.ActivityRestored, "", "", "")
+		if err != nil {
+			return models.Task{}, err
+		}
+		err = recordAudit(ctx, tx, models.AuditRestore, models.AuditEntityTask, taskID, taskID,
+			auditedDeletion{DeletedAt: &deletedAt}, auditedDeletion{})
+		if err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	// Dependencies may have changed while the tasks were in the trash, and
+	// the restored tasks block their dependents again
+	err = refreshBlockedStatus(ctx, tx, restored)
+	if err != nil {
+		return models.Task{}, err
+	}
+	err = refreshDependents(ctx, tx, restored)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return GetTask(id, DefaultSubtaskDepth)
+}
+
+// @Summary Purge a task
+// @Description Permanently deletes a task in the trash, with its subtasks, reminders and attachments
+// @ID purge-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {string} string "Successfully purged task"
+// @Failure 500 {object} string "Internal server error"
+// @Router /tasks/trash/{id} [delete]
+func PurgeTask(ctx context.Context, id string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL)", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to find task: %v", err)
+	}
+	if !exists {
+		return ErrNotInTrash
+	}
+
+	blobs, err := purgeTask(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	deleteBlobs(context.Background(), blobs)
+	return nil
+}
+
+// PurgeTrash permanently deletes the tasks that went to the trash before
+// the given time, and returns how many trees of tasks were purged. Deletion
+// times are stored in UTC.
+func PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	// Subtasks are purged with their parent when it is in the trash too
+	rows, err := config.DB.Query(`
+		SELECT id FROM tasks
+		WHERE deleted_at < $1 AND NOT EXISTS (
+			SELECT 1 FROM tasks AS parents WHERE parents.id = tasks.parent_id AND parents.deleted_at IS NOT NULL
+		)`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired tasks: %v", err)
+	}
+	expired, err := scanIDs(rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired tasks: %v", err)
+	}
+
+	purged := 0
+	for _, id := range expired {
+		err := PurgeTask(ctx, id)
+		if errors.Is(err, ErrNotInTrash) {
+			// Restored or purged in the meantime
+			continue
+		}
+		if err != nil {
+			log.Printf("Error purging task %s: %v", id, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purgeTask deletes a task and all its descendants, recording them and
+// their reminders in the audit log. It returns the storage keys of their
+// attachments, to be deleted once the transaction is committed.
+func purgeTask(ctx context.Context, tx *sql.Tx, id string) ([]string, error) {
+	rows, err := tx.Query("SELECT "+taskColumns+" FROM tasks WHERE id IN ("+fullSubtreeQuery+") FOR UPDATE", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find purged tasks: %v", err)
+	}
+	var purged []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to find purged tasks: %v", err)
+		}
+		purged = append(purged, task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find purged tasks: %v", err)
+	}
+
+	for i := range purged {
+		purged[i].Reminders, err = getReminders(tx, purged[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find purged reminders: %v", err)
+		}
+		err = auditReminders(ctx, tx, purged[i].ID, purged[i].Reminders, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = auditTask(ctx, tx, models.AuditPurge, &purged[i], nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Attachment rows go with the cascade, their content is removed once committed
+	rows, err = tx.Query("SELECT storage_key FROM attachments WHERE task_id IN ("+fullSubtreeQuery+")", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attachments: %v", err)
+	}
+	blobs, err := scanIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attachments: %v", err)
+	}
+
+	// Delete associated reminders, including the ones of subtasks
+	_, err = tx.Exec("DELETE FROM reminders WHERE task_id IN ("+fullSubtreeQuery+")", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete associated reminders: %v", err)
+	}
+
+	// Delete task, subtasks are removed by the cascade
+	_, err = tx.Exec("DELETE FROM tasks WHERE id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete task: %v", err)
+	}
+
+	return blobs, nil
+}
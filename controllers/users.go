@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+var (
+	ErrUserNotFound    = newError(KindNotFound, "user_not_found", "user not found")
+	ErrInvalidUsername = newError(KindValidation, "invalid_username", "username must be 1 to 50 letters, digits, '_', '-' or '.'")
+	ErrUsernameTaken   = newError(KindConflict, "username_taken", "username is already taken")
+	ErrInvalidTimeZone = newError(KindValidation, "invalid_time_zone", "time zone must be an IANA time zone such as Europe/Paris")
+	ErrUserForbidden   = newError(KindForbidden, "user_forbidden", "users can only change themselves")
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,50}$`)
+
+const userColumns = "id, username, name, email, time_zone, created_at"
+
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.Name, &user.Email, &user.TimeZone, &user.CreatedAt)
+	return user, err
+}
+
+func scanUsers(rows *sql.Rows) ([]models.User, error) {
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// @Summary Create a new user
+// @Description Adds a new user to the database
+// @ID create-user
+// @Accept json
+// @Produce json
+// @Param user body models.User true "User details"
+// @Success 200 {object} models.User "Successfully created user"
+// @Failure 500 {object} string "Internal server error"
+// @Router /users [post]
+func CreateUser(user models.User) (models.User, error) {
+	user.Username = strings.TrimSpace(user.Username)
+	if !usernamePattern.MatchString(user.Username) {
+		return models.User{}, ErrInvalidUsername
+	}
+	_, err := loadLocation(user.TimeZone)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.ID = models.NewID()
+
+	row := config.DB.QueryRow("INSERT INTO users (id, username, name, email, time_zone) VALUES ($1, $2, $3, $4, $5) RETURNING "+userColumns,
+		user.ID, user.Username, user.Name, user.Email, user.TimeZone)
+	user, err = scanUser(row)
+	if isUniqueViolation(err) {
+		return models.User{}, ErrUsernameTaken
+	}
+	return user, err
+}
+
+// @Summary Update a user by ID
+// @Description Updates the username, name, email and time zone of the calling user
+// @ID update-user
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body models.User true "Updated user details"
+// @Success 200 {object} models.User "Successfully updated user"
+// @Failure 500 {object} string "Internal server error"
+// @Router /users/{id} [put]
+func UpdateUser(ctx context.Context, id string, updatedUser models.User) (models.User, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	if id != userID {
+		return models.User{}, ErrUserForbidden
+	}
+	updatedUser.Username = strings.TrimSpace(updatedUser.Username)
+	if !usernamePattern.MatchString(updatedUser.Username) {
+		return models.User{}, ErrInvalidUsername
+	}
+	_, err = loadLocation(updatedUser.TimeZone)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	row := config.DB.QueryRow("UPDATE users SET username = $1, name = $2, email = $3, time_zone = $4 WHERE id = $5 RETURNING "+userColumns,
+		updatedUser.Username, updatedUser.Name, updatedUser.Email, updatedUser.TimeZone, id)
+	user, err := scanUser(row)
+	if isUniqueViolation(err) {
+		return models.User{}, ErrUsernameTaken
+	}
+	return user, notFound(err, ErrUserNotFound)
+}
+
+// @Summary Get a user by ID
+// @Description Retrieves a user from the database by ID
+// @ID get-user
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User "Successfully retrieved user"
+// @Failure 404 {object} string "User not found"
+// @Router /users/{id} [get]
+func GetUser(id string) (models.User, error) {
+	user, err := scanUser(config.DB.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	return user, notFound(err, ErrUserNotFound)
+}
+
+// @Summary Get all users
+// @Description Retrieves all users ordered by username
+// @ID get-all-users
+// @Produce json
+// @Success 200 {array} models.User "Successfully retrieved users"
+// @Failure 500 {object} string "Internal server error"
+// @Router /users [get]
+func GetAllUsers() ([]models.User, error) {
+	rows, err := config.DB.Query("SELECT " + userColumns + " FROM users ORDER BY LOWER(username)")
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+// getUsersByUsername returns the users with the given usernames, ignoring
+// case. Unknown usernames are skipped.
+func getUsersByUsername(q queryer, usernames []string) ([]models.User, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.Query("SELECT "+userColumns+" FROM users WHERE LOWER(username) = ANY($1)", lowerArray(usernames))
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+// loadLocation returns the time zone with the given IANA name, UTC when
+// empty. The time zone of the server, "Local", is not one.
+func loadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
+
+// lowerArray returns the lower-cased values as a Postgres array argument.
+func lowerArray(values []string) interface{} {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return pq.Array(lowered)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vikash-parashar/task-manager-2/ical"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Longest values accepted for task fields, as allowed by their columns
+const (
+	MaxTitleLength         = 255
+	MaxDescriptionLength   = 255
+	MaxPriorityLength      = 50
+	MaxNotifyStatusLength  = 50
+	MaxNotifyMessageLength = 255
+	MaxRecurrenceLength    = 255
+)
+
+// FieldError tells why a field of a request is invalid
+type FieldError struct {
+	Field   string `json:"field"` // JSON path of the field, e.g. reminders[0].date
+	Message string `json:"message"`
+}
+
+// ValidationError lists the invalid fields of a request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return "invalid fields: " + strings.Join(messages, "; ")
+}
+
+// Add records an invalid field.
+func (e *ValidationError) Add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e if any field is invalid, and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// fieldError returns a *ValidationError for a single invalid field.
+func fieldError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// validateTask checks the fields of a task before it is saved, and stores
+// reminder dates in UTC. It returns a *ValidationError listing every
+// invalid field.
+func validateTask(task *models.Task) error {
+	verr := &ValidationError{}
+
+	if strings.TrimSpace(task.Title) == "" {
+		verr.Add("title", "is required")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"title", task.Title, MaxTitleLength},
+		{"description", task.Description, MaxDescriptionLength},
+		{"priority", task.Priority, MaxPriorityLength},
+		{"notifyStatus", task.NotifyStatus, MaxNotifyStatusLength},
+		{"notifyMessage", task.NotifyMessage, MaxNotifyMessageLength},
+	} {
+		if utf8.RuneCountInString(field.value) > field.max {
+			verr.Add(field.name, "must be at most %d characters", field.max)
+		}
+	}
+
+	if task.DueDateTime.IsZero() {
+		verr.Add("dueDateTime", "is required")
+	}
+
+	switch task.NotifyMethod {
+	case "", models.NotifyMethodEmail, models.NotifyMethodPush:
+	default:
+		verr.Add("notifyMethod", "must be %q, %q or empty", models.NotifyMethodEmail, models.NotifyMethodPush)
+	}
+
+	if task.EstimateSeconds < 0 {
+		verr.Add("estimateSeconds", "must not be negative")
+	}
+
+	if task.Recurrence != "" {
+		rule, err := ical.Recurrence(task.Recurrence)
+		switch {
+		case err != nil:
+			verr.Add("recurrence", "%s", err)
+		case len(rule) > MaxRecurrenceLength:
+			verr.Add("recurrence", "must be at most %d characters", MaxRecurrenceLength)
+		default:
+			task.Recurrence = rule
+		}
+	}
+
+	seen := make(map[string]bool, len(task.Reminders))
+	for i, reminder := range task.Reminders {
+		field := fmt.Sprintf("reminders[%d].date", i)
+		date, _, err := parseReminderDate(reminder.Date)
+		if err != nil {
+			verr.Add(field, "must be a date in RFC3339 format")
+			continue
+		}
+		if seen[date] {
+			verr.Add(field, "duplicates another reminder")
+		}
+		seen[date] = true
+		task.Reminders[i].Date = date
+	}
+
+	return verr.Err()
+}
@@ -19,303 +19,4423 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/createTables": {
-            "post": {
-                "description": "Creates the Task and Reminder tables in the database",
-                "summary": "Create tables",
-                "operationId": "create-tables",
+        "/attachments/{id}": {
+            "get": {
+                "description": "Retrieves the content of an attachment. Its SHA-256 is sent in the X-Checksum-SHA256 header.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "summary": "Download an attachment",
+                "operationId": "download-attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Tables created successfully",
+                        "description": "Attachment content",
                         "schema": {
-                            "type": "string"
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
-            }
-        },
-        "/init": {
-            "get": {
-                "description": "Establishes a connection to the PostgreSQL database",
+            },
+            "delete": {
+                "description": "Deletes an attachment and its content",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Initialize the database connection",
-                "operationId": "init-db",
+                "summary": "Delete an attachment",
+                "operationId": "delete-attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Successfully connected to the database",
+                        "description": "Successfully deleted attachment",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/create": {
-            "post": {
-                "description": "Creates a new task with the specified details",
+        "/audit": {
+            "get": {
+                "description": "Lists the recorded mutations of the tasks of the calling user and of their reminders, tags, checklist items and attachments, most recent first. Pass the ID of the last entry as before to get the next page.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Create a new task",
-                "operationId": "create-task",
+                "summary": "Query the audit log",
+                "operationId": "get-audit-log",
                 "parameters": [
                     {
-                        "description": "models.Task details",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Task"
-                        }
+                        "type": "string",
+                        "description": "ID of the user who made the change",
+                        "name": "actorID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create, update, delete, complete or reopen",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "task, reminder, tag, checklist_item or attachment",
+                        "name": "entityType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the task, reminder, tag, checklist item or attachment",
+                        "name": "entityID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ID, including changes to its reminders, tags, checklist items and attachments",
+                        "name": "taskID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the request that made the change",
+                        "name": "requestID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, in RFC3339 format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time (exclusive), in RFC3339 format",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries older than this entry ID",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries, 100 by default",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created task",
+                    "200": {
+                        "description": "Audit entries",
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/delete/{id}": {
-            "delete": {
-                "description": "Deletes a task by its unique identifier",
+        "/calendar/feed": {
+            "post": {
+                "description": "Creates the secret URL of an iCalendar feed of the tasks of the calling user with a due date, for calendar apps\nto subscribe to. Creating it again replaces the URL, so the previous one stops working.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete a task by ID",
-                "operationId": "delete-task",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "models.Task ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Create the calendar feed of the user",
+                "operationId": "create-calendar-feed",
                 "responses": {
-                    "200": {
-                        "description": "Successfully deleted task",
+                    "201": {
+                        "description": "Created feed, with its URL",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CalendarFeed"
                         }
                     },
-                    "404": {
-                        "description": "models.Task not found",
+                    "401": {
+                        "description": "Missing X-User-ID header",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
-            }
-        },
-        "/tasks/dueReminders": {
-            "get": {
-                "description": "Retrieves a list of tasks with due reminders",
+            },
+            "delete": {
+                "description": "Deletes the calendar feed of the calling user, so its URL stops working",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get tasks with due reminders",
-                "operationId": "get-tasks-with-due-reminders",
+                "summary": "Delete the calendar feed of the user",
+                "operationId": "delete-calendar-feed",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved tasks with due reminders",
+                        "description": "Successfully deleted calendar feed",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Task"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Calendar feed not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/get/{id}": {
+        "/calendar/{token}.ics": {
             "get": {
-                "description": "Retrieves a task by its unique identifier",
+                "description": "Retrieves the tasks with a due date of the user of a feed as iCalendar data, with an alarm for each reminder, an RRULE for recurring tasks and the dates\nin the time zone of the user. The token in the URL identifies the feed, so no X-User-ID header is needed.\nThe ETag header identifies the content; when If-None-Match lists it the feed is unchanged and not sent again.",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
-                "summary": "Get a task by ID",
-                "operationId": "get-task",
+                "summary": "Get a calendar feed",
+                "operationId": "get-calendar-feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "models.Task ID",
-                        "name": "id",
+                        "description": "Secret token of the feed",
+                        "name": "token",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "VEVENT (the default) to show tasks in the calendar, VTODO in the to-do list, or VEVENT,VTODO",
+                        "name": "components",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the feed held by the client",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved task",
+                        "description": "iCalendar data",
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "models.Task not found",
+                    "304": {
+                        "description": "Feed not modified",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Calendar feed not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/getAll": {
-            "get": {
-                "description": "Retrieves a list of all tasks",
+        "/checklistItems": {
+            "post": {
+                "description": "Appends an item to the checklist of a task",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get all tasks",
-                "operationId": "get-all-tasks",
+                "summary": "Add a checklist item",
+                "operationId": "create-checklist-item",
+                "parameters": [
+                    {
+                        "description": "Checklist item details",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved tasks",
+                    "201": {
+                        "description": "Successfully created checklist item",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Task"
-                            }
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/update/{id}": {
+        "/checklistItems/{id}": {
             "put": {
-                "description": "Updates a task with the specified details",
+                "description": "Updates the text and state of a checklist item",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update a task by ID",
-                "operationId": "update-task",
+                "summary": "Update a checklist item",
+                "operationId": "update-checklist-item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "models.Task ID",
+                        "description": "Checklist item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated task details",
-                        "name": "task",
+                        "description": "Updated checklist item",
+                        "name": "item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "$ref": "#/definitions/models.ChecklistItem"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated task",
+                        "description": "Successfully updated checklist item",
                         "schema": {
-                            "$ref": "#/definitions/models.Task"
+                            "$ref": "#/definitions/models.ChecklistItem"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "404": {
-                        "description": "models.Task not found",
+                        "description": "Checklist item not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.problem"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.Reminder": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "taskID": {
-                    "type": "string"
+            },
+            "delete": {
+                "description": "Removes an item from the checklist of a task",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a checklist item",
+                "operationId": "delete-checklist-item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted checklist item",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
                 }
             }
         },
-        "models.Task": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
+        "/checklistItems/{id}/toggle": {
+            "post": {
+                "description": "Flips a checklist item between done and not done",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Toggle a checklist item",
+                "operationId": "toggle-checklist-item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully toggled checklist item",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments": {
+            "post": {
+                "description": "Posts a comment on a task as the calling user. Mentioned users are notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new comment",
+                "operationId": "create-comment",
+                "parameters": [
+                    {
+                        "description": "Comment details",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created comment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "put": {
+                "description": "Edits a comment of the calling user. Newly mentioned users are notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a comment",
+                "operationId": "update-comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated comment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the comment",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a comment of the calling user. The comment is hidden, its history is kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a comment",
+                "operationId": "delete-comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted comment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the comment",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}/revisions": {
+            "get": {
+                "description": "Retrieves the previous versions of an edited comment, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the history of a comment",
+                "operationId": "get-comment-revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved revisions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CommentRevision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts": {
+            "get": {
+                "description": "Retrieves the contacts of the calling user by name",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all contacts",
+                "operationId": "get-all-contacts",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved contacts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Contact"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a contact for the calling user. Tasks refer to it with their contactID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new contact",
+                "operationId": "create-contact",
+                "parameters": [
+                    {
+                        "description": "Contact details",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Contact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created contact",
+                        "schema": {
+                            "$ref": "#/definitions/models.Contact"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/{id}": {
+            "get": {
+                "description": "Retrieves a contact of the calling user by its unique identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a contact by ID",
+                "operationId": "get-contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved contact",
+                        "schema": {
+                            "$ref": "#/definitions/models.Contact"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the name, email and phone of a contact of the calling user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a contact by ID",
+                "operationId": "update-contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated contact details",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Contact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated contact",
+                        "schema": {
+                            "$ref": "#/definitions/models.Contact"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a contact of the calling user, its tasks are kept without a contact",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a contact by ID",
+                "operationId": "delete-contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted contact",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/createTables": {
+            "post": {
+                "description": "Creates the Task and Reminder tables in the database",
+                "summary": "Create tables",
+                "operationId": "create-tables",
+                "responses": {
+                    "200": {
+                        "description": "Tables created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Streams the changes of the tasks of the user as Server-Sent Events: task.created, task.updated,\ntask.deleted and reminder.fired. Each event has an ID; a client reconnecting with the Last-Event-ID\nheader gets the events it missed, or a stream.reset event if they are no longer buffered.\nComments are sent as heartbeats while the stream is idle.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Stream task events",
+                "operationId": "stream-events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "401": {
+                        "description": "Anonymous request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Queries tasks with their reminders, contact, comments and tags in one round trip, or runs a mutation.\nQueries deeper than GRAPHQL_MAX_DEPTH or more complex than GRAPHQL_MAX_COMPLEXITY are rejected, see the readme.\nErrors of the query are reported in the errors of the response, with their code in the extensions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Run a GraphQL query",
+                "operationId": "graphql",
+                "parameters": [
+                    {
+                        "description": "Query, operation name and variables",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.graphqlRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data and errors of the query",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/init": {
+            "get": {
+                "description": "Establishes a connection to the PostgreSQL database",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Initialize the database connection",
+                "operationId": "init-db",
+                "responses": {
+                    "200": {
+                        "description": "Successfully connected to the database",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects": {
+            "get": {
+                "description": "Retrieves the projects of the calling user in their saved order",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all projects",
+                "operationId": "get-all-projects",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "List archived projects instead of active ones",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved projects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Project"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a project for the calling user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new project",
+                "operationId": "create-project",
+                "parameters": [
+                    {
+                        "description": "Project details",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/order": {
+            "put": {
+                "description": "Sets the order of the calling user's projects",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Reorder projects",
+                "operationId": "reorder-projects",
+                "parameters": [
+                    {
+                        "description": "Project IDs in their new order",
+                        "name": "projects",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.reorderProjectsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Projects in their new order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Project"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}": {
+            "get": {
+                "description": "Retrieves a project of the calling user by its unique identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a project by ID",
+                "operationId": "get-project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the name, description and task defaults of a project of the calling user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a project by ID",
+                "operationId": "update-project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated project details",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a project of the calling user, its tasks are kept without a project",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a project by ID",
+                "operationId": "delete-project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted project",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/archive": {
+            "post": {
+                "description": "Hides a project from the default project listing",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Archive a project",
+                "operationId": "archive-project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully archived project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/unarchive": {
+            "post": {
+                "description": "Brings an archived project back to the default project listing",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Unarchive a project",
+                "operationId": "unarchive-project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully unarchived project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/time": {
+            "get": {
+                "description": "Sums the time entries of the calling user started in a date range by project, user, task or date. The range defaults to the last 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a time report",
+                "operationId": "get-time-report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project, user, task or date",
+                        "name": "groupBy",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, as YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, as YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Time spent per group",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimeReportRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Retrieves the tags of the workspace of the request, or the private tags of the calling user",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all tags",
+                "operationId": "get-all-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID, which must be the one of the X-Workspace-ID header",
+                        "name": "workspaceID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved tags",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "workspaceID is not the workspace of the request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a tag for the calling user, or for the workspace of the request (X-Workspace-ID header) when workspaceID is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new tag",
+                "operationId": "create-tag",
+                "parameters": [
+                    {
+                        "description": "Tag details",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created tag",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "403": {
+                        "description": "workspaceID is not the workspace of the request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "description": "Retrieves a tag by its unique identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a tag by ID",
+                "operationId": "get-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved tag",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Renames or recolors a tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a tag by ID",
+                "operationId": "update-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated tag details",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated tag",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a tag and detaches it from all tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a tag by ID",
+                "operationId": "delete-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted tag",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/merge": {
+            "post": {
+                "description": "Moves the tasks of the given tags onto the target tag and deletes the merged tags",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Merge tags",
+                "operationId": "merge-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs of the tags to merge into the target",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.tagIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully merged tags",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "description": "Retrieves a list of all tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all tasks",
+                "operationId": "get-all-tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag IDs",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "and (default) or or",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the owner of the tasks",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new task with the specified details. The IDs of the task and its reminders are generated by the server,\nand the Location header points to the created task. Invalid fields are listed in the errors of the problem details.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new task",
+                "operationId": "create-task",
+                "parameters": [
+                    {
+                        "description": "models.Task details",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, to safely retry it",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Idempotency-Key in progress or used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/batch": {
+            "post": {
+                "description": "Creates, updates, deletes and completes up to MAX_BATCH_OPERATIONS tasks, validated like single requests.\nIn atomic mode (the default) all operations succeed or none is applied, and the first failure is reported with its index.\nIn best effort mode each operation is applied on its own, and the result of each one holds its status and error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Apply a batch of operations to tasks",
+                "operationId": "batch-tasks",
+                "parameters": [
+                    {
+                        "description": "Mode and operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result of each operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.batchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task of an atomic operation not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "An atomic operation conflicts with a task",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task of an atomic operation has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/dueReminders": {
+            "get": {
+                "description": "Retrieves a list of tasks with due reminders",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get tasks with due reminders",
+                "operationId": "get-tasks-with-due-reminders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag IDs",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "and (default) or or",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the owner of the tasks",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved tasks with due reminders",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/next": {
+            "get": {
+                "description": "Lists incomplete tasks in dependency order, so every task comes after the tasks blocking it",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the next tasks",
+                "operationId": "get-next-tasks",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only list tasks that are not blocked",
+                        "name": "readyOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag IDs",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "and (default) or or",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the owner of the tasks",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks in dependency order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/quickAdd": {
+            "post": {
+                "description": "Parses a task written as free text, such as \"Call John at 2:30 pm on Monday !high #work remind me 15m before\", into its title,\ndue date, priority, tags, contact and reminders, in the time zone of the request or else of the user, and creates it.\nWith dryRun the task is only parsed, so that it can be confirmed, and the warnings list what would fail its creation.\nThe tokens break down how each part of the text was understood.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a task from free text",
+                "operationId": "quick-add-task",
+                "parameters": [
+                    {
+                        "description": "Text of the task",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, to safely retry it",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parsed task, on a dry run",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Created task",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/trash": {
+            "get": {
+                "description": "Lists the tasks in the trash, most recently deleted first. They are purged after the retention period.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the trash",
+                "operationId": "get-trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag IDs",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "and (default) or or",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the owner of the tasks",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks in the trash",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/trash/{id}": {
+            "delete": {
+                "description": "Permanently deletes a task in the trash, with its subtasks, reminders and attachments",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Purge a task",
+                "operationId": "purge-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully purged task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/trash/{id}/restore": {
+            "post": {
+                "description": "Moves a task out of the trash, with the subtasks deleted along with it",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restore a task",
+                "operationId": "restore-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Parent task is in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "get": {
+                "description": "Retrieves a task by its unique identifier. The ETag header holds its version; when If-None-Match lists it the task is unchanged and not sent again.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a task by ID",
+                "operationId": "get-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "models.Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Levels of subtasks to include, defaults to 1",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version held by the client",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "304": {
+                        "description": "Task not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "models.Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates a task with the specified details. With If-Match, the update only happens if the task still has that ETag.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a task by ID",
+                "operationId": "update-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "models.Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated task details",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "models.Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Moves a task and its subtasks to the trash, from where they can be restored until purged",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a task by ID",
+                "operationId": "delete-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "models.Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the deletion is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted task",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "models.Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes some fields of a task with a JSON merge patch (RFC 7396, Content-Type application/merge-patch+json)\nor a JSON patch (RFC 6902, Content-Type application/json-patch+json). Reminders are addressed by index,\ne.g. /reminders/0/date, and new ones get an ID. The patch is applied atomically: if any operation fails,\nnothing changes. With If-Match, the patch only applies if the task still has that ETag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Patch a task by ID",
+                "operationId": "patch-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch or JSON patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patched task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch or task",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/activity": {
+            "get": {
+                "description": "Retrieves the comments and changes of a task in a single feed, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the activity of a task",
+                "operationId": "get-task-activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved activity",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Activity"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments": {
+            "post": {
+                "description": "Attaches a file to a task. The file is sent in the \"file\" part of a multipart form.\nIts SHA-256 can be given in the X-Checksum-SHA256 header or in a \"sha256\" part sent before the file.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Upload an attachment",
+                "operationId": "create-attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex encoded SHA-256 of the file",
+                        "name": "sha256",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully uploaded attachment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or checksum mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Attachment too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist/order": {
+            "put": {
+                "description": "Sets the order of the checklist items of a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Reorder a checklist",
+                "operationId": "reorder-checklist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Checklist item IDs in their new order",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.reorderChecklistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checklist in its new order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ChecklistItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/comments": {
+            "get": {
+                "description": "Retrieves the comments of a task, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the comments of a task",
+                "operationId": "get-comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved comments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Comment"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/complete": {
+            "post": {
+                "description": "Marks a task as completed. With cascade, its subtasks and checklist items are completed too.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Complete a task",
+                "operationId": "complete-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also complete subtasks and checklist items",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully completed task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Task is blocked",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/dependencies": {
+            "post": {
+                "description": "Declares that a task is blocked by another one. The task is blocked until the other one is completed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add a dependency",
+                "operationId": "add-dependency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the blocked task",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID of the blocking task",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.dependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task with its updated status",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Dependency would create a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a dependency between two tasks, unblocking the task if nothing else blocks it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove a dependency",
+                "operationId": "remove-dependency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the blocked task",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID of the blocking task",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.dependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task with its updated status",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/reminders": {
+            "get": {
+                "description": "Lists the reminders of a task by date",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the reminders of a task",
+                "operationId": "get-reminders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reminders of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reminder"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a reminder to a task. The date must be in the future and differ from the other reminders of the task.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add a reminder",
+                "operationId": "create-reminder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reminder date, in RFC3339 format",
+                        "name": "reminder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Reminder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created reminder",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reminder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The task already has a reminder at this date",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/reminders/{reminderID}": {
+            "put": {
+                "description": "Moves a reminder to another date. The date must be in the future and differ from the other reminders of the task.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a reminder",
+                "operationId": "update-reminder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reminder ID",
+                        "name": "reminderID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reminder date, in RFC3339 format",
+                        "name": "reminder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Reminder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated reminder",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reminder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or reminder not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The task already has a reminder at this date",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a reminder from a task",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a reminder",
+                "operationId": "delete-reminder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reminder ID",
+                        "name": "reminderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted reminder",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or reminder not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/reopen": {
+            "post": {
+                "description": "Marks a completed task as open again",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Reopen a task",
+                "operationId": "reopen-task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully reopened task",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/tags": {
+            "post": {
+                "description": "Attaches tags to a task, ignoring tags that are already attached",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Attach tags to a task",
+                "operationId": "attach-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs of the tags to attach",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.tagIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Detaches tags from a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Detach tags from a task",
+                "operationId": "detach-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs of the tags to detach",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.tagIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/timeEntries": {
+            "get": {
+                "description": "Retrieves the time entries of a task, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the time entries of a task",
+                "operationId": "get-time-entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved time entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimeEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/timer": {
+            "post": {
+                "description": "Starts tracking the time the calling user spends on a task. A user can only run one timer at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Start a timer",
+                "operationId": "start-timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "timer",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.startTimerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Running timer",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Note too long",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A timer is already running",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/timeEntries": {
+            "post": {
+                "description": "Records time the calling user spent on a task without a timer, from a start time and either an end time or a duration in seconds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add a time entry",
+                "operationId": "create-time-entry",
+                "parameters": [
+                    {
+                        "description": "Time entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeEntry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created time entry",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/timeEntries/{id}": {
+            "delete": {
+                "description": "Removes a time entry of the calling user",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a time entry",
+                "operationId": "delete-time-entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Time entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted time entry",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Time entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/timers/current": {
+            "get": {
+                "description": "Retrieves the running timer of the calling user",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the running timer",
+                "operationId": "get-running-timer",
+                "responses": {
+                    "200": {
+                        "description": "Running timer",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No timer is running",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stops the running timer of the calling user",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Stop the running timer",
+                "operationId": "stop-timer",
+                "responses": {
+                    "200": {
+                        "description": "Stopped time entry",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "The request does not identify the user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No timer is running",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Retrieves all users ordered by username",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all users",
+                "operationId": "get-all-users",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a user that can be mentioned with @username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a new user",
+                "operationId": "create-user",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Username already taken",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Retrieves a user by its unique identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a user by ID",
+                "operationId": "get-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the username, name, email and time zone of the calling user. The time zone is the one of\nthe dates of quick add and of the calendar feed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update a user by ID",
+                "operationId": "update-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, which must be the one of the X-User-ID header",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing X-User-ID header",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not the calling user",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Username already taken",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket exchanging JSON messages. Clients send commands with an ID echoed in the reply:\nsubscribe and unsubscribe with tasks and projects to follow, moveTask with taskID, projectID and an\noptional version, completeTask and reopenTask with taskID, and toggleChecklistItem with itemID.\nReplies are result or error messages, changes of followed tasks are event messages, and a reset\nmessage means events were missed. Only the tasks of the user are followed. Like every request, the\nuser is identified by the X-User-ID header, which the gateway sets for browsers from their session.",
+                "summary": "Connect a task board",
+                "operationId": "connect-board",
+                "responses": {
+                    "101": {
+                        "description": "Switching to the WebSocket protocol",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Anonymous request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.BatchResult": {
+            "type": "object",
+            "properties": {
+                "err": {},
+                "id": {
+                    "description": "Task the operation applied to",
+                    "type": "string"
+                },
+                "task": {
+                    "description": "Task after the operation, nil after a deletion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    ]
+                }
+            }
+        },
+        "controllers.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON path of the field, e.g. reminders[0].date",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.batchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Why the operation failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.problem"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Task the operation applied to",
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status the operation would have on its own",
+                    "type": "integer"
+                },
+                "task": {
+                    "description": "Task after the operation, absent after a deletion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.dependencyRequest": {
+            "type": "object",
+            "properties": {
+                "blockedByID": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.graphqlRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "handlers.problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Stable error code, e.g. task_not_found",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Invalid fields of the request",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "requestID": {
+                    "description": "ID of the request, to find it in the logs",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.reorderChecklistRequest": {
+            "type": "object",
+            "properties": {
+                "itemIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.reorderProjectsRequest": {
+            "type": "object",
+            "properties": {
+                "projectIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.startTimerRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.tagIDsRequest": {
+            "type": "object",
+            "properties": {
+                "tagIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "actorID": {
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "For ActivityComment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    ]
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "For ActivityChanged",
+                    "type": "string"
+                },
+                "newValue": {
+                    "description": "For ActivityChanged",
+                    "type": "string"
+                },
+                "oldValue": {
+                    "description": "For ActivityChanged",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Attachment": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "Hex encoded SHA-256 of the content",
+                    "type": "string"
+                },
+                "contentType": {
+                    "description": "Sniffed from the content",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "In bytes",
+                    "type": "integer"
+                },
+                "taskID": {
+                    "type": "string"
+                },
+                "uploadedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actorID": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "entityID": {
+                    "type": "string"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "taskID": {
+                    "description": "Task the entity is or belongs to",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "cascade": {
+                    "description": "Also complete subtasks and checklist items",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "Task updated, deleted or completed",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "BatchCreate, BatchUpdate, BatchDelete or BatchComplete",
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/models.Task"
+                },
+                "version": {
+                    "description": "Version the update or deletion is based on, like If-Match",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "BatchModeAtomic (default) or BatchModeBestEffort",
+                    "type": "string"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchOperation"
+                    }
+                }
+            }
+        },
+        "models.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Secret part of the URL, only known when the feed is created",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChecklistItem": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "taskID": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Comment": {
+            "type": "object",
+            "properties": {
+                "authorID": {
+                    "type": "string"
+                },
+                "body": {
+                    "description": "Markdown",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "description": "IDs of the users mentioned with @username",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "taskID": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Set once the comment has been edited",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommentRevision": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "commentID": {
+                    "type": "string"
+                },
+                "editedAt": {
+                    "description": "When this version was replaced",
+                    "type": "string"
+                },
+                "editedBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Contact": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "Owner of the contact, set from the request",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "occurredAt": {
+                    "type": "string"
+                },
+                "previousProjectID": {
+                    "description": "Set when the task moved to another project",
+                    "type": "string"
+                },
+                "projectID": {
+                    "type": "string"
+                },
+                "reminderID": {
+                    "description": "Reminder that fired",
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "Owner of the task",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the task after the change",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Project": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "defaultNotifyMethod": {
+                    "type": "string"
+                },
+                "defaultPriority": {
+                    "description": "Defaults for new tasks",
+                    "type": "string"
+                },
+                "defaultReminderOffsets": {
+                    "description": "Durations before the due date, e.g., \"15m\", \"1h\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Sort order among the user's projects",
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuickAddRequest": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "Only parse the text, to confirm the task before creating it",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "description": "IANA name such as Europe/Paris, defaults to the time zone of the user",
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuickAddResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "False on a dry run",
+                    "type": "boolean"
+                },
+                "task": {
+                    "description": "Created task, or the task that would be created on a dry run",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    ]
+                },
+                "timeZone": {
+                    "description": "Time zone the text was parsed in",
+                    "type": "string"
+                },
+                "tokens": {
+                    "description": "Parts of the text understood",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuickAddToken"
+                    }
+                },
+                "warnings": {
+                    "description": "Parts that could not be applied, e.g. an unknown contact",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.QuickAddToken": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Tag or contact the text refers to",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "QuickAddDate, QuickAddTime, ...",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "As written",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "What the text means, e.g. the date 2026-10-26, the time 14:30 or the offset 15m",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Reminder": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "e.g., \"#ff9800\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "workspaceID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Task": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Attachment"
+                    }
+                },
+                "blockedBy": {
+                    "description": "IDs of the tasks that must be completed first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "checklist": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChecklistItem"
+                    }
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "contactID": {
+                    "description": "Person the task is about",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "Set while the task is in the trash, in UTC",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
                 },
                 "dueDateTime": {
                     "type": "string"
                 },
+                "estimateSeconds": {
+                    "description": "Time tracking fields",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -331,17 +4451,137 @@ const docTemplate = `{
                     "description": "e.g., \"pending\", \"sent\", \"failed\"",
                     "type": "string"
                 },
+                "parentID": {
+                    "description": "Subtask fields",
+                    "type": "string"
+                },
                 "priority": {
                     "type": "string"
                 },
+                "progress": {
+                    "description": "Share of checklist items and subtasks done, from 0 to 1",
+                    "type": "number"
+                },
+                "projectID": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence rule of a recurring task, as an iCalendar RRULE value such as\nFREQ=WEEKLY;BYDAY=MO. The due date is the first occurrence.",
+                    "type": "string"
+                },
                 "reminders": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Reminder"
                     }
                 },
+                "status": {
+                    "description": "TaskStatusOpen, TaskStatusBlocked or TaskStatusCompleted",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Task"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "timeSpentSeconds": {
+                    "description": "Total of the time entries, including running timers",
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "Owner of the task, set from the request",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Concurrency fields",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TimeEntry": {
+            "type": "object",
+            "properties": {
+                "durationSeconds": {
+                    "description": "Up to now for a running timer",
+                    "type": "integer"
+                },
+                "endedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manual": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimeReportRow": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Project, user or task ID, or date as YYYY-MM-DD",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Project or task name",
+                    "type": "string"
+                },
+                "seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "description": "IANA name such as Europe/Paris, UTC when empty",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Handle used for @mentions",
+                    "type": "string"
                 }
             }
         }
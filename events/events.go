@@ -0,0 +1,162 @@
+// Package events streams the changes of tasks to the clients following
+// them. Changes are notified by Postgres on models.EventChannel, so every
+// instance of the server sees the same events in the same order, and kept
+// in a bounded buffer so that clients can resume after a disconnection.
+package events
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// subscriberBuffer is how many events a subscriber can lag behind before
+// it is dropped. Dropped clients reconnect and resume from the buffer.
+const subscriberBuffer = 64
+
+// Broker fans out events to the subscribers they match.
+type Broker struct {
+	mu          sync.Mutex
+	buffer      []models.Event // Ring of the latest events, oldest at next once full
+	next        int
+	full        bool
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events matched by its filter.
+type Subscription struct {
+	Events <-chan models.Event // Closed when the subscriber lags behind
+	Missed []models.Event      // Buffered events after the one resumed from
+	Lost   bool                // The event resumed from is no longer buffered, events may have been missed
+
+	broker *Broker
+	match  func(models.Event) bool
+	events chan models.Event
+}
+
+// NewBroker returns a broker keeping the latest bufferSize events.
+func NewBroker(bufferSize int) *Broker {
+	return &Broker{
+		buffer:      make([]models.Event, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish buffers an event and sends it to the subscribers it matches.
+func (b *Broker) Publish(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.buffer) > 0 {
+		b.buffer[b.next] = event
+		b.next = (b.next + 1) % len(b.buffer)
+		b.full = b.full || b.next == 0
+	}
+
+	for sub := range b.subscribers {
+		if !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Too slow, let it resume from the buffer
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe returns a subscription to the events matched by match, which
+// is called with the broker locked and must not block. A non-zero
+// lastEventID resumes after that event, with the buffered events that
+// followed it in Missed.
+func (b *Broker) Subscribe(match func(models.Event) bool, lastEventID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan models.Event, subscriberBuffer)
+	sub := &Subscription{Events: events, broker: b, match: match, events: events}
+	if lastEventID != 0 {
+		sub.Missed, sub.Lost = b.since(match, lastEventID)
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// since returns the buffered events matched by match following lastEventID, and
+// whether lastEventID is no longer buffered. Events are compared by
+// position, as they are buffered in the order transactions committed,
+// which their IDs may not follow.
+func (b *Broker) since(match func(models.Event) bool, lastEventID int64) ([]models.Event, bool) {
+	var ordered []models.Event
+	if b.full {
+		ordered = append(ordered, b.buffer[b.next:]...)
+	}
+	ordered = append(ordered, b.buffer[:b.next]...)
+
+	for i, event := range ordered {
+		if event.ID != lastEventID {
+			continue
+		}
+		var missed []models.Event
+		for _, event := range ordered[i+1:] {
+			if match(event) {
+				missed = append(missed, event)
+			}
+		}
+		return missed, false
+	}
+	return nil, true
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.unsubscribe(s)
+}
+
+func (b *Broker) unsubscribe(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Listen publishes the events notified on models.EventChannel of the
+// database at connStr. It reconnects on its own and never returns.
+func (b *Broker) Listen(connStr string) {
+	listener := pq.NewListener(connStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("Event listener:", err)
+		}
+	})
+	err := listener.Listen(models.EventChannel)
+	if err != nil {
+		log.Println("Error listening for events:", err)
+	}
+
+	for {
+		select {
+		case notification := <-listener.Notify:
+			// nil after a reconnection, notifications sent meanwhile are lost
+			if notification == nil {
+				continue
+			}
+			var event models.Event
+			err := json.Unmarshal([]byte(notification.Extra), &event)
+			if err != nil {
+				log.Println("Error decoding event:", err)
+				continue
+			}
+			b.Publish(event)
+		case <-time.After(90 * time.Second):
+			// Check the connection while idle
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// publish publishes events with the given IDs, in order.
+func publish(b *Broker, ids ...int64) {
+	for _, id := range ids {
+		b.Publish(models.Event{ID: id, Type: models.EventTaskUpdated})
+	}
+}
+
+// ids returns the IDs of events.
+func ids(events []models.Event) []int64 {
+	var ids []int64
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func all(models.Event) bool { return true }
+
+func TestResume(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		bufferSize  int
+		published   []int64
+		match       func(models.Event) bool
+		lastEventID int64
+		missed      []int64
+		lost        bool
+	}{
+		{"buffer not full", 4, []int64{1, 2, 3}, all, 1, []int64{2, 3}, false},
+		{"up to date", 4, []int64{1, 2, 3}, all, 3, nil, false},
+		{"buffer just full", 4, []int64{1, 2, 3, 4}, all, 1, []int64{2, 3, 4}, false},
+		{"wrapped around", 4, []int64{1, 2, 3, 4, 5, 6}, all, 3, []int64{4, 5, 6}, false},
+		{"wrapped around, newest", 4, []int64{1, 2, 3, 4, 5, 6}, all, 6, nil, false},
+		{"wrapped around twice", 4, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9}, all, 7, []int64{8, 9}, false},
+		{"evicted", 4, []int64{1, 2, 3, 4, 5, 6}, all, 2, nil, true},
+		{"unknown", 4, []int64{1, 2, 3}, all, 42, nil, true},
+		{"no buffer", 0, []int64{1, 2, 3}, all, 2, nil, true},
+		// Events are resumed by position, in the order they were published
+		{"out of order IDs", 4, []int64{1, 3, 2, 4}, all, 3, []int64{2, 4}, false},
+		{
+			name:        "filtered",
+			bufferSize:  8,
+			published:   []int64{1, 2, 3, 4, 5, 6},
+			match:       func(event models.Event) bool { return event.ID%2 == 0 },
+			lastEventID: 2,
+			missed:      []int64{4, 6},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker(tt.bufferSize)
+			publish(b, tt.published...)
+
+			sub := b.Subscribe(tt.match, tt.lastEventID)
+			defer sub.Close()
+			if got := ids(sub.Missed); !reflect.DeepEqual(got, tt.missed) || sub.Lost != tt.lost {
+				t.Errorf("got missed %v and lost %v, want %v and %v", got, sub.Lost, tt.missed, tt.lost)
+			}
+		})
+	}
+}
+
+func TestSubscribeWithoutLastEventID(t *testing.T) {
+	b := NewBroker(4)
+	publish(b, 1, 2)
+
+	sub := b.Subscribe(all, 0)
+	defer sub.Close()
+	if sub.Missed != nil || sub.Lost {
+		t.Errorf("got missed %v and lost %v, want neither", ids(sub.Missed), sub.Lost)
+	}
+
+	publish(b, 3)
+	if event := <-sub.Events; event.ID != 3 {
+		t.Errorf("got event %d, want 3", event.ID)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	b := NewBroker(4)
+	sub := b.Subscribe(func(event models.Event) bool { return event.TaskID == "1" }, 0)
+
+	b.Publish(models.Event{ID: 1, TaskID: "1"})
+	b.Publish(models.Event{ID: 2, TaskID: "2"})
+	b.Publish(models.Event{ID: 3, TaskID: "1"})
+	sub.Close()
+	sub.Close() // Closing again does nothing
+
+	var got []models.Event
+	for event := range sub.Events {
+		got = append(got, event)
+	}
+	if want := []int64{1, 3}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("got events %v, want %v", ids(got), want)
+	}
+
+	// Closed subscriptions no longer receive events
+	b.Publish(models.Event{ID: 4, TaskID: "1"})
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	b := NewBroker(2 * subscriberBuffer)
+	slow := b.Subscribe(all, 0)
+	fast := b.Subscribe(all, 0)
+
+	var received []models.Event
+	for id := int64(1); id <= subscriberBuffer+2; id++ {
+		publish(b, id)
+		received = append(received, <-fast.Events)
+	}
+	fast.Close()
+
+	// The slow subscriber gets the events its channel held, then is dropped
+	var got []models.Event
+	for event := range slow.Events {
+		got = append(got, event)
+	}
+	if len(got) != subscriberBuffer || got[len(got)-1].ID != subscriberBuffer {
+		t.Fatalf("got events %v, want 1 to %d", ids(got), subscriberBuffer)
+	}
+	if len(received) != subscriberBuffer+2 {
+		t.Errorf("fast subscriber got %d events, want %d", len(received), subscriberBuffer+2)
+	}
+	slow.Close() // Closing a dropped subscription does nothing
+
+	// It resumes from the buffer
+	resumed := b.Subscribe(all, got[len(got)-1].ID)
+	defer resumed.Close()
+	if want := []int64{subscriberBuffer + 1, subscriberBuffer + 2}; !reflect.DeepEqual(ids(resumed.Missed), want) || resumed.Lost {
+		t.Errorf("got missed %v and lost %v, want %v", ids(resumed.Missed), resumed.Lost, want)
+	}
+}
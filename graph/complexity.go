@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Complexity estimates the number of fields a query resolves. Every field
+// counts once, and the fields selected in a page count once per entity of
+// the page. Pages without a known size count as the largest ones.
+func Complexity(query, operationName string, variables map[string]interface{}) (int, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0, err
+	}
+
+	c := complexity{doc: doc, variables: variables, spreads: map[string]bool{}}
+	total := 0
+	for _, op := range doc.Operations {
+		if operationName != "" && op.Name != operationName {
+			continue
+		}
+		// Without an operation name, the costliest operation is the one
+		// that may run
+		if cost := c.selectionSet(op.SelectionSet); cost > total {
+			total = cost
+		}
+	}
+	return total, nil
+}
+
+// CheckComplexity returns an error reporting a query exceeding the maximum
+// complexity, or a query that cannot be parsed.
+func CheckComplexity(query, operationName string, variables map[string]interface{}, max int) *errors.QueryError {
+	cost, err := Complexity(query, operationName, variables)
+	if err != nil {
+		return &errors.QueryError{Message: err.Error(), Extensions: map[string]interface{}{"code": "invalid_query"}}
+	}
+	if cost > max {
+		return &errors.QueryError{
+			Message:    fmt.Sprintf("the query complexity is %d, the maximum is %d", cost, max),
+			Extensions: map[string]interface{}{"code": CodeTooComplex, "complexity": cost, "maxComplexity": max},
+		}
+	}
+	return nil
+}
+
+type complexity struct {
+	doc       *ast.QueryDocument
+	variables map[string]interface{}
+	spreads   map[string]bool // Fragments being expanded, to stop on cycles
+}
+
+func (c *complexity) selectionSet(set ast.SelectionSet) int {
+	cost := 0
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost += 1 + c.pageSize(selection)*c.selectionSet(selection.SelectionSet)
+		case *ast.InlineFragment:
+			cost += c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment := c.doc.Fragments.ForName(selection.Name)
+			if fragment == nil || c.spreads[selection.Name] {
+				continue
+			}
+			c.spreads[selection.Name] = true
+			cost += c.selectionSet(fragment.SelectionSet)
+			delete(c.spreads, selection.Name)
+		}
+	}
+	return cost
+}
+
+// pageSize returns the number of entities a field selects fields of, from
+// its first argument.
+func (c *complexity) pageSize(field *ast.Field) int {
+	arg := field.Arguments.ForName("first")
+	if arg == nil {
+		if _, ok := pagedFields[field.Name]; ok {
+			return defaultPageSize
+		}
+		return 1
+	}
+
+	value, err := arg.Value.Value(c.variables)
+	if err != nil {
+		return maxPageSize
+	}
+	switch n := value.(type) {
+	case int64:
+		return clampPageSize(int(n))
+	case float64:
+		return clampPageSize(int(n))
+	}
+	return maxPageSize
+}
+
+// pagedFields are the fields returning pages
+var pagedFields = map[string]struct{}{
+	"tasks":    {},
+	"contacts": {},
+}
+
+func clampPageSize(n int) int {
+	if n < 1 || n > maxPageSize {
+		return maxPageSize
+	}
+	return n
+}
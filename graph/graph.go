@@ -0,0 +1,72 @@
+// Package graph serves tasks, their reminders and contacts over GraphQL.
+// Relations are loaded in batches for all the tasks of a query, see
+// WithLoaders.
+package graph
+
+import (
+	_ "embed"
+	"errors"
+	"log"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Schema executes GraphQL queries. Contexts must carry loaders, see
+// WithLoaders.
+var Schema = graphql.MustParseSchema(schema, &Resolver{}, graphql.MaxDepth(config.GraphQLMaxDepth))
+
+// Resolver resolves the queries and mutations of the schema
+type Resolver struct{}
+
+// Error codes of failures outside the controllers
+const (
+	CodeInternal      = "internal_error"
+	CodeTooComplex    = "query_too_complex"
+	CodeInvalidCursor = "invalid_cursor"
+)
+
+// resolverError reports an error to clients with its stable code, and the
+// invalid fields of validation errors.
+type resolverError struct {
+	message string
+	code    string
+	fields  []controllers.FieldError
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions is added to the error in the response
+func (e *resolverError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["errors"] = e.fields
+	}
+	return extensions
+}
+
+// wrapError turns an error returned by the controllers into the error
+// reported to clients. Unexpected errors are logged and reported without
+// their details.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *controllers.ValidationError
+	if errors.As(err, &validationErr) {
+		return &resolverError{message: err.Error(), code: "invalid_fields", fields: validationErr.Fields}
+	}
+	if domainErr := controllers.Classify(err); domainErr != nil {
+		return &resolverError{message: err.Error(), code: domainErr.Code}
+	}
+
+	log.Printf("graphql: %v", err)
+	return &resolverError{message: "internal error", code: CodeInternal}
+}
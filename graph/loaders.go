@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/dataloader"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// loaders batch the relations requested for the tasks of a query into one
+// database query per relation. They cache what they load, so they are made
+// for each request.
+type loaders struct {
+	tasks     *dataloader.Loader
+	contacts  *dataloader.Loader
+	reminders *dataloader.Loader
+	comments  *dataloader.Loader
+	tags      *dataloader.Loader
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns a copy of ctx carrying new loaders, for the execution
+// of one query.
+func WithLoaders(ctx context.Context) context.Context {
+	// Only the contacts of the calling user are loaded
+	getContacts := func(ids []string) (map[string]models.Contact, error) {
+		return controllers.GetContactsByIDs(ctx, ids)
+	}
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		tasks:     dataloader.NewBatchedLoader(batchByID(controllers.GetTasksByIDs)),
+		contacts:  dataloader.NewBatchedLoader(batchByID(getContacts)),
+		reminders: dataloader.NewBatchedLoader(batchByID(controllers.GetRemindersByTaskIDs)),
+		comments:  dataloader.NewBatchedLoader(batchByID(controllers.GetCommentsByTaskIDs)),
+		tags:      dataloader.NewBatchedLoader(batchByID(controllers.GetTagsByTaskIDs)),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	l, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		panic("graph: context without loaders")
+	}
+	return l
+}
+
+// batchByID adapts a function loading values by ID to a batch function.
+// IDs without a value resolve to the zero value.
+func batchByID[T any](load func(ids []string) (map[string]T, error)) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		values, err := load(keys.Keys())
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: values[key.String()], Error: err}
+		}
+		return results
+	}
+}
+
+// load resolves the value of an ID with a loader.
+func load[T any](ctx context.Context, loader *dataloader.Loader, id string) (T, error) {
+	var value T
+	data, err := loader.Load(ctx, dataloader.StringKey(id))()
+	if err != nil {
+		return value, err
+	}
+	value, ok := data.(T)
+	if !ok {
+		return value, fmt.Errorf("graph: loaded %T instead of %T", data, value)
+	}
+	return value, nil
+}
+
+// loadTask returns the task with the given ID, or ErrTaskNotFound.
+func loadTask(ctx context.Context, id string) (models.Task, error) {
+	task, err := load[models.Task](ctx, loadersFrom(ctx).tasks, id)
+	if err == nil && task.ID == "" {
+		err = controllers.ErrTaskNotFound
+	}
+	return task, err
+}
+
+// loadContact returns the contact with the given ID, or
+// ErrContactNotFound.
+func loadContact(ctx context.Context, id string) (models.Contact, error) {
+	contact, err := load[models.Contact](ctx, loadersFrom(ctx).contacts, id)
+	if err == nil && contact.ID == "" {
+		err = controllers.ErrContactNotFound
+	}
+	return contact, err
+}
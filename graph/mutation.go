@@ -0,0 +1,251 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+type taskInput struct {
+	Title           string
+	Description     *string
+	Priority        *string
+	DueDateTime     *graphql.Time
+	ProjectID       *graphql.ID
+	ContactID       *graphql.ID
+	ParentID        *graphql.ID
+	EstimateSeconds *int32
+	NotifyMethod    *string
+	Recurrence      *string
+	Reminders       *[]string
+}
+
+// task returns the task to create from the input.
+func (input taskInput) task() models.Task {
+	task := models.Task{Title: input.Title}
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+	if input.DueDateTime != nil {
+		task.DueDateTime = input.DueDateTime.Time
+	}
+	if input.ProjectID != nil {
+		task.ProjectID = string(*input.ProjectID)
+	}
+	if input.ContactID != nil {
+		task.ContactID = string(*input.ContactID)
+	}
+	if input.ParentID != nil {
+		task.ParentID = string(*input.ParentID)
+	}
+	if input.EstimateSeconds != nil {
+		task.EstimateSeconds = int64(*input.EstimateSeconds)
+	}
+	if input.NotifyMethod != nil {
+		task.NotifyMethod = *input.NotifyMethod
+	}
+	if input.Recurrence != nil {
+		task.Recurrence = *input.Recurrence
+	}
+	if input.Reminders != nil {
+		task.Reminders = []models.Reminder{}
+		for _, date := range *input.Reminders {
+			task.Reminders = append(task.Reminders, models.Reminder{Date: date})
+		}
+	}
+	return task
+}
+
+type taskPatch struct {
+	Title           *string
+	Description     *string
+	Priority        *string
+	DueDateTime     *graphql.Time
+	ProjectID       *graphql.ID
+	ContactID       *graphql.ID
+	ParentID        *graphql.ID
+	EstimateSeconds *int32
+	NotifyMethod    *string
+	Recurrence      *string
+}
+
+// mergePatch returns the patch as a JSON merge patch of the task, holding
+// only the fields set.
+func (patch taskPatch) mergePatch() ([]byte, error) {
+	fields := map[string]interface{}{}
+	set := func(name string, value interface{}, ok bool) {
+		if ok {
+			fields[name] = value
+		}
+	}
+	set("title", patch.Title, patch.Title != nil)
+	set("description", patch.Description, patch.Description != nil)
+	set("priority", patch.Priority, patch.Priority != nil)
+	set("dueDateTime", patch.DueDateTime, patch.DueDateTime != nil)
+	set("projectID", patch.ProjectID, patch.ProjectID != nil)
+	set("contactID", patch.ContactID, patch.ContactID != nil)
+	set("parentID", patch.ParentID, patch.ParentID != nil)
+	set("estimateSeconds", patch.EstimateSeconds, patch.EstimateSeconds != nil)
+	set("notifyMethod", patch.NotifyMethod, patch.NotifyMethod != nil)
+	set("recurrence", patch.Recurrence, patch.Recurrence != nil)
+	return json.Marshal(fields)
+}
+
+// CreateTask creates a task owned by the calling user.
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Input taskInput }) (*taskResolver, error) {
+	task := args.Input.task()
+	task.UserID = auth.UserID(ctx)
+
+	task, err := controllers.CreateTask(ctx, task)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &taskResolver{task}, nil
+}
+
+// UpdateTask applies a patch to a task.
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Patch   taskPatch
+	Version int32
+}) (*taskResolver, error) {
+	patch, err := args.Patch.mergePatch()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	task, err := controllers.PatchTask(ctx, string(args.ID), controllers.MergePatch, patch, int64(args.Version))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &taskResolver{task}, nil
+}
+
+// DeleteTask moves a task to the trash.
+func (r *Resolver) DeleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version int32
+}) (bool, error) {
+	err := controllers.DeleteTask(ctx, string(args.ID), int64(args.Version))
+	if err != nil {
+		return false, wrapError(err)
+	}
+	return true, nil
+}
+
+// CompleteTask completes a task, and its subtasks and checklist items with
+// cascade.
+func (r *Resolver) CompleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Cascade bool
+}) (*taskResolver, error) {
+	task, err := controllers.CompleteTask(ctx, string(args.ID), args.Cascade)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &taskResolver{task}, nil
+}
+
+// ReopenTask reopens a completed task.
+func (r *Resolver) ReopenTask(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	task, err := controllers.ReopenTask(ctx, string(args.ID))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &taskResolver{task}, nil
+}
+
+// CreateReminder adds a reminder to a task.
+func (r *Resolver) CreateReminder(ctx context.Context, args struct {
+	TaskID graphql.ID
+	Date   string
+}) (*reminderResolver, error) {
+	reminder, err := controllers.CreateReminder(ctx, string(args.TaskID), models.Reminder{Date: args.Date})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &reminderResolver{reminder}, nil
+}
+
+// UpdateReminder changes the date of a reminder.
+func (r *Resolver) UpdateReminder(ctx context.Context, args struct {
+	TaskID graphql.ID
+	ID     graphql.ID
+	Date   string
+}) (*reminderResolver, error) {
+	reminder, err := controllers.UpdateReminder(ctx, string(args.TaskID), string(args.ID), models.Reminder{Date: args.Date})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &reminderResolver{reminder}, nil
+}
+
+// DeleteReminder removes a reminder from a task.
+func (r *Resolver) DeleteReminder(ctx context.Context, args struct {
+	TaskID graphql.ID
+	ID     graphql.ID
+}) (bool, error) {
+	err := controllers.DeleteReminder(ctx, string(args.TaskID), string(args.ID))
+	if err != nil {
+		return false, wrapError(err)
+	}
+	return true, nil
+}
+
+type contactInput struct {
+	Name  string
+	Email *string
+	Phone *string
+}
+
+// contact returns the contact described by the input.
+func (input contactInput) contact() models.Contact {
+	contact := models.Contact{Name: input.Name}
+	if input.Email != nil {
+		contact.Email = *input.Email
+	}
+	if input.Phone != nil {
+		contact.Phone = *input.Phone
+	}
+	return contact
+}
+
+// CreateContact creates a contact owned by the calling user.
+func (r *Resolver) CreateContact(ctx context.Context, args struct{ Input contactInput }) (*contactResolver, error) {
+	contact := args.Input.contact()
+	contact.UserID = auth.UserID(ctx)
+
+	contact, err := controllers.CreateContact(contact)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &contactResolver{contact}, nil
+}
+
+// UpdateContact replaces the details of a contact.
+func (r *Resolver) UpdateContact(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input contactInput
+}) (*contactResolver, error) {
+	contact, err := controllers.UpdateContact(ctx, string(args.ID), args.Input.contact())
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &contactResolver{contact}, nil
+}
+
+// DeleteContact removes a contact, its tasks are kept without a contact.
+func (r *Resolver) DeleteContact(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	err := controllers.DeleteContact(ctx, string(args.ID))
+	if err != nil {
+		return false, wrapError(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Page sizes of connections
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// encodeCursor returns the opaque cursor pointing after an entity.
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// decodeCursor returns the ID of the entity a cursor points after.
+func decodeCursor(cursor *string) (string, error) {
+	if cursor == nil || *cursor == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil {
+		return "", &resolverError{message: "after must be a cursor returned in a page", code: CodeInvalidCursor}
+	}
+	return string(id), nil
+}
+
+// pageSize checks the number of entities requested.
+func pageSize(first int32) (int, error) {
+	if first < 1 || first > maxPageSize {
+		return 0, &resolverError{message: fmt.Sprintf("first must be between 1 and %d", maxPageSize), code: "invalid_page_size"}
+	}
+	return int(first), nil
+}
+
+type taskFilterInput struct {
+	TagIDs    *[]graphql.ID
+	TagMode   *string
+	ProjectID *graphql.ID
+	ContactID *graphql.ID
+}
+
+// taskFilter returns the filter of a task query, like parseTaskFilter.
+func (input *taskFilterInput) taskFilter() (models.TaskFilter, error) {
+	filter := models.TaskFilter{TagMode: models.TagModeAnd}
+	if input == nil {
+		return filter, nil
+	}
+
+	if input.TagIDs != nil {
+		for _, id := range *input.TagIDs {
+			filter.TagIDs = append(filter.TagIDs, string(id))
+		}
+	}
+	if input.TagMode != nil && *input.TagMode != "" {
+		filter.TagMode = strings.ToLower(*input.TagMode)
+	}
+	if input.ProjectID != nil {
+		filter.ProjectID = string(*input.ProjectID)
+	}
+	if input.ContactID != nil {
+		filter.ContactID = string(*input.ContactID)
+	}
+
+	switch filter.TagMode {
+	case models.TagModeAnd, models.TagModeOr:
+	default:
+		return models.TaskFilter{}, &resolverError{
+			message: fmt.Sprintf("tagMode must be %q or %q", models.TagModeAnd, models.TagModeOr),
+			code:    "invalid_tag_mode",
+		}
+	}
+	return filter, nil
+}
+
+// Task returns the task with the given ID, or null if there is none.
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	task, err := loadTask(ctx, string(args.ID))
+	if err == controllers.ErrTaskNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &taskResolver{task}, nil
+}
+
+// Tasks returns a page of the tasks matching the filter.
+func (r *Resolver) Tasks(ctx context.Context, args struct {
+	Filter *taskFilterInput
+	First  int32
+	After  *string
+}) (*taskConnectionResolver, error) {
+	filter, err := args.Filter.taskFilter()
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(args.First)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more task tells whether there is a next page
+	tasks, err := controllers.GetTasksPage(filter, after, limit+1)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	connection := &taskConnectionResolver{hasNextPage: len(tasks) > limit}
+	if connection.hasNextPage {
+		tasks = tasks[:limit]
+	}
+	for _, task := range tasks {
+		connection.nodes = append(connection.nodes, &taskResolver{task})
+	}
+	return connection, nil
+}
+
+// Contact returns the contact with the given ID, or null if there is none.
+func (r *Resolver) Contact(ctx context.Context, args struct{ ID graphql.ID }) (*contactResolver, error) {
+	contact, err := loadContact(ctx, string(args.ID))
+	if err == controllers.ErrContactNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &contactResolver{contact}, nil
+}
+
+// Contacts returns a page of the contacts of the calling user.
+func (r *Resolver) Contacts(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*contactConnectionResolver, error) {
+	limit, err := pageSize(args.First)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := controllers.GetContactsPage(auth.UserID(ctx), after, limit+1)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	connection := &contactConnectionResolver{hasNextPage: len(contacts) > limit}
+	if connection.hasNextPage {
+		contacts = contacts[:limit]
+	}
+	for _, contact := range contacts {
+		connection.nodes = append(connection.nodes, &contactResolver{contact})
+	}
+	return connection, nil
+}
@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// optionalID returns nil for empty IDs, which are null in the schema.
+func optionalID(id string) *graphql.ID {
+	if id == "" {
+		return nil
+	}
+	value := graphql.ID(id)
+	return &value
+}
+
+// optionalTime returns nil for zero and missing times.
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+type taskResolver struct {
+	task models.Task
+}
+
+func (r *taskResolver) ID() graphql.ID             { return graphql.ID(r.task.ID) }
+func (r *taskResolver) Title() string              { return r.task.Title }
+func (r *taskResolver) Description() string        { return r.task.Description }
+func (r *taskResolver) Priority() string           { return r.task.Priority }
+func (r *taskResolver) DueDateTime() *graphql.Time { return optionalTime(&r.task.DueDateTime) }
+func (r *taskResolver) Status() string             { return r.task.Status }
+func (r *taskResolver) CompletedAt() *graphql.Time { return optionalTime(r.task.CompletedAt) }
+func (r *taskResolver) UserID() *graphql.ID        { return optionalID(r.task.UserID) }
+func (r *taskResolver) ProjectID() *graphql.ID     { return optionalID(r.task.ProjectID) }
+func (r *taskResolver) ParentID() *graphql.ID      { return optionalID(r.task.ParentID) }
+func (r *taskResolver) EstimateSeconds() int32     { return int32(r.task.EstimateSeconds) }
+func (r *taskResolver) NotifyMethod() string       { return r.task.NotifyMethod }
+func (r *taskResolver) NotifyStatus() string       { return r.task.NotifyStatus }
+func (r *taskResolver) Recurrence() string         { return r.task.Recurrence }
+func (r *taskResolver) Version() int32             { return int32(r.task.Version) }
+func (r *taskResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.task.UpdatedAt} }
+
+func (r *taskResolver) Reminders(ctx context.Context) ([]*reminderResolver, error) {
+	reminders, err := load[[]models.Reminder](ctx, loadersFrom(ctx).reminders, r.task.ID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return reminderResolvers(reminders), nil
+}
+
+func (r *taskResolver) Contact(ctx context.Context) (*contactResolver, error) {
+	if r.task.ContactID == "" {
+		return nil, nil
+	}
+	contact, err := load[models.Contact](ctx, loadersFrom(ctx).contacts, r.task.ContactID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if contact.ID == "" {
+		return nil, nil
+	}
+	return &contactResolver{contact}, nil
+}
+
+func (r *taskResolver) Comments(ctx context.Context) ([]*commentResolver, error) {
+	comments, err := load[[]models.Comment](ctx, loadersFrom(ctx).comments, r.task.ID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	resolvers := make([]*commentResolver, len(comments))
+	for i := range comments {
+		resolvers[i] = &commentResolver{comments[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *taskResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	tags, err := load[[]models.Tag](ctx, loadersFrom(ctx).tags, r.task.ID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	resolvers := make([]*tagResolver, len(tags))
+	for i := range tags {
+		resolvers[i] = &tagResolver{tags[i]}
+	}
+	return resolvers, nil
+}
+
+type reminderResolver struct {
+	reminder models.Reminder
+}
+
+func reminderResolvers(reminders []models.Reminder) []*reminderResolver {
+	resolvers := make([]*reminderResolver, len(reminders))
+	for i := range reminders {
+		resolvers[i] = &reminderResolver{reminders[i]}
+	}
+	return resolvers
+}
+
+func (r *reminderResolver) ID() graphql.ID     { return graphql.ID(r.reminder.ID) }
+func (r *reminderResolver) Date() string       { return r.reminder.Date }
+func (r *reminderResolver) TaskID() graphql.ID { return graphql.ID(r.reminder.TaskID) }
+
+type contactResolver struct {
+	contact models.Contact
+}
+
+func (r *contactResolver) ID() graphql.ID          { return graphql.ID(r.contact.ID) }
+func (r *contactResolver) Name() string            { return r.contact.Name }
+func (r *contactResolver) Email() string           { return r.contact.Email }
+func (r *contactResolver) Phone() string           { return r.contact.Phone }
+func (r *contactResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.contact.CreatedAt} }
+
+type commentResolver struct {
+	comment models.Comment
+}
+
+func (r *commentResolver) ID() graphql.ID           { return graphql.ID(r.comment.ID) }
+func (r *commentResolver) AuthorID() graphql.ID     { return graphql.ID(r.comment.AuthorID) }
+func (r *commentResolver) Body() string             { return r.comment.Body }
+func (r *commentResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.comment.CreatedAt} }
+func (r *commentResolver) UpdatedAt() *graphql.Time { return optionalTime(r.comment.UpdatedAt) }
+
+func (r *commentResolver) Mentions() []graphql.ID {
+	mentions := make([]graphql.ID, len(r.comment.Mentions))
+	for i, userID := range r.comment.Mentions {
+		mentions[i] = graphql.ID(userID)
+	}
+	return mentions
+}
+
+type tagResolver struct {
+	tag models.Tag
+}
+
+func (r *tagResolver) ID() graphql.ID { return graphql.ID(r.tag.ID) }
+func (r *tagResolver) Name() string   { return r.tag.Name }
+func (r *tagResolver) Color() string  { return r.tag.Color }
+
+type pageInfoResolver struct {
+	endCursor   *string
+	hasNextPage bool
+}
+
+func (r *pageInfoResolver) EndCursor() *string { return r.endCursor }
+func (r *pageInfoResolver) HasNextPage() bool  { return r.hasNextPage }
+
+type taskEdgeResolver struct {
+	node *taskResolver
+}
+
+func (r *taskEdgeResolver) Cursor() string      { return encodeCursor(r.node.task.ID) }
+func (r *taskEdgeResolver) Node() *taskResolver { return r.node }
+
+type taskConnectionResolver struct {
+	nodes       []*taskResolver
+	hasNextPage bool
+}
+
+func (r *taskConnectionResolver) Nodes() []*taskResolver { return r.nodes }
+
+func (r *taskConnectionResolver) Edges() []*taskEdgeResolver {
+	edges := make([]*taskEdgeResolver, len(r.nodes))
+	for i, node := range r.nodes {
+		edges[i] = &taskEdgeResolver{node}
+	}
+	return edges
+}
+
+func (r *taskConnectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: r.hasNextPage}
+	if n := len(r.nodes); n > 0 {
+		cursor := encodeCursor(r.nodes[n-1].task.ID)
+		info.endCursor = &cursor
+	}
+	return info
+}
+
+type contactEdgeResolver struct {
+	node *contactResolver
+}
+
+func (r *contactEdgeResolver) Cursor() string         { return encodeCursor(r.node.contact.ID) }
+func (r *contactEdgeResolver) Node() *contactResolver { return r.node }
+
+type contactConnectionResolver struct {
+	nodes       []*contactResolver
+	hasNextPage bool
+}
+
+func (r *contactConnectionResolver) Nodes() []*contactResolver { return r.nodes }
+
+func (r *contactConnectionResolver) Edges() []*contactEdgeResolver {
+	edges := make([]*contactEdgeResolver, len(r.nodes))
+	for i, node := range r.nodes {
+		edges[i] = &contactEdgeResolver{node}
+	}
+	return edges
+}
+
+func (r *contactConnectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: r.hasNextPage}
+	if n := len(r.nodes); n > 0 {
+		cursor := encodeCursor(r.nodes[n-1].contact.ID)
+		info.endCursor = &cursor
+	}
+	return info
+}
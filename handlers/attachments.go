@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// ChecksumHeader carries the hex encoded SHA-256 of an attachment, on
+// uploads to have it verified and on downloads to let clients verify it.
+const ChecksumHeader = "X-Checksum-SHA256"
+
+// multipartOverhead is the room left for the multipart framing around an
+// uploaded file.
+const multipartOverhead = 1 << 20
+
+// @Summary Upload an attachment
+// @Description Attaches a file to a task. The file is sent in the "file" part of a multipart form.
+// @Description Its SHA-256 can be given in the X-Checksum-SHA256 header or in a "sha256" part sent before the file.
+// @ID create-attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param file formData file true "File to attach"
+// @Param sha256 formData string false "Hex encoded SHA-256 of the file"
+// @Success 201 {object} models.Attachment "Successfully uploaded attachment"
+// @Failure 400 {object} problem "Bad request or checksum mismatch"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 413 {object} problem "Attachment too large"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/attachments [post]
+func UploadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxAttachmentSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		badRequest(w, r, "Expected a multipart/form-data body")
+		return
+	}
+
+	checksum := r.Header.Get(ChecksumHeader)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			badRequest(w, r, "Missing file part")
+			return
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeError(w, r, err, "")
+				return
+			}
+			badRequest(w, r, "Invalid multipart body")
+			return
+		}
+
+		switch part.FormName() {
+		case "sha256":
+			value, err := io.ReadAll(io.LimitReader(part, 128))
+			if err != nil {
+				badRequest(w, r, "Invalid multipart body")
+				return
+			}
+			checksum = strings.TrimSpace(string(value))
+		case "file":
+			attachment, err := controllers.CreateAttachment(r.Context(), chi.URLParam(r, "id"), part.FileName(), part, checksum)
+			if err != nil {
+				writeError(w, r, err, "Error uploading attachment")
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(attachment)
+			return
+		}
+	}
+}
+
+// @Summary Download an attachment
+// @Description Retrieves the content of an attachment. Its SHA-256 is sent in the X-Checksum-SHA256 header.
+// @ID download-attachment
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Success 200 {file} file "Attachment content"
+// @Failure 404 {object} problem "Attachment not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /attachments/{id} [get]
+func DownloadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	attachment, content, err := controllers.OpenAttachment(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error downloading attachment")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+attachment.Checksum+`"`)
+	w.Header().Set(ChecksumHeader, attachment.Checksum)
+
+	io.Copy(w, content)
+}
+
+// @Summary Delete an attachment
+// @Description Deletes an attachment and its content
+// @ID delete-attachment
+// @Produce json
+// @Param id path string true "Attachment ID"
+// @Success 200 {object} string "Successfully deleted attachment"
+// @Failure 404 {object} problem "Attachment not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /attachments/{id} [delete]
+func DeleteAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteAttachment(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting attachment")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// @Summary Query the audit log
+// @Description Lists the recorded mutations of the tasks of the calling user and of their reminders, tags, checklist items and attachments, most recent first. Pass the ID of the last entry as before to get the next page.
+// @ID get-audit-log
+// @Produce json
+// @Param actorID query string false "ID of the user who made the change"
+// @Param action query string false "create, update, delete, complete or reopen"
+// @Param entityType query string false "task, reminder, tag, checklist_item or attachment"
+// @Param entityID query string false "ID of the task, reminder, tag, checklist item or attachment"
+// @Param taskID query string false "Task ID, including changes to its reminders, tags, checklist items and attachments"
+// @Param requestID query string false "ID of the request that made the change"
+// @Param from query string false "Earliest time, in RFC3339 format"
+// @Param to query string false "Latest time (exclusive), in RFC3339 format"
+// @Param before query int false "Only entries older than this entry ID"
+// @Param limit query int false "Maximum number of entries, 100 by default"
+// @Success 200 {array} models.AuditEntry "Audit entries"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /audit [get]
+func GetAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		ActorID:    query.Get("actorID"),
+		Action:     query.Get("action"),
+		EntityType: query.Get("entityType"),
+		EntityID:   query.Get("entityID"),
+		TaskID:     query.Get("taskID"),
+		RequestID:  query.Get("requestID"),
+	}
+
+	var err error
+	if value := query.Get("from"); value != "" {
+		filter.From, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			badRequest(w, r, "Invalid from time")
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		filter.To, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			badRequest(w, r, "Invalid to time")
+			return
+		}
+	}
+	if value := query.Get("before"); value != "" {
+		filter.BeforeID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			badRequest(w, r, "Invalid before ID")
+			return
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		filter.Limit, err = strconv.Atoi(value)
+		if err != nil || filter.Limit <= 0 {
+			badRequest(w, r, "Invalid limit")
+			return
+		}
+	}
+
+	entries, err := controllers.GetAuditLog(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving audit log")
+		return
+	}
+
+	json.NewEncoder(w).Encode(entries)
+}
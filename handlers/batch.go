@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// batchResult is the outcome of an operation of a batch
+type batchResult struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op"`
+	ID     string       `json:"id,omitempty"`    // Task the operation applied to
+	Status int          `json:"status"`          // Status the operation would have on its own
+	Task   *models.Task `json:"task,omitempty"`  // Task after the operation, absent after a deletion
+	Error  *problem     `json:"error,omitempty"` // Why the operation failed
+}
+
+// batchRequest is the body of the batch endpoint. The task of each
+// operation is decoded on its own, so that an invalid one fails its
+// operation only, with the rules of single requests.
+type batchRequest struct {
+	Mode       string `json:"mode"`
+	Operations []struct {
+		models.BatchOperation
+		Task json.RawMessage `json:"task"` // Shadows BatchOperation.Task
+	} `json:"operations"`
+}
+
+// batch returns the batch of the request, with the task of each operation
+// or why it is invalid.
+func (req batchRequest) batch() models.BatchRequest {
+	batch := models.BatchRequest{Mode: req.Mode, Operations: make([]models.BatchOperation, len(req.Operations))}
+	for i, input := range req.Operations {
+		op := input.BatchOperation
+		if len(input.Task) > 0 && string(input.Task) != "null" {
+			task, err := parseTask(input.Task)
+			var validationErr *controllers.ValidationError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &validationErr):
+				op.TaskErr = err
+			case errors.As(err, &typeErr) && typeErr.Field != "":
+				verr := &controllers.ValidationError{}
+				verr.Add(typeErr.Field, "has an invalid type")
+				op.TaskErr = verr
+			case err != nil:
+				verr := &controllers.ValidationError{}
+				verr.Add("", "must be a task")
+				op.TaskErr = verr
+			default:
+				op.Task = &task
+			}
+		}
+		batch.Operations[i] = op
+	}
+	return batch
+}
+
+// @Summary Apply a batch of operations to tasks
+// @Description Creates, updates, deletes and completes up to MAX_BATCH_OPERATIONS tasks, validated like single requests.
+// @Description In atomic mode (the default) all operations succeed or none is applied, and the first failure is reported with its index.
+// @Description In best effort mode each operation is applied on its own, and the result of each one holds its status and error.
+// @ID batch-tasks
+// @Accept json
+// @Produce json
+// @Param batch body models.BatchRequest true "Mode and operations"
+// @Success 200 {array} batchResult "Result of each operation"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task of an atomic operation not found"
+// @Failure 409 {object} problem "An atomic operation conflicts with a task"
+// @Failure 412 {object} problem "Task of an atomic operation has been changed since it was read"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/batch [post]
+func BatchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+	batch := req.batch()
+
+	results, err := controllers.ApplyBatch(r.Context(), batch)
+	if err != nil {
+		writeError(w, r, err, "Error applying batch")
+		return
+	}
+
+	response := make([]batchResult, len(results))
+	for i, result := range results {
+		op := batch.Operations[i]
+		response[i] = batchResult{Index: i, Op: op.Op, ID: result.ID, Status: http.StatusOK, Task: result.Task}
+		if op.Op == models.BatchCreate {
+			response[i].Status = http.StatusCreated
+		}
+		if result.Err != nil {
+			p := completeProblem(r, errorProblem(r, result.Err, "Error applying operation"))
+			response[i].Status = p.Status
+			response[i].Error = &p
+		}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
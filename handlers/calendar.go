@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// @Summary Create the calendar feed of the user
+// @Description Creates the secret URL of an iCalendar feed of the tasks of the calling user with a due date, for calendar apps
+// @Description to subscribe to. Creating it again replaces the URL, so the previous one stops working.
+// @ID create-calendar-feed
+// @Produce json
+// @Success 201 {object} models.CalendarFeed "Created feed, with its URL"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /calendar/feed [post]
+func CreateCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, err := controllers.CreateCalendarFeed(r.Context())
+	if err != nil {
+		writeError(w, r, err, "Error creating calendar feed")
+		return
+	}
+
+	feed.URL = calendarFeedURL(r, feed.Token)
+	w.Header().Set("Location", feed.URL)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(feed)
+}
+
+// @Summary Delete the calendar feed of the user
+// @Description Deletes the calendar feed of the calling user, so its URL stops working
+// @ID delete-calendar-feed
+// @Produce json
+// @Success 200 {object} string "Successfully deleted calendar feed"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Calendar feed not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /calendar/feed [delete]
+func DeleteCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteCalendarFeed(r.Context())
+	if err != nil {
+		writeError(w, r, err, "Error deleting calendar feed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get a calendar feed
+// @Description Retrieves the tasks with a due date of the user of a feed as iCalendar data, with an alarm for each reminder, an RRULE for recurring tasks and the dates
+// @Description in the time zone of the user. The token in the URL identifies the feed, so no X-User-ID header is needed.
+// @Description The ETag header identifies the content; when If-None-Match lists it the feed is unchanged and not sent again.
+// @ID get-calendar-feed
+// @Produce text/calendar
+// @Param token path string true "Secret token of the feed"
+// @Param components query string false "VEVENT (the default) to show tasks in the calendar, VTODO in the to-do list, or VEVENT,VTODO"
+// @Param If-None-Match header string false "ETag of the feed held by the client"
+// @Success 200 {string} string "iCalendar data"
+// @Success 304 {string} string "Feed not modified"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Calendar feed not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /calendar/{token}.ics [get]
+func GetCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	var components []string
+	if value := r.URL.Query().Get("components"); value != "" {
+		components = strings.Split(value, ",")
+	}
+
+	data, err := controllers.GetCalendarFeed(chi.URLParam(r, "token"), components)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving calendar feed")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if header := r.Header.Get("If-None-Match"); header != "" && matchETag(header, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+// calendarFeedURL returns the absolute URL of the feed with the given token,
+// on the host the request was sent to.
+func calendarFeedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + "/v1/calendar/" + token + ".ics"
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/helpers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// notifyMentions notifies the users newly mentioned in a comment.
+func notifyMentions(comment models.Comment, mentioned []models.User) {
+	if len(mentioned) == 0 {
+		return
+	}
+
+	task, err := controllers.GetTask(comment.TaskID, 0)
+	if err != nil {
+		log.Printf("Error loading task %s to notify mentions: %v", comment.TaskID, err)
+		return
+	}
+
+	for _, user := range mentioned {
+		helpers.NotifyMention(task, user, comment)
+	}
+}
+
+// @Summary Create a new comment
+// @Description Posts a comment on a task as the calling user. Mentioned users are notified.
+// @ID create-comment
+// @Accept json
+// @Produce json
+// @Param comment body models.Comment true "Comment details"
+// @Success 201 {object} models.Comment "Successfully created comment"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /comments [post]
+func CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	var newComment models.Comment
+	err := json.NewDecoder(r.Body).Decode(&newComment)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	comment, mentioned, err := controllers.CreateComment(r.Context(), newComment)
+	if err != nil {
+		writeError(w, r, err, "Error creating comment")
+		return
+	}
+	notifyMentions(comment, mentioned)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// @Summary Get the comments of a task
+// @Description Retrieves the comments of a task, oldest first
+// @ID get-comments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Comment "Successfully retrieved comments"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/comments [get]
+func GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	comments, err := controllers.GetComments(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving comments")
+		return
+	}
+
+	json.NewEncoder(w).Encode(comments)
+}
+
+// @Summary Update a comment
+// @Description Edits a comment of the calling user. Newly mentioned users are notified.
+// @ID update-comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param comment body models.Comment true "Updated comment"
+// @Success 200 {object} models.Comment "Successfully updated comment"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 403 {object} problem "Not the author of the comment"
+// @Failure 404 {object} problem "Comment not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /comments/{id} [put]
+func UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedComment models.Comment
+	err := json.NewDecoder(r.Body).Decode(&updatedComment)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	comment, mentioned, err := controllers.UpdateComment(r.Context(), chi.URLParam(r, "id"), updatedComment.Body)
+	if err != nil {
+		writeError(w, r, err, "Error updating comment")
+		return
+	}
+	notifyMentions(comment, mentioned)
+
+	json.NewEncoder(w).Encode(comment)
+}
+
+// @Summary Delete a comment
+// @Description Deletes a comment of the calling user. The comment is hidden, its history is kept.
+// @ID delete-comment
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} string "Successfully deleted comment"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 403 {object} problem "Not the author of the comment"
+// @Failure 404 {object} problem "Comment not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /comments/{id} [delete]
+func DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteComment(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting comment")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get the history of a comment
+// @Description Retrieves the previous versions of an edited comment, oldest first
+// @ID get-comment-revisions
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {array} models.CommentRevision "Successfully retrieved revisions"
+// @Failure 404 {object} problem "Comment not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /comments/{id}/revisions [get]
+func GetCommentRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	revisions, err := controllers.GetCommentRevisions(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving comment history")
+		return
+	}
+
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// @Summary Get the activity of a task
+// @Description Retrieves the comments and changes of a task in a single feed, oldest first
+// @ID get-task-activity
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Activity "Successfully retrieved activity"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/activity [get]
+func GetTaskActivityHandler(w http.ResponseWriter, r *http.Request) {
+	feed, err := controllers.GetTaskActivity(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving activity")
+		return
+	}
+
+	json.NewEncoder(w).Encode(feed)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// @Summary Create a new contact
+// @Description Creates a contact for the calling user. Tasks refer to it with their contactID.
+// @ID create-contact
+// @Accept json
+// @Produce json
+// @Param contact body models.Contact true "Contact details"
+// @Success 201 {object} models.Contact "Successfully created contact"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /contacts [post]
+func CreateContactHandler(w http.ResponseWriter, r *http.Request) {
+	var newContact models.Contact
+	err := json.NewDecoder(r.Body).Decode(&newContact)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+	newContact.UserID = auth.UserID(r.Context())
+
+	contact, err := controllers.CreateContact(newContact)
+	if err != nil {
+		writeError(w, r, err, "Error creating contact")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(contact)
+}
+
+// @Summary Get a contact by ID
+// @Description Retrieves a contact of the calling user by its unique identifier
+// @ID get-contact
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Success 200 {object} models.Contact "Successfully retrieved contact"
+// @Failure 404 {object} problem "Contact not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /contacts/{id} [get]
+func GetContactHandler(w http.ResponseWriter, r *http.Request) {
+	contact, err := controllers.GetContact(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving contact")
+		return
+	}
+
+	json.NewEncoder(w).Encode(contact)
+}
+
+// @Summary Get all contacts
+// @Description Retrieves the contacts of the calling user by name
+// @ID get-all-contacts
+// @Produce json
+// @Success 200 {array} models.Contact "Successfully retrieved contacts"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /contacts [get]
+func GetAllContactsHandler(w http.ResponseWriter, r *http.Request) {
+	contacts, err := controllers.GetAllContacts(auth.UserID(r.Context()))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving contacts")
+		return
+	}
+
+	json.NewEncoder(w).Encode(contacts)
+}
+
+// @Summary Update a contact by ID
+// @Description Updates the name, email and phone of a contact of the calling user
+// @ID update-contact
+// @Accept json
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Param contact body models.Contact true "Updated contact details"
+// @Success 200 {object} models.Contact "Successfully updated contact"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Contact not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /contacts/{id} [put]
+func UpdateContactHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedContact models.Contact
+	err := json.NewDecoder(r.Body).Decode(&updatedContact)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	contact, err := controllers.UpdateContact(r.Context(), chi.URLParam(r, "id"), updatedContact)
+	if err != nil {
+		writeError(w, r, err, "Error updating contact")
+		return
+	}
+
+	json.NewEncoder(w).Encode(contact)
+}
+
+// @Summary Delete a contact by ID
+// @Description Deletes a contact of the calling user, its tasks are kept without a contact
+// @ID delete-contact
+// @Produce json
+// @Param id path string true "Contact ID"
+// @Success 200 {string} string "Successfully deleted contact"
+// @Failure 404 {object} problem "Contact not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /contacts/{id} [delete]
+func DeleteContactHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteContact(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting contact")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
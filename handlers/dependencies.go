@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// dependencyRequest is the body of the dependency endpoints.
+type dependencyRequest struct {
+	BlockedByID string `json:"blockedByID"`
+}
+
+// @Summary Add a dependency
+// @Description Declares that a task is blocked by another one. The task is blocked until the other one is completed.
+// @ID add-dependency
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the blocked task"
+// @Param dependency body dependencyRequest true "ID of the blocking task"
+// @Success 200 {object} models.Task "Task with its updated status"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 409 {object} problem "Dependency would create a cycle"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/dependencies [post]
+func AddDependencyHandler(w http.ResponseWriter, r *http.Request) {
+	var req dependencyRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.BlockedByID == "" {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := controllers.AddDependency(r.Context(), chi.URLParam(r, "id"), req.BlockedByID)
+	if err != nil {
+		writeError(w, r, err, "Error adding dependency")
+		return
+	}
+
+	json.NewEncoder(w).Encode(task)
+}
+
+// @Summary Remove a dependency
+// @Description Removes a dependency between two tasks, unblocking the task if nothing else blocks it
+// @ID remove-dependency
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the blocked task"
+// @Param dependency body dependencyRequest true "ID of the blocking task"
+// @Success 200 {object} models.Task "Task with its updated status"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Dependency not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/dependencies [delete]
+func RemoveDependencyHandler(w http.ResponseWriter, r *http.Request) {
+	var req dependencyRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.BlockedByID == "" {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := controllers.RemoveDependency(r.Context(), chi.URLParam(r, "id"), req.BlockedByID)
+	if err != nil {
+		writeError(w, r, err, "Error removing dependency")
+		return
+	}
+
+	json.NewEncoder(w).Encode(task)
+}
+
+// @Summary Get the next tasks
+// @Description Lists incomplete tasks in dependency order, so every task comes after the tasks blocking it
+// @ID get-next-tasks
+// @Produce json
+// @Param readyOnly query bool false "Only list tasks that are not blocked"
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Param userID query string false "ID of the owner of the tasks"
+// @Success 200 {array} models.Task "Tasks in dependency order"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/next [get]
+func GetNextTasksHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	readyOnly := r.URL.Query().Get("readyOnly") == "true"
+
+	tasks, err := controllers.GetNextTasks(filter, readyOnly)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving tasks")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tasks)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deprecated marks the responses of deprecated routes with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers, and links to the routes
+// replacing them.
+func Deprecated(deprecatedAt, sunset time.Time, successor string) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetDate)
+			w.Header().Add("Link", link)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// taskETag is the entity tag of a version of a task.
+func taskETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// matchETag reports whether an If-Match or If-None-Match header lists etag.
+// Weak comparison, used by If-None-Match, ignores the W/ prefix.
+func matchETag(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch evaluates the If-Match header of a request on a task. It
+// returns the version to pass to the controller, zero when the request has
+// no precondition, or writes a 412 response and returns false.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, taskID string) (int64, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, true
+	}
+
+	version, err := controllers.GetTaskVersion(taskID)
+	if errors.Is(err, controllers.ErrTaskNotFound) {
+		// A missing task matches no entity tag, not even *
+		writeProblem(w, r, problem{
+			Status: http.StatusPreconditionFailed,
+			Detail: "Task does not match If-Match",
+			Code:   CodePreconditionFailed,
+		})
+		return 0, false
+	}
+	if err != nil {
+		writeError(w, r, err, "Error checking task version")
+		return 0, false
+	}
+
+	if !matchETag(header, taskETag(version), false) {
+		w.Header().Set("ETag", taskETag(version))
+		writeError(w, r, controllers.ErrVersionMismatch, "")
+		return 0, false
+	}
+	return version, true
+}
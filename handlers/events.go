@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// eventStreamReset tells a resuming client that events may have been missed,
+// and that it should reload its tasks
+const eventStreamReset = "stream.reset"
+
+// @Summary Stream task events
+// @Description Streams the changes of the tasks of the user as Server-Sent Events: task.created, task.updated,
+// @Description task.deleted and reminder.fired. Each event has an ID; a client reconnecting with the Last-Event-ID
+// @Description header gets the events it missed, or a stream.reset event if they are no longer buffered.
+// @Description Comments are sent as heartbeats while the stream is idle.
+// @ID stream-events
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {object} models.Event "Stream of events"
+// @Failure 401 {object} problem "Anonymous request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /events [get]
+func StreamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	if userID == "" {
+		writeError(w, r, controllers.ErrUnauthorized, "")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, fmt.Errorf("response writer does not support flushing"), "Streaming is not supported")
+		return
+	}
+
+	// An invalid ID resumes from nowhere, which resets the stream
+	var lastEventID int64
+	resume := r.Header.Get("Last-Event-ID")
+	if resume != "" {
+		lastEventID, _ = strconv.ParseInt(resume, 10, 64)
+		if lastEventID == 0 {
+			lastEventID = -1
+		}
+	}
+	sub := config.Events.Subscribe(func(event models.Event) bool {
+		return event.UserID == userID
+	}, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep proxies from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Lost {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventStreamReset)
+	}
+	for _, event := range sub.Missed {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(config.EventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for lagging behind, the client resumes on reconnection
+				return
+			}
+			writeEvent(w, event)
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes an event in the Server-Sent Events format.
+func writeEvent(w http.ResponseWriter, event models.Event) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
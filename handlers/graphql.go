@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/graph"
+)
+
+// graphqlRequest is a GraphQL query sent over HTTP
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// @Summary Run a GraphQL query
+// @Description Queries tasks with their reminders, contact, comments and tags in one round trip, or runs a mutation.
+// @Description Queries deeper than GRAPHQL_MAX_DEPTH or more complex than GRAPHQL_MAX_COMPLEXITY are rejected, see the readme.
+// @Description Errors of the query are reported in the errors of the response, with their code in the extensions.
+// @ID graphql
+// @Accept json
+// @Produce json
+// @Param query body graphqlRequest true "Query, operation name and variables"
+// @Success 200 {object} object "Data and errors of the query"
+// @Failure 400 {object} problem "Bad request"
+// @Router /graphql [post]
+func GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var request graphqlRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+	if request.Query == "" {
+		badRequest(w, r, "query is required")
+		return
+	}
+
+	var response *graphql.Response
+	queryErr := graph.CheckComplexity(request.Query, request.OperationName, request.Variables, config.GraphQLMaxComplexity)
+	if queryErr != nil {
+		response = &graphql.Response{Errors: []*errors.QueryError{queryErr}}
+	} else {
+		ctx := graph.WithLoaders(r.Context())
+		response = graph.Schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -2,118 +2,169 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/auth"
 	"github.com/vikash-parashar/task-manager-2/controllers"
 	"github.com/vikash-parashar/task-manager-2/models"
 )
 
 // @Summary Create a new task
-// @Description Creates a new task with the specified details
+// @Description Creates a new task with the specified details. The IDs of the task and its reminders are generated by the server,
+// @Description and the Location header points to the created task. Invalid fields are listed in the errors of the problem details.
 // @ID create-task
 // @Produce json
 // @Param task body models.Task true "models.Task details"
+// @Param Idempotency-Key header string false "Unique key of the request, to safely retry it"
 // @Success 201 {object} models.Task "Successfully created task"
-// @Failure 400 {object} string "Bad request"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/create [post]
+// @Failure 400 {object} problem "Bad request"
+// @Failure 409 {object} problem "Idempotency-Key in progress or used for a different request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks [post]
 func CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
-	var newTask models.Task
-	err := json.NewDecoder(r.Body).Decode(&newTask)
-	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	newTask, ok := decodeTask(w, r)
+	if !ok {
 		return
 	}
+	newTask.UserID = auth.UserID(r.Context())
 
-	err = controllers.CreateTask(newTask)
+	task, err := controllers.CreateTask(r.Context(), newTask)
 	if err != nil {
-		http.Error(w, "Error creating task", http.StatusInternalServerError)
+		writeError(w, r, err, "Error creating task")
 		return
 	}
 
+	w.Header().Set("Location", "/v1/tasks/"+task.ID)
+	w.Header().Set("ETag", taskETag(task.Version))
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task)
 }
 
 // @Summary Get a task by ID
-// @Description Retrieves a task by its unique identifier
+// @Description Retrieves a task by its unique identifier. The ETag header holds its version; when If-None-Match lists it the task is unchanged and not sent again.
 // @ID get-task
 // @Produce json
 // @Param id path string true "models.Task ID"
+// @Param depth query int false "Levels of subtasks to include, defaults to 1"
+// @Param If-None-Match header string false "ETag of the version held by the client"
 // @Success 200 {object} models.Task "Successfully retrieved task"
-// @Failure 404 {object} string "models.Task not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/get/{id} [get]
+// @Success 304 {string} string "Task not modified"
+// @Failure 404 {object} problem "models.Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id} [get]
 func GetTaskHandler(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
 	if taskID == "" {
-		http.Error(w, "Task ID is required", http.StatusBadRequest)
+		badRequest(w, r, "Task ID is required")
 		return
 	}
 
-	task, err := controllers.GetTask(taskID)
+	var err error
+	depth := controllers.DefaultSubtaskDepth
+	if value := r.URL.Query().Get("depth"); value != "" {
+		depth, err = strconv.Atoi(value)
+		if err != nil || depth < 0 || depth > controllers.MaxSubtaskDepth {
+			badRequest(w, r, fmt.Sprintf("depth must be between 0 and %d", controllers.MaxSubtaskDepth))
+			return
+		}
+	}
+
+	// Compare versions before loading the whole task
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		version, err := controllers.GetTaskVersion(taskID)
+		if err == nil && matchETag(header, taskETag(version), true) {
+			w.Header().Set("ETag", taskETag(version))
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	task, err := controllers.GetTask(taskID, depth)
 	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		writeError(w, r, err, "Error retrieving task")
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(task.Version))
 	json.NewEncoder(w).Encode(task)
 }
 
 // @Summary Update a task by ID
-// @Description Updates a task with the specified details
+// @Description Updates a task with the specified details. With If-Match, the update only happens if the task still has that ETag.
 // @ID update-task
 // @Produce json
 // @Param id path string true "models.Task ID"
 // @Param task body models.Task true "Updated task details"
+// @Param If-Match header string false "ETag of the version the update is based on"
 // @Success 200 {object} models.Task "Successfully updated task"
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "models.Task not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/update/{id} [put]
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "models.Task not found"
+// @Failure 412 {object} problem "Task has been changed since it was read"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id} [put]
 func UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
 	if taskID == "" {
-		http.Error(w, "Task ID is required", http.StatusBadRequest)
+		badRequest(w, r, "Task ID is required")
 		return
 	}
 
-	var updatedTask models.Task
-	err := json.NewDecoder(r.Body).Decode(&updatedTask)
-	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	updatedTask, ok := decodeTask(w, r)
+	if !ok {
+		return
+	}
+
+	// The version in the body is ignored, only If-Match sets a precondition
+	version, ok := checkIfMatch(w, r, taskID)
+	if !ok {
 		return
 	}
+	updatedTask.Version = version
 
-	err = controllers.UpdateTask(taskID, updatedTask)
+	err := controllers.UpdateTask(r.Context(), taskID, updatedTask)
 	if err != nil {
-		http.Error(w, "Error updating task", http.StatusInternalServerError)
+		writeError(w, r, err, "Error updating task")
 		return
 	}
 
+	// Let the client chain conditional updates
+	if version, err := controllers.GetTaskVersion(taskID); err == nil {
+		w.Header().Set("ETag", taskETag(version))
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
 // @Summary Delete a task by ID
-// @Description Deletes a task by its unique identifier
+// @Description Moves a task and its subtasks to the trash, from where they can be restored until purged
 // @ID delete-task
 // @Produce json
 // @Param id path string true "models.Task ID"
+// @Param If-Match header string false "ETag of the version the deletion is based on"
 // @Success 200 {object} string "Successfully deleted task"
-// @Failure 404 {object} string "models.Task not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/delete/{id} [delete]
+// @Failure 404 {object} problem "models.Task not found"
+// @Failure 412 {object} problem "Task has been changed since it was read"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id} [delete]
 func DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
 	if taskID == "" {
-		http.Error(w, "Task ID is required", http.StatusBadRequest)
+		badRequest(w, r, "Task ID is required")
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, taskID)
+	if !ok {
 		return
 	}
 
-	err := controllers.DeleteTask(taskID)
+	err := controllers.DeleteTask(r.Context(), taskID, version)
 	if err != nil {
-		http.Error(w, "Error deleting task", http.StatusInternalServerError)
+		writeError(w, r, err, "Error deleting task")
 		return
 	}
 
@@ -124,13 +175,25 @@ func DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieves a list of all tasks
 // @ID get-all-tasks
 // @Produce json
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Param userID query string false "ID of the owner of the tasks"
 // @Success 200 {array} models.Task "Successfully retrieved tasks"
-// @Failure 500 {object} string "Internal server error"
-// @Router /tasks/getAll [get]
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks [get]
 func GetAllTasksHandler(w http.ResponseWriter, r *http.Request) {
-	tasks, err := controllers.GetAllTasks()
+	filter, err := parseTaskFilter(r)
 	if err != nil {
-		http.Error(w, "Error retrieving tasks", http.StatusInternalServerError)
+		badRequest(w, r, err.Error())
+		return
+	}
+
+	tasks, err := controllers.GetAllTasks(filter)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving tasks")
 		return
 	}
 
@@ -141,14 +204,63 @@ func GetAllTasksHandler(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieves a list of tasks with due reminders
 // @ID get-tasks-with-due-reminders
 // @Produce json
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Param userID query string false "ID of the owner of the tasks"
 // @Success 200 {array} models.Task "Successfully retrieved tasks with due reminders"
-// @Failure 500 {object} string "Internal server error"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
 // @Router /tasks/dueReminders [get]
 func GetTasksWithDueReminder(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
 	currentTime := time.Now()
-	tasks, err := controllers.GetTasksWithDueReminders(currentTime)
+	tasks, err := controllers.GetTasksWithDueReminders(currentTime, filter)
 	if err != nil {
+		writeError(w, r, err, "Error retrieving tasks with due reminders")
 		return
 	}
 	json.NewEncoder(w).Encode(tasks)
 }
+
+// parseTaskFilter reads the task listing filters from the query string.
+func parseTaskFilter(r *http.Request) (models.TaskFilter, error) {
+	query := r.URL.Query()
+	filter := models.TaskFilter{
+		TagIDs:    splitList(query["tags"]),
+		TagMode:   strings.ToLower(query.Get("tagMode")),
+		ProjectID: query.Get("projectID"),
+		ContactID: query.Get("contactID"),
+		UserID:    query.Get("userID"),
+	}
+
+	switch filter.TagMode {
+	case "":
+		filter.TagMode = models.TagModeAnd
+	case models.TagModeAnd, models.TagModeOr:
+	default:
+		return models.TaskFilter{}, fmt.Errorf("tagMode must be %q or %q", models.TagModeAnd, models.TagModeOr)
+	}
+
+	return filter, nil
+}
+
+// splitList flattens repeated and comma separated query values.
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
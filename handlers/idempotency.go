@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Headers of idempotent requests
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed" // Set to true on replayed responses
+)
+
+// replayedHeaders are the response headers stored with an idempotent
+// response and replayed with it.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// Idempotent makes the POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key safe to retry. The first response for a key is stored
+// with a fingerprint of the request and replayed to retries, while the key
+// is rejected for a different request. Server errors are not stored, so
+// their retries run again.
+func Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || !isMutation(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.MaxAttachmentSize+multipartOverhead))
+		if err != nil {
+			writeError(w, r, err, "Error reading request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(r, body)
+
+		stored, err := controllers.BeginIdempotentRequest(r.Context(), key, fingerprint)
+		if err != nil {
+			writeError(w, r, err, "Error checking idempotency key")
+			return
+		}
+		if stored != nil {
+			replayResponse(w, *stored)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			// Let a retry run again if the handler panicked
+			if recovered := recover(); recovered != nil {
+				releaseIdempotencyKey(r, key, fingerprint)
+				panic(recovered)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status >= http.StatusInternalServerError {
+			releaseIdempotencyKey(r, key, fingerprint)
+			return
+		}
+
+		response := models.IdempotentResponse{Status: recorder.status, Header: http.Header{}, Body: recorder.body.Bytes()}
+		for _, name := range replayedHeaders {
+			if values := w.Header().Values(name); len(values) > 0 {
+				response.Header[name] = values
+			}
+		}
+		err = controllers.CompleteIdempotentRequest(r.Context(), key, fingerprint, response)
+		if err != nil {
+			log.Printf("Error storing idempotent response: %v", err)
+		}
+	})
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint hashes what identifies a request, so a key reused for
+// another request is detected.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+"\n"+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, response models.IdempotentResponse) {
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+func releaseIdempotencyKey(r *http.Request, key, fingerprint string) {
+	err := controllers.ReleaseIdempotencyKey(r.Context(), key, fingerprint)
+	if err != nil {
+		log.Printf("Error releasing idempotency key: %v", err)
+	}
+}
+
+// responseRecorder writes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// maxPatchSize is the largest patch accepted, in bytes.
+const maxPatchSize = 1 << 20
+
+// @Summary Patch a task by ID
+// @Description Changes some fields of a task with a JSON merge patch (RFC 7396, Content-Type application/merge-patch+json)
+// @Description or a JSON patch (RFC 6902, Content-Type application/json-patch+json). Reminders are addressed by index,
+// @Description e.g. /reminders/0/date, and new ones get an ID. The patch is applied atomically: if any operation fails,
+// @Description nothing changes. With If-Match, the patch only applies if the task still has that ETag.
+// @ID patch-task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param patch body object true "Merge patch or JSON patch"
+// @Param If-Match header string false "ETag of the version the patch is based on"
+// @Success 200 {object} models.Task "Patched task"
+// @Failure 400 {object} problem "Invalid patch or task"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 409 {object} problem "A test operation failed"
+// @Failure 412 {object} problem "Task has been changed since it was read"
+// @Failure 415 {object} problem "Unsupported patch format"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id} [patch]
+func PatchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := chi.URLParam(r, "id")
+
+	format, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (format != controllers.MergePatch && format != controllers.JSONPatch) {
+		writeError(w, r, controllers.ErrPatchFormat, "")
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, taskID)
+	if !ok {
+		return
+	}
+
+	task, err := controllers.PatchTask(r.Context(), taskID, format, patch, version)
+	if err != nil {
+		writeError(w, r, err, "Error patching task")
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task.Version))
+	json.NewEncoder(w).Encode(task)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// problem is an RFC 7807 problem details response
+type problem struct {
+	Type      string                   `json:"type"`
+	Title     string                   `json:"title"`
+	Status    int                      `json:"status"`
+	Detail    string                   `json:"detail,omitempty"`
+	Instance  string                   `json:"instance,omitempty"`
+	Code      string                   `json:"code"`                // Stable error code, e.g. task_not_found
+	RequestID string                   `json:"requestID,omitempty"` // ID of the request, to find it in the logs
+	Errors    []controllers.FieldError `json:"errors,omitempty"`    // Invalid fields of the request
+}
+
+// Error codes of failures outside the controllers
+const (
+	CodeBadRequest         = "bad_request"
+	CodeInternal           = "internal_error"
+	CodePreconditionFailed = "precondition_failed"
+)
+
+// kindStatus maps the kinds of controller errors to response statuses.
+var kindStatus = map[controllers.ErrorKind]int{
+	controllers.KindNotFound:     http.StatusNotFound,
+	controllers.KindConflict:     http.StatusConflict,
+	controllers.KindValidation:   http.StatusBadRequest,
+	controllers.KindUnauthorized: http.StatusUnauthorized,
+	controllers.KindForbidden:    http.StatusForbidden,
+	controllers.KindPrecondition: http.StatusPreconditionFailed,
+	controllers.KindTooLarge:     http.StatusRequestEntityTooLarge,
+	controllers.KindUnsupported:  http.StatusUnsupportedMediaType,
+}
+
+// completeProblem fills in the defaults and the request fields of a problem.
+func completeProblem(r *http.Request, p problem) problem {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+	return p
+}
+
+// writeProblem writes a problem details response for the request.
+func writeProblem(w http.ResponseWriter, r *http.Request, p problem) {
+	p = completeProblem(r, p)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeError writes the response for an error returned by the controllers.
+// Unexpected errors are logged and reported with message only, so their
+// details do not leak to clients.
+func writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeProblem(w, r, errorProblem(r, err, message))
+}
+
+// errorProblem returns the problem reporting an error returned by the
+// controllers, as described by writeError.
+func errorProblem(r *http.Request, err error, message string) problem {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = controllers.ErrAttachmentTooLarge
+	}
+
+	domainErr := controllers.Classify(err)
+	if domainErr == nil {
+		log.Printf("%s: %v (request %s)", message, err, middleware.GetReqID(r.Context()))
+		return problem{Status: http.StatusInternalServerError, Detail: message, Code: CodeInternal}
+	}
+
+	p := problem{Status: kindStatus[domainErr.Kind], Detail: err.Error(), Code: domainErr.Code}
+	var validationErr *controllers.ValidationError
+	if errors.As(err, &validationErr) {
+		p.Detail = "The request has invalid fields"
+		p.Errors = validationErr.Fields
+	}
+	return p
+}
+
+// badRequest writes the response for a malformed request.
+func badRequest(w http.ResponseWriter, r *http.Request, detail string) {
+	writeProblem(w, r, problem{Status: http.StatusBadRequest, Detail: detail, Code: CodeBadRequest})
+}
+
+// decodeTask reads a task from a request body, or writes a 400 response
+// and returns false.
+func decodeTask(w http.ResponseWriter, r *http.Request) (models.Task, bool) {
+	var data json.RawMessage
+	err := json.NewDecoder(r.Body).Decode(&data)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return models.Task{}, false
+	}
+
+	task, err := parseTask(data)
+	var validationErr *controllers.ValidationError
+	if errors.As(err, &validationErr) {
+		writeError(w, r, err, "")
+		return models.Task{}, false
+	}
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return models.Task{}, false
+	}
+	return task, true
+}
+
+// parseTask reads a task from JSON. An invalid dueDateTime is reported as a
+// *controllers.ValidationError, and other invalid JSON as is.
+func parseTask(data []byte) (models.Task, error) {
+	var input struct {
+		models.Task
+		DueDateTime json.RawMessage `json:"dueDateTime"` // Shadows Task.DueDateTime
+	}
+	err := json.Unmarshal(data, &input)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task := input.Task
+	if len(input.DueDateTime) > 0 {
+		err = json.Unmarshal(input.DueDateTime, &task.DueDateTime)
+		if err != nil {
+			verr := &controllers.ValidationError{}
+			verr.Add("dueDateTime", "must be a date in RFC3339 format")
+			return models.Task{}, verr
+		}
+	}
+	return task, nil
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// reorderProjectsRequest is the body of the reorder endpoint.
+type reorderProjectsRequest struct {
+	ProjectIDs []string `json:"projectIDs"`
+}
+
+// @Summary Create a new project
+// @Description Creates a project for the calling user
+// @ID create-project
+// @Accept json
+// @Produce json
+// @Param project body models.Project true "Project details"
+// @Success 201 {object} models.Project "Successfully created project"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects [post]
+func CreateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	var newProject models.Project
+	err := json.NewDecoder(r.Body).Decode(&newProject)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+	project, err := controllers.CreateProject(r.Context(), newProject)
+	if err != nil {
+		writeError(w, r, err, "Error creating project")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(project)
+}
+
+// @Summary Get a project by ID
+// @Description Retrieves a project of the calling user by its unique identifier
+// @ID get-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project "Successfully retrieved project"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/{id} [get]
+func GetProjectHandler(w http.ResponseWriter, r *http.Request) {
+	project, err := controllers.GetProject(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(project)
+}
+
+// @Summary Get all projects
+// @Description Retrieves the projects of the calling user in their saved order
+// @ID get-all-projects
+// @Produce json
+// @Param archived query bool false "List archived projects instead of active ones"
+// @Success 200 {array} models.Project "Successfully retrieved projects"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects [get]
+func GetAllProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	archived := r.URL.Query().Get("archived") == "true"
+
+	projects, err := controllers.GetAllProjects(r.Context(), archived)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving projects")
+		return
+	}
+
+	json.NewEncoder(w).Encode(projects)
+}
+
+// @Summary Update a project by ID
+// @Description Updates the name, description and task defaults of a project of the calling user
+// @ID update-project
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param project body models.Project true "Updated project details"
+// @Success 200 {object} models.Project "Successfully updated project"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/{id} [put]
+func UpdateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedProject models.Project
+	err := json.NewDecoder(r.Body).Decode(&updatedProject)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	project, err := controllers.UpdateProject(r.Context(), chi.URLParam(r, "id"), updatedProject)
+	if err != nil {
+		writeError(w, r, err, "Error updating project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(project)
+}
+
+// @Summary Archive a project
+// @Description Hides a project from the default project listing
+// @ID archive-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project "Successfully archived project"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/{id}/archive [post]
+func ArchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	setProjectArchived(w, r, true)
+}
+
+// @Summary Unarchive a project
+// @Description Brings an archived project back to the default project listing
+// @ID unarchive-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project "Successfully unarchived project"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/{id}/unarchive [post]
+func UnarchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	setProjectArchived(w, r, false)
+}
+
+func setProjectArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	project, err := controllers.SetProjectArchived(r.Context(), chi.URLParam(r, "id"), archived)
+	if err != nil {
+		writeError(w, r, err, "Error archiving project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(project)
+}
+
+// @Summary Reorder projects
+// @Description Sets the order of the calling user's projects
+// @ID reorder-projects
+// @Accept json
+// @Produce json
+// @Param projects body reorderProjectsRequest true "Project IDs in their new order"
+// @Success 200 {array} models.Project "Projects in their new order"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/order [put]
+func ReorderProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	var req reorderProjectsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	projects, err := controllers.ReorderProjects(r.Context(), req.ProjectIDs)
+	if err != nil {
+		writeError(w, r, err, "Error reordering projects")
+		return
+	}
+
+	json.NewEncoder(w).Encode(projects)
+}
+
+// @Summary Delete a project by ID
+// @Description Deletes a project of the calling user, its tasks are kept without a project
+// @ID delete-project
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} string "Successfully deleted project"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Project not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /projects/{id} [delete]
+func DeleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteProject(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting project")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// @Summary Create a task from free text
+// @Description Parses a task written as free text, such as "Call John at 2:30 pm on Monday !high #work remind me 15m before", into its title,
+// @Description due date, priority, tags, contact and reminders, in the time zone of the request or else of the user, and creates it.
+// @Description With dryRun the task is only parsed, so that it can be confirmed, and the warnings list what would fail its creation.
+// @Description The tokens break down how each part of the text was understood.
+// @ID quick-add-task
+// @Accept json
+// @Produce json
+// @Param request body models.QuickAddRequest true "Text of the task"
+// @Param Idempotency-Key header string false "Unique key of the request, to safely retry it"
+// @Success 200 {object} models.QuickAddResult "Parsed task, on a dry run"
+// @Success 201 {object} models.QuickAddResult "Created task"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/quickAdd [post]
+func QuickAddHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.QuickAddRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	result, err := controllers.QuickAdd(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err, "Error adding task")
+		return
+	}
+
+	if result.Created {
+		w.Header().Set("Location", "/v1/tasks/"+result.Task.ID)
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// @Summary Get the reminders of a task
+// @Description Lists the reminders of a task by date
+// @ID get-reminders
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.Reminder "Reminders of the task"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/reminders [get]
+func GetRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	reminders, err := controllers.GetReminders(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving reminders")
+		return
+	}
+
+	json.NewEncoder(w).Encode(reminders)
+}
+
+// @Summary Add a reminder
+// @Description Adds a reminder to a task. The date must be in the future and differ from the other reminders of the task.
+// @ID create-reminder
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminder body models.Reminder true "Reminder date, in RFC3339 format"
+// @Success 201 {object} models.Reminder "Created reminder"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 409 {object} problem "The task already has a reminder at this date"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/reminders [post]
+func CreateReminderHandler(w http.ResponseWriter, r *http.Request) {
+	var newReminder models.Reminder
+	err := json.NewDecoder(r.Body).Decode(&newReminder)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	reminder, err := controllers.CreateReminder(r.Context(), chi.URLParam(r, "id"), newReminder)
+	if err != nil {
+		writeError(w, r, err, "Error creating reminder")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reminder)
+}
+
+// @Summary Update a reminder
+// @Description Moves a reminder to another date. The date must be in the future and differ from the other reminders of the task.
+// @ID update-reminder
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Param reminder body models.Reminder true "Reminder date, in RFC3339 format"
+// @Success 200 {object} models.Reminder "Updated reminder"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task or reminder not found"
+// @Failure 409 {object} problem "The task already has a reminder at this date"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/reminders/{reminderID} [put]
+func UpdateReminderHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedReminder models.Reminder
+	err := json.NewDecoder(r.Body).Decode(&updatedReminder)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	reminder, err := controllers.UpdateReminder(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "reminderID"), updatedReminder)
+	if err != nil {
+		writeError(w, r, err, "Error updating reminder")
+		return
+	}
+
+	json.NewEncoder(w).Encode(reminder)
+}
+
+// @Summary Delete a reminder
+// @Description Removes a reminder from a task
+// @ID delete-reminder
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Success 200 {string} string "Successfully deleted reminder"
+// @Failure 404 {object} problem "Task or reminder not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/reminders/{reminderID} [delete]
+func DeleteReminderHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteReminder(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "reminderID"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting reminder")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
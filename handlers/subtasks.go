@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// reorderChecklistRequest is the body of the checklist reorder endpoint.
+type reorderChecklistRequest struct {
+	ItemIDs []string `json:"itemIDs"`
+}
+
+// @Summary Complete a task
+// @Description Marks a task as completed. With cascade, its subtasks and checklist items are completed too.
+// @ID complete-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param cascade query bool false "Also complete subtasks and checklist items"
+// @Success 200 {object} models.Task "Successfully completed task"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 409 {object} problem "Task is blocked"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/complete [post]
+func CompleteTaskHandler(w http.ResponseWriter, r *http.Request) {
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	task, err := controllers.CompleteTask(r.Context(), chi.URLParam(r, "id"), cascade)
+	if err != nil {
+		writeError(w, r, err, "Error completing task")
+		return
+	}
+
+	json.NewEncoder(w).Encode(task)
+}
+
+// @Summary Reopen a task
+// @Description Marks a completed task as open again
+// @ID reopen-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task "Successfully reopened task"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/reopen [post]
+func ReopenTaskHandler(w http.ResponseWriter, r *http.Request) {
+	task, err := controllers.ReopenTask(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error reopening task")
+		return
+	}
+
+	json.NewEncoder(w).Encode(task)
+}
+
+// @Summary Add a checklist item
+// @Description Appends an item to the checklist of a task
+// @ID create-checklist-item
+// @Accept json
+// @Produce json
+// @Param item body models.ChecklistItem true "Checklist item details"
+// @Success 201 {object} models.ChecklistItem "Successfully created checklist item"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /checklistItems [post]
+func CreateChecklistItemHandler(w http.ResponseWriter, r *http.Request) {
+	var newItem models.ChecklistItem
+	err := json.NewDecoder(r.Body).Decode(&newItem)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	item, err := controllers.CreateChecklistItem(r.Context(), newItem)
+	if err != nil {
+		writeError(w, r, err, "Error creating checklist item")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// @Summary Update a checklist item
+// @Description Updates the text and state of a checklist item
+// @ID update-checklist-item
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Param item body models.ChecklistItem true "Updated checklist item"
+// @Success 200 {object} models.ChecklistItem "Successfully updated checklist item"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Checklist item not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /checklistItems/{id} [put]
+func UpdateChecklistItemHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedItem models.ChecklistItem
+	err := json.NewDecoder(r.Body).Decode(&updatedItem)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	item, err := controllers.UpdateChecklistItem(r.Context(), chi.URLParam(r, "id"), updatedItem)
+	if err != nil {
+		writeError(w, r, err, "Error updating checklist item")
+		return
+	}
+
+	json.NewEncoder(w).Encode(item)
+}
+
+// @Summary Toggle a checklist item
+// @Description Flips a checklist item between done and not done
+// @ID toggle-checklist-item
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Success 200 {object} models.ChecklistItem "Successfully toggled checklist item"
+// @Failure 404 {object} problem "Checklist item not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /checklistItems/{id}/toggle [post]
+func ToggleChecklistItemHandler(w http.ResponseWriter, r *http.Request) {
+	item, err := controllers.ToggleChecklistItem(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error toggling checklist item")
+		return
+	}
+
+	json.NewEncoder(w).Encode(item)
+}
+
+// @Summary Reorder a checklist
+// @Description Sets the order of the checklist items of a task
+// @ID reorder-checklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param items body reorderChecklistRequest true "Checklist item IDs in their new order"
+// @Success 200 {array} models.ChecklistItem "Checklist in its new order"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/checklist/order [put]
+func ReorderChecklistHandler(w http.ResponseWriter, r *http.Request) {
+	var req reorderChecklistRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	items, err := controllers.ReorderChecklist(r.Context(), chi.URLParam(r, "id"), req.ItemIDs)
+	if err != nil {
+		writeError(w, r, err, "Error reordering checklist")
+		return
+	}
+
+	json.NewEncoder(w).Encode(items)
+}
+
+// @Summary Delete a checklist item
+// @Description Removes an item from the checklist of a task
+// @ID delete-checklist-item
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Success 200 {object} string "Successfully deleted checklist item"
+// @Failure 404 {object} problem "Checklist item not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /checklistItems/{id} [delete]
+func DeleteChecklistItemHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteChecklistItem(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting checklist item")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// tagIDsRequest is the body of the attach, detach and merge endpoints.
+type tagIDsRequest struct {
+	TagIDs []string `json:"tagIDs"`
+}
+
+// @Summary Create a new tag
+// @Description Creates a tag for the calling user, or for the workspace of the request (X-Workspace-ID header) when workspaceID is set
+// @ID create-tag
+// @Accept json
+// @Produce json
+// @Param tag body models.Tag true "Tag details"
+// @Success 201 {object} models.Tag "Successfully created tag"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 403 {object} problem "workspaceID is not the workspace of the request"
+// @Failure 409 {object} problem "Tag already exists"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags [post]
+func CreateTagHandler(w http.ResponseWriter, r *http.Request) {
+	var newTag models.Tag
+	err := json.NewDecoder(r.Body).Decode(&newTag)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+	tag, err := controllers.CreateTag(r.Context(), newTag)
+	if err != nil {
+		writeError(w, r, err, "Error creating tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tag)
+}
+
+// @Summary Get all tags
+// @Description Retrieves the tags of the workspace of the request, or the private tags of the calling user
+// @ID get-all-tags
+// @Produce json
+// @Param workspaceID query string false "Workspace ID, which must be the one of the X-Workspace-ID header"
+// @Success 200 {array} models.Tag "Successfully retrieved tags"
+// @Failure 403 {object} problem "workspaceID is not the workspace of the request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags [get]
+func GetAllTagsHandler(w http.ResponseWriter, r *http.Request) {
+	tags, err := controllers.GetAllTags(r.Context(), r.URL.Query().Get("workspaceID"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving tags")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tags)
+}
+
+// @Summary Get a tag by ID
+// @Description Retrieves a tag by its unique identifier
+// @ID get-tag
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.Tag "Successfully retrieved tag"
+// @Failure 404 {object} problem "Tag not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags/{id} [get]
+func GetTagHandler(w http.ResponseWriter, r *http.Request) {
+	tag, err := controllers.GetTag(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving tag")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tag)
+}
+
+// @Summary Update a tag by ID
+// @Description Renames or recolors a tag
+// @ID update-tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param tag body models.Tag true "Updated tag details"
+// @Success 200 {object} models.Tag "Successfully updated tag"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Tag not found"
+// @Failure 409 {object} problem "Tag already exists"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags/{id} [put]
+func UpdateTagHandler(w http.ResponseWriter, r *http.Request) {
+	tagID := chi.URLParam(r, "id")
+
+	var updatedTag models.Tag
+	err := json.NewDecoder(r.Body).Decode(&updatedTag)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	tag, err := controllers.UpdateTag(r.Context(), tagID, updatedTag)
+	if err != nil {
+		writeError(w, r, err, "Error updating tag")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tag)
+}
+
+// @Summary Delete a tag by ID
+// @Description Deletes a tag and detaches it from all tasks
+// @ID delete-tag
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} string "Successfully deleted tag"
+// @Failure 404 {object} problem "Tag not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags/{id} [delete]
+func DeleteTagHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteTag(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Merge tags
+// @Description Moves the tasks of the given tags onto the target tag and deletes the merged tags
+// @ID merge-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Target tag ID"
+// @Param tags body tagIDsRequest true "IDs of the tags to merge into the target"
+// @Success 200 {object} models.Tag "Successfully merged tags"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Tag not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tags/{id}/merge [post]
+func MergeTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var req tagIDsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	tag, err := controllers.MergeTags(r.Context(), chi.URLParam(r, "id"), req.TagIDs)
+	if err != nil {
+		writeError(w, r, err, "Error merging tags")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tag)
+}
+
+// @Summary Attach tags to a task
+// @Description Attaches tags to a task, ignoring tags that are already attached
+// @ID attach-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param tags body tagIDsRequest true "IDs of the tags to attach"
+// @Success 200 {array} models.Tag "Tags of the task"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Task or tag not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/tags [post]
+func AttachTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var req tagIDsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	tags, err := controllers.AttachTags(r.Context(), chi.URLParam(r, "id"), req.TagIDs)
+	if err != nil {
+		writeError(w, r, err, "Error attaching tags")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tags)
+}
+
+// @Summary Detach tags from a task
+// @Description Detaches tags from a task
+// @ID detach-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param tags body tagIDsRequest true "IDs of the tags to detach"
+// @Success 200 {array} models.Tag "Tags of the task"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 404 {object} problem "Task or tag not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/tags [delete]
+func DetachTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var req tagIDsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	tags, err := controllers.DetachTags(r.Context(), chi.URLParam(r, "id"), req.TagIDs)
+	if err != nil {
+		writeError(w, r, err, "Error detaching tags")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tags)
+}
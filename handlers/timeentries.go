@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// reportDateLayout is the layout of the from and to parameters of the time report.
+const reportDateLayout = "2006-01-02"
+
+// startTimerRequest is the body of the start timer endpoint.
+type startTimerRequest struct {
+	Note string `json:"note"`
+}
+
+// @Summary Start a timer
+// @Description Starts tracking the time the calling user spends on a task. A user can only run one timer at a time.
+// @ID start-timer
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param timer body startTimerRequest false "Optional note"
+// @Success 201 {object} models.TimeEntry "Running timer"
+// @Failure 400 {object} problem "Note too long"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 409 {object} problem "A timer is already running"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/timer [post]
+func StartTimerHandler(w http.ResponseWriter, r *http.Request) {
+	var req startTimerRequest
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			badRequest(w, r, "Invalid request body")
+			return
+		}
+	}
+
+	entry, err := controllers.StartTimer(r.Context(), chi.URLParam(r, "id"), req.Note)
+	if err != nil {
+		writeError(w, r, err, "Error starting timer")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary Stop the running timer
+// @Description Stops the running timer of the calling user
+// @ID stop-timer
+// @Produce json
+// @Success 200 {object} models.TimeEntry "Stopped time entry"
+// @Failure 404 {object} problem "No timer is running"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /timers/current [delete]
+func StopTimerHandler(w http.ResponseWriter, r *http.Request) {
+	entry, err := controllers.StopTimer(r.Context())
+	if err != nil {
+		writeError(w, r, err, "Error stopping timer")
+		return
+	}
+
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary Get the running timer
+// @Description Retrieves the running timer of the calling user
+// @ID get-running-timer
+// @Produce json
+// @Success 200 {object} models.TimeEntry "Running timer"
+// @Failure 404 {object} problem "No timer is running"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /timers/current [get]
+func GetRunningTimerHandler(w http.ResponseWriter, r *http.Request) {
+	entry, err := controllers.GetRunningTimer(r.Context())
+	if err != nil {
+		writeError(w, r, err, "Error retrieving timer")
+		return
+	}
+
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary Add a time entry
+// @Description Records time the calling user spent on a task without a timer, from a start time and either an end time or a duration in seconds
+// @ID create-time-entry
+// @Accept json
+// @Produce json
+// @Param entry body models.TimeEntry true "Time entry details"
+// @Success 201 {object} models.TimeEntry "Successfully created time entry"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 404 {object} problem "Task not found"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /timeEntries [post]
+func CreateTimeEntryHandler(w http.ResponseWriter, r *http.Request) {
+	var newEntry models.TimeEntry
+	err := json.NewDecoder(r.Body).Decode(&newEntry)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	entry, err := controllers.CreateTimeEntry(r.Context(), newEntry)
+	if err != nil {
+		writeError(w, r, err, "Error creating time entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary Get the time entries of a task
+// @Description Retrieves the time entries of a task, most recent first
+// @ID get-time-entries
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.TimeEntry "Successfully retrieved time entries"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/{id}/timeEntries [get]
+func GetTimeEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	entries, err := controllers.GetTimeEntries(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving time entries")
+		return
+	}
+
+	json.NewEncoder(w).Encode(entries)
+}
+
+// @Summary Delete a time entry
+// @Description Removes a time entry of the calling user
+// @ID delete-time-entry
+// @Produce json
+// @Param id path string true "Time entry ID"
+// @Success 200 {string} string "Successfully deleted time entry"
+// @Failure 404 {object} problem "Time entry not found"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /timeEntries/{id} [delete]
+func DeleteTimeEntryHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.DeleteTimeEntry(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error deleting time entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get a time report
+// @Description Sums the time entries of the calling user started in a date range by project, user, task or date. The range defaults to the last 30 days.
+// @ID get-time-report
+// @Produce json
+// @Param groupBy query string true "project, user, task or date"
+// @Param from query string false "First day, as YYYY-MM-DD"
+// @Param to query string false "Last day, as YYYY-MM-DD"
+// @Param projectID query string false "Project ID"
+// @Success 200 {array} models.TimeReportRow "Time spent per group"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "The request does not identify the user"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /reports/time [get]
+func GetTimeReportHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.TimeReportFilter{
+		GroupBy:   query.Get("groupBy"),
+		ProjectID: query.Get("projectID"),
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	filter.From = today.AddDate(0, 0, -29)
+	filter.To = today.AddDate(0, 0, 1)
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			badRequest(w, r, "Invalid from date")
+			return
+		}
+		filter.From = from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			badRequest(w, r, "Invalid to date")
+			return
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	report, err := controllers.GetTimeReport(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving time report")
+		return
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
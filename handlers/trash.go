@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+)
+
+// @Summary Get the trash
+// @Description Lists the tasks in the trash, most recently deleted first. They are purged after the retention period.
+// @ID get-trash
+// @Produce json
+// @Param tags query string false "Comma separated tag IDs"
+// @Param tagMode query string false "and (default) or or"
+// @Param projectID query string false "Project ID"
+// @Param contactID query string false "Contact ID"
+// @Param userID query string false "ID of the owner of the tasks"
+// @Success 200 {array} models.Task "Tasks in the trash"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/trash [get]
+func GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
+	tasks, err := controllers.GetTrash(filter)
+	if err != nil {
+		writeError(w, r, err, "Error retrieving trash")
+		return
+	}
+
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// @Summary Restore a task
+// @Description Moves a task out of the trash, with the subtasks deleted along with it
+// @ID restore-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task "Restored task"
+// @Failure 404 {object} problem "Task not found in the trash"
+// @Failure 409 {object} problem "Parent task is in the trash"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/trash/{id}/restore [post]
+func RestoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	task, err := controllers.RestoreTask(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error restoring task")
+		return
+	}
+
+	json.NewEncoder(w).Encode(task)
+}
+
+// @Summary Purge a task
+// @Description Permanently deletes a task in the trash, with its subtasks, reminders and attachments
+// @ID purge-task
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {string} string "Successfully purged task"
+// @Failure 404 {object} problem "Task not found in the trash"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /tasks/trash/{id} [delete]
+func PurgeTaskHandler(w http.ResponseWriter, r *http.Request) {
+	err := controllers.PurgeTask(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error purging task")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
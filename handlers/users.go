@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// @Summary Create a new user
+// @Description Creates a user that can be mentioned with @username
+// @ID create-user
+// @Accept json
+// @Produce json
+// @Param user body models.User true "User details"
+// @Success 201 {object} models.User "Successfully created user"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 409 {object} problem "Username already taken"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /users [post]
+func CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var newUser models.User
+	err := json.NewDecoder(r.Body).Decode(&newUser)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	user, err := controllers.CreateUser(newUser)
+	if err != nil {
+		writeError(w, r, err, "Error creating user")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// @Summary Get a user by ID
+// @Description Retrieves a user by its unique identifier
+// @ID get-user
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User "Successfully retrieved user"
+// @Failure 404 {object} problem "User not found"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /users/{id} [get]
+func GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := controllers.GetUser(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error retrieving user")
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
+// @Summary Update a user by ID
+// @Description Replaces the username, name, email and time zone of the calling user. The time zone is the one of
+// @Description the dates of quick add and of the calendar feed.
+// @ID update-user
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID, which must be the one of the X-User-ID header"
+// @Param user body models.User true "Updated user details"
+// @Success 200 {object} models.User "Successfully updated user"
+// @Failure 400 {object} problem "Bad request"
+// @Failure 401 {object} problem "Missing X-User-ID header"
+// @Failure 403 {object} problem "The user is not the calling user"
+// @Failure 404 {object} problem "User not found"
+// @Failure 409 {object} problem "Username already taken"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /users/{id} [put]
+func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedUser models.User
+	err := json.NewDecoder(r.Body).Decode(&updatedUser)
+	if err != nil {
+		badRequest(w, r, "Invalid request body")
+		return
+	}
+
+	user, err := controllers.UpdateUser(r.Context(), chi.URLParam(r, "id"), updatedUser)
+	if err != nil {
+		writeError(w, r, err, "Error updating user")
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
+// @Summary Get all users
+// @Description Retrieves all users ordered by username
+// @ID get-all-users
+// @Produce json
+// @Success 200 {array} models.User "Successfully retrieved users"
+// @Failure 500 {object} problem "Internal server error"
+// @Router /users [get]
+func GetAllUsersHandler(w http.ResponseWriter, r *http.Request) {
+	users, err := controllers.GetAllUsers()
+	if err != nil {
+		writeError(w, r, err, "Error retrieving users")
+		return
+	}
+
+	json.NewEncoder(w).Encode(users)
+}
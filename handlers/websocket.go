@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Limits of board connections
+const (
+	wsWriteWait      = 10 * time.Second // Longest time to write a message
+	wsMaxMessageSize = 64 << 10         // Largest command accepted
+	wsSendBuffer     = 64               // Messages queued for a client before it is disconnected
+)
+
+// Commands sent by board clients
+const (
+	wsSubscribe           = "subscribe"   // Follow the tasks and projects listed
+	wsUnsubscribe         = "unsubscribe" // Stop following them
+	wsMoveTask            = "moveTask"    // Move a task to another project, or out of projects
+	wsCompleteTask        = "completeTask"
+	wsReopenTask          = "reopenTask"
+	wsToggleChecklistItem = "toggleChecklistItem"
+)
+
+// Messages sent to board clients
+const (
+	wsResult = "result" // Reply to a successful command
+	wsError  = "error"  // Reply to a failed command
+	wsEvent  = "event"  // Change of a followed task
+	wsReset  = "reset"  // Events may have been missed, reload the board
+)
+
+// wsCommand is a command sent by a board client
+type wsCommand struct {
+	Type      string   `json:"type"`
+	ID        string   `json:"id"` // Chosen by the client, and echoed in the reply
+	Tasks     []string `json:"tasks,omitempty"`
+	Projects  []string `json:"projects,omitempty"`
+	TaskID    string   `json:"taskID,omitempty"`
+	ProjectID string   `json:"projectID,omitempty"` // Project to move the task to
+	Version   int64    `json:"version,omitempty"`   // Version the move is based on, like If-Match
+	Cascade   bool     `json:"cascade,omitempty"`   // Also complete subtasks and checklist items
+	ItemID    string   `json:"itemID,omitempty"`    // Checklist item to toggle
+}
+
+// wsMessage is a message sent to a board client
+type wsMessage struct {
+	Type          string                `json:"type"`
+	ID            string                `json:"id,omitempty"` // ID of the command replied to
+	Task          *models.Task          `json:"task,omitempty"`
+	ChecklistItem *models.ChecklistItem `json:"checklistItem,omitempty"`
+	Event         *models.Event         `json:"event,omitempty"`
+	Error         *problem              `json:"error,omitempty"`
+}
+
+// The API allows all origins, and requests are not authenticated by cookies
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// boardConn is the connection of a board client.
+type boardConn struct {
+	ws     *websocket.Conn
+	r      *http.Request // Upgraded request, carrying the user
+	userID string
+	send   chan wsMessage
+	done   chan struct{}
+	once   sync.Once
+
+	mu       sync.Mutex
+	tasks    map[string]bool
+	projects map[string]bool
+}
+
+// @Summary Connect a task board
+// @Description Upgrades to a WebSocket exchanging JSON messages. Clients send commands with an ID echoed in the reply:
+// @Description subscribe and unsubscribe with tasks and projects to follow, moveTask with taskID, projectID and an
+// @Description optional version, completeTask and reopenTask with taskID, and toggleChecklistItem with itemID.
+// @Description Replies are result or error messages, changes of followed tasks are event messages, and a reset
+// @Description message means events were missed. Only the tasks of the user are followed. Like every request, the
+// @Description user is identified by the X-User-ID header, which the gateway sets for browsers from their session.
+// @ID connect-board
+// @Success 101 {string} string "Switching to the WebSocket protocol"
+// @Failure 401 {object} problem "Anonymous request"
+// @Router /ws [get]
+func BoardSocketHandler(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r.Context())
+	if userID == "" {
+		writeError(w, r, controllers.ErrUnauthorized, "")
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has replied
+		return
+	}
+
+	conn := &boardConn{
+		ws:       ws,
+		r:        r,
+		userID:   userID,
+		send:     make(chan wsMessage, wsSendBuffer),
+		done:     make(chan struct{}),
+		tasks:    make(map[string]bool),
+		projects: make(map[string]bool),
+	}
+	go conn.writeMessages()
+	go conn.forwardEvents()
+	conn.readCommands()
+}
+
+// readCommands runs the commands of the client one at a time, until the
+// connection closes. A client sending commands faster than they run is
+// slowed down by the connection.
+func (c *boardConn) readCommands() {
+	defer c.close()
+
+	c.ws.SetReadLimit(wsMaxMessageSize)
+	// The client answers the pings of writeMessages
+	pongWait := 2 * config.EventHeartbeat
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var cmd wsCommand
+		var reply wsMessage
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			reply = c.errorMessage("", problem{Status: http.StatusBadRequest, Detail: "Invalid command", Code: CodeBadRequest})
+		} else {
+			reply = c.run(cmd)
+		}
+
+		select {
+		case c.send <- reply:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// run runs a command through the controllers, like the matching route.
+func (c *boardConn) run(cmd wsCommand) wsMessage {
+	ctx := c.r.Context()
+	reply := wsMessage{Type: wsResult, ID: cmd.ID}
+	var err error
+
+	switch cmd.Type {
+	case wsSubscribe, wsUnsubscribe:
+		c.mu.Lock()
+		for _, id := range cmd.Tasks {
+			c.tasks[id] = cmd.Type == wsSubscribe
+		}
+		for _, id := range cmd.Projects {
+			c.projects[id] = cmd.Type == wsSubscribe
+		}
+		c.mu.Unlock()
+	case wsMoveTask:
+		var task models.Task
+		task, err = moveTask(ctx, cmd.TaskID, cmd.ProjectID, cmd.Version)
+		reply.Task = &task
+	case wsCompleteTask:
+		var task models.Task
+		task, err = controllers.CompleteTask(ctx, cmd.TaskID, cmd.Cascade)
+		reply.Task = &task
+	case wsReopenTask:
+		var task models.Task
+		task, err = controllers.ReopenTask(ctx, cmd.TaskID)
+		reply.Task = &task
+	case wsToggleChecklistItem:
+		var item models.ChecklistItem
+		item, err = controllers.ToggleChecklistItem(ctx, cmd.ItemID)
+		reply.ChecklistItem = &item
+	default:
+		return c.errorMessage(cmd.ID, problem{Status: http.StatusBadRequest, Detail: "Unknown command type", Code: CodeBadRequest})
+	}
+
+	if err != nil {
+		return c.errorMessage(cmd.ID, errorProblem(c.r, err, "Error running command"))
+	}
+	return reply
+}
+
+// moveTask moves a task to another project, as a merge patch would.
+func moveTask(ctx context.Context, taskID, projectID string, version int64) (models.Task, error) {
+	patch, err := json.Marshal(map[string]string{"projectID": projectID})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return controllers.PatchTask(ctx, taskID, controllers.MergePatch, patch, version)
+}
+
+func (c *boardConn) errorMessage(id string, p problem) wsMessage {
+	p = completeProblem(c.r, p)
+	return wsMessage{Type: wsError, ID: id, Error: &p}
+}
+
+// follows reports whether the client follows the task of an event, or the
+// project it belongs or belonged to. Only the tasks of the user are followed,
+// whatever the client subscribes to.
+func (c *boardConn) follows(event models.Event) bool {
+	if event.UserID != c.userID {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tasks[event.TaskID] || c.projects[event.ProjectID] ||
+		(event.PreviousProjectID != "" && c.projects[event.PreviousProjectID])
+}
+
+// forwardEvents queues the events followed by the client. A client too slow
+// to receive them is disconnected.
+func (c *boardConn) forwardEvents() {
+	sub := config.Events.Subscribe(c.follows, 0)
+	var lastEventID int64
+	for {
+		select {
+		case <-c.done:
+			sub.Close()
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped by the broker for lagging, resume from its buffer
+				sub = config.Events.Subscribe(c.follows, lastEventID)
+				if sub.Lost || lastEventID == 0 {
+					c.queue(wsMessage{Type: wsReset})
+				}
+				for _, event := range sub.Missed {
+					event := event
+					lastEventID = event.ID
+					c.queue(wsMessage{Type: wsEvent, Event: &event})
+				}
+				continue
+			}
+			lastEventID = event.ID
+			c.queue(wsMessage{Type: wsEvent, Event: &event})
+		}
+	}
+}
+
+// queue queues a message without blocking, and disconnects the client if
+// its queue is full.
+func (c *boardConn) queue(message wsMessage) {
+	select {
+	case c.send <- message:
+	default:
+		c.ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"), time.Now().Add(wsWriteWait))
+		c.close()
+	}
+}
+
+// writeMessages writes the queued messages, and pings the client while the
+// connection is idle.
+func (c *boardConn) writeMessages() {
+	defer c.close()
+
+	ping := time.NewTicker(config.EventHeartbeat)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case message := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.ws.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *boardConn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
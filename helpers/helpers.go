@@ -1,10 +1,12 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/vikash-parashar/task-manager-2/config"
 	"github.com/vikash-parashar/task-manager-2/controllers"
 	"github.com/vikash-parashar/task-manager-2/models"
 )
@@ -16,7 +18,7 @@ func CheckReminders() {
 		currentTime := time.Now()
 
 		// Query tasks with reminders due
-		tasks, err := controllers.GetTasksWithDueReminders(currentTime)
+		tasks, err := controllers.GetTasksWithDueReminders(currentTime, models.TaskFilter{})
 		if err != nil {
 			log.Println("Error querying tasks with due reminders:", err)
 		}
@@ -34,9 +36,9 @@ func CheckReminders() {
 // sendNotification checks the notification method and sends the notification.
 func sendNotification(task models.Task) {
 	switch task.NotifyMethod {
-	case "email":
+	case models.NotifyMethodEmail:
 		sendEmailNotification(task)
-	case "push":
+	case models.NotifyMethodPush:
 		sendPushNotification(task)
 	default:
 		log.Printf("Unknown notification method for task %s: %s", task.ID, task.NotifyMethod)
@@ -54,3 +56,82 @@ func sendPushNotification(task models.Task) {
 	//TODO: Implement push notification logic
 	fmt.Printf("Sending push notification for task %s\n", task.ID)
 }
+
+// NotifyMention notifies a user mentioned in a comment on a task, by email
+// when the user has an email address, by push otherwise.
+func NotifyMention(task models.Task, mentioned models.User, comment models.Comment) {
+	message := fmt.Sprintf("@%s, you were mentioned in a comment on %q", mentioned.Username, task.Title)
+
+	log.Printf("Notifying user %s of mention in comment %s", mentioned.ID, comment.ID)
+	if mentioned.Email != "" {
+		sendUserEmail(mentioned, message)
+	} else {
+		sendUserPush(mentioned, message)
+	}
+}
+
+// sendUserEmail sends an email to a user.
+func sendUserEmail(user models.User, message string) {
+	//TODO: Implement email notification logic
+	fmt.Printf("Sending email to %s: %s\n", user.Email, message)
+}
+
+// sendUserPush sends a push notification to a user.
+func sendUserPush(user models.User, message string) {
+	//TODO: Implement push notification logic
+	fmt.Printf("Sending push notification to user %s: %s\n", user.ID, message)
+}
+
+// PurgeTrash periodically purges the tasks that have been in the trash for
+// longer than the retention period.
+func PurgeTrash() {
+	for {
+		purged, err := controllers.PurgeTrash(context.Background(), time.Now().Add(-config.TrashRetention))
+		if err != nil {
+			log.Println("Error purging trash:", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d tasks from the trash", purged)
+		}
+
+		time.Sleep(time.Hour)
+	}
+}
+
+// PurgeIdempotencyKeys periodically deletes the Idempotency-Keys older than
+// their time to live.
+func PurgeIdempotencyKeys() {
+	for {
+		purged, err := controllers.PurgeIdempotencyKeys(time.Now().Add(-config.IdempotencyKeyTTL))
+		if err != nil {
+			log.Println("Error purging idempotency keys:", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d idempotency keys", purged)
+		}
+
+		time.Sleep(time.Hour)
+	}
+}
+
+// FireReminders periodically fires the reminders that came due, streaming
+// an event and notifying the task for each. Reminders more than an hour
+// late, e.g. while the server was down, are not fired.
+func FireReminders() {
+	for {
+		now := time.Now()
+		fired, err := controllers.FireDueReminders(now.Add(-time.Hour), now)
+		if err != nil {
+			log.Println("Error firing reminders:", err)
+		}
+
+		for _, event := range fired {
+			task, err := controllers.GetTask(event.TaskID, 0)
+			if err != nil {
+				log.Printf("Error getting task %s of fired reminder: %v", event.TaskID, err)
+				continue
+			}
+			sendNotification(task)
+		}
+
+		time.Sleep(time.Minute)
+	}
+}
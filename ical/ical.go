@@ -0,0 +1,158 @@
+// Package ical writes iCalendar (RFC 5545) data, such as the calendar feed
+// of the tasks of a user.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest line allowed, in octets, before folding
+const maxLineLength = 75
+
+// Component is an iCalendar component such as VCALENDAR or VEVENT, with its
+// properties and subcomponents in order
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Property is a property of a component. Params are written as is, e.g.
+// "TZID=Europe/Paris", and Value must already be escaped when it is text.
+type Property struct {
+	Name   string
+	Params []string
+	Value  string
+}
+
+// NewComponent returns an empty component.
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add adds a property to the component.
+func (c *Component) Add(name, value string, params ...string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText adds a text property, escaped.
+func (c *Component) AddText(name, value string) {
+	c.Add(name, Text(value))
+}
+
+// AddComponent adds a subcomponent.
+func (c *Component) AddComponent(sub *Component) {
+	c.Components = append(c.Components, sub)
+}
+
+// Encode writes the component as iCalendar data, with CRLF line endings and
+// long lines folded.
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c *Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		line := p.Name
+		for _, param := range p.Params {
+			line += ";" + param
+		}
+		writeLine(w, line+":"+p.Value)
+	}
+	for _, sub := range c.Components {
+		sub.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine writes a content line, folded every maxLineLength octets
+// without splitting UTF-8 sequences.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Text escapes a text value.
+func Text(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// List escapes values and joins them into a multi-valued text, such as
+// CATEGORIES.
+func List(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = Text(value)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// UTC formats a time in UTC, e.g. 20261026T133000Z.
+func UTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// DateTime returns the value and parameters of a time in the given time
+// zone, e.g. 20261026T143000 with TZID=Europe/Paris, or in UTC for the UTC
+// time zone.
+func DateTime(t time.Time, loc *time.Location) (string, []string) {
+	if loc == time.UTC {
+		return UTC(t), nil
+	}
+	return t.In(loc).Format("20060102T150405"), []string{"TZID=" + loc.String()}
+}
+
+// Duration formats a duration, e.g. -PT15M or P1DT2H.
+func Duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	value := sign + "P"
+	if days > 0 {
+		value += fmt.Sprintf("%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 || days == 0 {
+		value += "T"
+		if hours > 0 {
+			value += fmt.Sprintf("%dH", hours)
+		}
+		if minutes > 0 {
+			value += fmt.Sprintf("%dM", minutes)
+		}
+		if seconds > 0 || (hours == 0 && minutes == 0) {
+			value += fmt.Sprintf("%dS", seconds)
+		}
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// encode returns the iCalendar data of a component.
+func encode(t *testing.T, c *Component) string {
+	t.Helper()
+	var b strings.Builder
+	err := c.Encode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestText(t *testing.T) {
+	for _, tt := range []struct {
+		name, value, want string
+	}{
+		{"plain", "Call John", "Call John"},
+		{"comma", "milk, eggs", `milk\, eggs`},
+		{"semicolon", "a;b", `a\;b`},
+		{"backslash", `C:\tasks`, `C:\\tasks`},
+		{"escaped already", `\,`, `\\\,`},
+		{"newline", "first\nsecond", `first\nsecond`},
+		{"crlf", "first\r\nsecond", `first\nsecond`},
+		{"carriage return", "first\rsecond", `first\nsecond`},
+		{"colon kept", "Re: call", "Re: call"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.value); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	got := List([]string{"home", "a,b", `c\d`})
+	want := `home,a\,b,c\\d`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value string   // Value of a SUMMARY property, 8 octets with "SUMMARY:"
+		want  []string // Lines written, without CRLF
+	}{
+		{
+			name:  "short",
+			value: "Call John",
+			want:  []string{"SUMMARY:Call John"},
+		},
+		{
+			name:  "75 octets",
+			value: strings.Repeat("a", 67),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67)},
+		},
+		{
+			name:  "76 octets",
+			value: strings.Repeat("a", 68),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67), " a"},
+		},
+		{
+			name:  "continuation lines",
+			value: strings.Repeat("a", 67+74+10),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67), " " + strings.Repeat("a", 74), " " + strings.Repeat("a", 10)},
+		},
+		{
+			// é is 2 octets, the 75th octet is its first one
+			name:  "multi-byte character at the limit",
+			value: strings.Repeat("a", 66) + "é" + "b",
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 66), " éb"},
+		},
+		{
+			// € is 3 octets, 25 of them fill 75 octets
+			name:  "multi-byte characters",
+			value: strings.Repeat("€", 30),
+			want:  []string{"SUMMARY:" + strings.Repeat("€", 22), " " + strings.Repeat("€", 8)},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponent("VTODO")
+			c.AddText("SUMMARY", tt.value)
+			got := strings.Split(strings.TrimSuffix(encode(t, c), "\r\n"), "\r\n")
+			want := append(append([]string{"BEGIN:VTODO"}, tt.want...), "END:VTODO")
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("got %q, want %q", got, want)
+			}
+			for _, line := range got {
+				if len(line) > maxLineLength {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{15 * time.Minute, "PT15M"},
+		{-15 * time.Minute, "-PT15M"},
+		{26 * time.Hour, "P1DT2H"},
+		{48 * time.Hour, "P2D"},
+		{90*time.Minute + 5*time.Second, "PT1H30M5S"},
+	} {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies of the recurrence rules accepted by Recurrence
+var frequencies = map[string]bool{
+	"DAILY":   true,
+	"WEEKLY":  true,
+	"MONTHLY": true,
+	"YEARLY":  true,
+}
+
+var weekdays = map[string]bool{
+	"MO": true, "TU": true, "WE": true, "TH": true, "FR": true, "SA": true, "SU": true,
+}
+
+// Recurrence checks a recurrence rule, the value of an RRULE property such
+// as FREQ=WEEKLY;BYDAY=MO,WE, and returns it in upper case. An "RRULE:"
+// prefix is removed. Only the DAILY, WEEKLY, MONTHLY and YEARLY frequencies
+// and the INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH parts are
+// accepted. UNTIL is a date or a time in UTC, as RFC 5545 requires when the
+// start has a time zone.
+func Recurrence(rule string) (string, error) {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	rule = strings.TrimPrefix(rule, "RRULE:")
+	if rule == "" {
+		return "", errors.New("is empty")
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return "", fmt.Errorf("has an invalid part %q", part)
+		}
+		if _, ok := parts[name]; ok {
+			return "", fmt.Errorf("has %s more than once", name)
+		}
+		parts[name] = value
+	}
+
+	freq := parts["FREQ"]
+	if !frequencies[freq] {
+		return "", errors.New("must have FREQ set to DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	for name, value := range parts {
+		var err error
+		switch name {
+		case "FREQ":
+		case "INTERVAL", "COUNT":
+			err = checkNumbers(value, 1, 1<<16, false)
+		case "UNTIL":
+			_, err = time.Parse("20060102T150405Z", value)
+			if err != nil {
+				_, err = time.Parse("20060102", value)
+			}
+		case "BYDAY":
+			err = checkWeekdays(value, freq == "MONTHLY" || freq == "YEARLY")
+		case "BYMONTHDAY":
+			err = checkNumbers(value, 1, 31, true)
+		case "BYMONTH":
+			err = checkNumbers(value, 1, 12, false)
+		default:
+			return "", fmt.Errorf("has an unsupported part %s", name)
+		}
+		if err != nil {
+			return "", fmt.Errorf("has an invalid %s", name)
+		}
+	}
+	if parts["COUNT"] != "" && parts["UNTIL"] != "" {
+		return "", errors.New("must not have both COUNT and UNTIL")
+	}
+	return rule, nil
+}
+
+// checkNumbers checks a comma-separated list of numbers between min and
+// max, or between -max and -min too when negative is set.
+func checkNumbers(value string, min, max int, negative bool) error {
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return err
+		}
+		if negative && n < 0 {
+			n = -n
+		}
+		if n < min || n > max {
+			return fmt.Errorf("%d is out of range", n)
+		}
+	}
+	return nil
+}
+
+// checkWeekdays checks a comma-separated list of weekdays, e.g. MO or -1FR
+// when ordinals are allowed.
+func checkWeekdays(value string, ordinals bool) error {
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 || !weekdays[item[len(item)-2:]] {
+			return fmt.Errorf("invalid weekday %q", item)
+		}
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			if !ordinals {
+				return fmt.Errorf("invalid weekday %q", item)
+			}
+			err := checkNumbers(ordinal, 1, 53, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
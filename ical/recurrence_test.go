@@ -0,0 +1,76 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+func TestRecurrence(t *testing.T) {
+	for _, tt := range []struct {
+		rule string
+		want string // Empty when the rule is invalid
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"freq=weekly;byday=mo,we", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"RRULE:FREQ=MONTHLY;BYDAY=-1FR", "FREQ=MONTHLY;BYDAY=-1FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;INTERVAL=2", "FREQ=MONTHLY;BYMONTHDAY=-1;INTERVAL=2"},
+		{"FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25;COUNT=3", "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25;COUNT=3"},
+		{"FREQ=WEEKLY;UNTIL=20261231T230000Z", "FREQ=WEEKLY;UNTIL=20261231T230000Z"},
+		{"FREQ=WEEKLY;UNTIL=20261231", "FREQ=WEEKLY;UNTIL=20261231"},
+		{"", ""},
+		{"BYDAY=MO", ""},
+		{"FREQ=HOURLY", ""},
+		{"FREQ=DAILY;FREQ=WEEKLY", ""},
+		{"FREQ=DAILY;INTERVAL=0", ""},
+		{"FREQ=DAILY;COUNT=x", ""},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20261231", ""},
+		{"FREQ=WEEKLY;UNTIL=20261231T230000", ""}, // Local time
+		{"FREQ=WEEKLY;BYDAY=1MO", ""},             // Ordinal in a weekly rule
+		{"FREQ=WEEKLY;BYDAY=XX", ""},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", ""},
+		{"FREQ=YEARLY;BYMONTH=13", ""},
+		{"FREQ=DAILY;BYHOUR=9", ""},
+		{"FREQ=DAILY;", ""},
+	} {
+		got, err := Recurrence(tt.rule)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Recurrence(%q) = %q, want an error", tt.rule, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Recurrence(%q) = %q, %v, want %q", tt.rule, got, err, tt.want)
+		}
+	}
+}
+
+func TestFeedRecurrence(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := models.Task{
+		ID:          "1",
+		Title:       "Stand-up",
+		DueDateTime: time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC),
+		Recurrence:  "FREQ=WEEKLY;BYDAY=MO",
+	}
+	got := encode(t, Feed([]models.Task{task}, FeedOptions{Location: loc, Components: []string{Event, Todo}}))
+
+	for _, want := range []string{
+		"BEGIN:VEVENT\r\nUID:1-event\r\n",
+		"DTSTART;TZID=Europe/Paris:20260302T090000\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\nEND:VEVENT",
+		"DTSTART;TZID=Europe/Paris:20260302T090000\r\nDUE;TZID=Europe/Paris:20260302T090000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO\r\nEND:VTODO",
+		// Occurrences after the due date are covered by the time zone
+		"DTSTART:20451029T030000\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("feed does not contain %q:\n%s", want, got)
+		}
+	}
+}
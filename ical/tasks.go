@@ -0,0 +1,177 @@
+package ical
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// Components a task can be written as
+const (
+	Event = "VEVENT" // An event at the due date, shown in the calendar
+	Todo  = "VTODO"  // A to-do due at the due date, shown in the to-do list
+)
+
+// prodID identifies the application writing the calendars
+const prodID = "-//task-manager-2//Tasks//EN"
+
+// priorities maps the priorities of tasks to iCalendar priorities, 1 being
+// the highest
+var priorities = map[string]string{
+	"urgent":    "1",
+	"important": "2",
+	"high":      "3",
+	"medium":    "5",
+	"low":       "9",
+}
+
+// FeedOptions configure the calendar of a feed
+type FeedOptions struct {
+	Name            string         // Shown by calendar apps, e.g. Tasks
+	Location        *time.Location // Time zone of the dates, UTC when nil
+	Components      []string       // Event and/or Todo, Event when empty
+	RefreshInterval time.Duration  // How often calendar apps fetch the feed again, if set
+}
+
+// Feed returns the calendar of the tasks with a due date. Each task is an
+// event and/or a to-do, with an alarm for each of its reminders, repeated
+// by the recurrence rule of recurring tasks.
+func Feed(tasks []models.Task, opts FeedOptions) *Component {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	components := opts.Components
+	if len(components) == 0 {
+		components = []string{Event}
+	}
+
+	cal := NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", prodID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	cal.Add("METHOD", "PUBLISH")
+	if opts.Name != "" {
+		cal.AddText("NAME", opts.Name)
+		cal.AddText("X-WR-CALNAME", opts.Name)
+	}
+	if loc != time.UTC {
+		cal.AddText("X-WR-TIMEZONE", loc.String())
+	}
+	if opts.RefreshInterval > 0 {
+		cal.Add("REFRESH-INTERVAL", Duration(opts.RefreshInterval), "VALUE=DURATION")
+		cal.Add("X-PUBLISHED-TTL", Duration(opts.RefreshInterval))
+	}
+
+	// The time zone covers every date written in it
+	var first, last time.Time
+	var entries []*Component
+	for _, task := range tasks {
+		if task.DueDateTime.IsZero() {
+			continue
+		}
+		for _, date := range append([]time.Time{task.DueDateTime}, reminderDates(task)...) {
+			if first.IsZero() || date.Before(first) {
+				first = date
+			}
+			if date.After(last) {
+				last = date
+			}
+		}
+		// Occurrences of recurring tasks go on, Timezone bounds them
+		if task.Recurrence != "" {
+			if end := task.DueDateTime.AddDate(maxTimezoneYears, 0, 0); end.After(last) {
+				last = end
+			}
+		}
+		for _, name := range components {
+			entries = append(entries, taskComponent(task, name, loc))
+		}
+	}
+	if loc != time.UTC && len(entries) > 0 {
+		cal.AddComponent(Timezone(loc, first, last))
+	}
+	cal.Components = append(cal.Components, entries...)
+	return cal
+}
+
+// taskComponent returns the event or to-do of a task.
+func taskComponent(task models.Task, name string, loc *time.Location) *Component {
+	c := NewComponent(name)
+	c.Add("UID", task.ID+"-"+strings.ToLower(strings.TrimPrefix(name, "V")))
+	c.Add("DTSTAMP", UTC(task.UpdatedAt))
+	c.Add("LAST-MODIFIED", UTC(task.UpdatedAt))
+	c.Add("SEQUENCE", strconv.FormatInt(task.Version, 10))
+	c.AddText("SUMMARY", task.Title)
+	if task.Description != "" {
+		c.AddText("DESCRIPTION", task.Description)
+	}
+	if priority, ok := priorities[strings.ToLower(task.Priority)]; ok {
+		c.Add("PRIORITY", priority)
+	}
+	if len(task.Tags) > 0 {
+		names := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			names[i] = tag.Name
+		}
+		c.Add("CATEGORIES", List(names))
+	}
+
+	due, params := DateTime(task.DueDateTime, loc)
+	// Alarms of to-dos are relative to their due date, the end of the to-do
+	triggerParams := []string(nil)
+	if name == Event {
+		c.Add("DTSTART", due, params...)
+		if task.EstimateSeconds > 0 {
+			c.Add("DURATION", Duration(time.Duration(task.EstimateSeconds)*time.Second))
+		}
+	} else {
+		// A recurring to-do needs a start for its occurrences
+		if task.Recurrence != "" {
+			c.Add("DTSTART", due, params...)
+		}
+		c.Add("DUE", due, params...)
+		if task.Status == models.TaskStatusCompleted {
+			c.Add("STATUS", "COMPLETED")
+			if task.CompletedAt != nil {
+				c.Add("COMPLETED", UTC(*task.CompletedAt))
+			}
+			c.Add("PERCENT-COMPLETE", "100")
+		} else {
+			c.Add("STATUS", "NEEDS-ACTION")
+			c.Add("PERCENT-COMPLETE", strconv.Itoa(int(math.Round(task.Progress*100))))
+		}
+		if task.ParentID != "" {
+			c.Add("RELATED-TO", task.ParentID+"-todo")
+		}
+		triggerParams = []string{"RELATED=END"}
+	}
+	if task.Recurrence != "" {
+		c.Add("RRULE", task.Recurrence)
+	}
+
+	for _, date := range reminderDates(task) {
+		alarm := NewComponent("VALARM")
+		alarm.Add("ACTION", "DISPLAY")
+		alarm.AddText("DESCRIPTION", task.Title)
+		alarm.Add("TRIGGER", Duration(date.Sub(task.DueDateTime)), triggerParams...)
+		c.AddComponent(alarm)
+	}
+	return c
+}
+
+// reminderDates returns the dates of the reminders of a task, skipping the
+// invalid ones.
+func reminderDates(task models.Task) []time.Time {
+	var dates []time.Time
+	for _, reminder := range task.Reminders {
+		date, err := time.Parse(time.RFC3339, reminder.Date)
+		if err == nil {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
@@ -0,0 +1,87 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxTimezoneYears bounds the years whose transitions are listed by
+// Timezone, so that a far away date does not list thousands of them.
+const maxTimezoneYears = 20
+
+// Timezone returns the VTIMEZONE component of a time zone, listing its
+// transitions between the start of the year of from and the end of the year
+// of to, so that calendar apps read the times in that zone as Go does. At
+// most maxTimezoneYears years are listed, later times keep the last offset.
+func Timezone(loc *time.Location, from, to time.Time) *Component {
+	tz := NewComponent("VTIMEZONE")
+	tz.Add("TZID", loc.String())
+
+	startYear := from.In(loc).Year()
+	endYear := to.In(loc).Year() + 1
+	if endYear > startYear+maxTimezoneYears {
+		endYear = startYear + maxTimezoneYears
+	}
+	start := time.Date(startYear, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(endYear, time.January, 1, 0, 0, 0, 0, loc)
+
+	// The offset in effect at the start, then every change of offset. Offsets
+	// change at most once a day.
+	_, offset := start.Zone()
+	tz.AddComponent(observance(start, offset))
+	for t := start; t.Before(end); {
+		next := t.Add(24 * time.Hour)
+		if _, nextOffset := next.Zone(); nextOffset != offset {
+			transition := findTransition(t, next)
+			tz.AddComponent(observance(transition, offset))
+			_, offset = transition.Zone()
+		}
+		t = next
+	}
+	return tz
+}
+
+// findTransition returns the first second between before and after with the
+// offset of after.
+func findTransition(before, after time.Time) time.Time {
+	_, offset := after.Zone()
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2).Truncate(time.Second)
+		if _, midOffset := mid.Zone(); midOffset == offset {
+			after = mid
+		} else {
+			before = mid
+		}
+	}
+	return after
+}
+
+// observance returns the STANDARD or DAYLIGHT component of the offset
+// starting at t, replacing the offset from.
+func observance(t time.Time, from int) *Component {
+	name, offset := t.Zone()
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	o := NewComponent(kind)
+	// DTSTART is the local time before the transition
+	o.Add("DTSTART", t.In(time.FixedZone("", from)).Format("20060102T150405"))
+	o.Add("TZOFFSETFROM", utcOffset(from))
+	o.Add("TZOFFSETTO", utcOffset(offset))
+	o.AddText("TZNAME", name)
+	return o
+}
+
+// utcOffset formats an offset in seconds east of UTC, e.g. +0100.
+func utcOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	value := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	if offset%60 != 0 {
+		value += fmt.Sprintf("%02d", offset%60)
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // Time zones do not depend on the system
+)
+
+func TestTimezone(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		zone     string
+		from, to time.Time
+		want     string
+	}{
+		{
+			name: "daylight saving time",
+			zone: "Europe/Paris",
+			from: time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, time.November, 2, 9, 0, 0, 0, time.UTC),
+			want: `BEGIN:VTIMEZONE
+TZID:Europe/Paris
+BEGIN:STANDARD
+DTSTART:20260101T000000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0100
+TZNAME:CET
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20260329T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+TZNAME:CEST
+END:DAYLIGHT
+BEGIN:STANDARD
+DTSTART:20261025T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+TZNAME:CET
+END:STANDARD
+END:VTIMEZONE
+`,
+		},
+		{
+			name: "west of UTC",
+			zone: "America/New_York",
+			from: time.Date(2026, time.January, 10, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, time.January, 20, 9, 0, 0, 0, time.UTC),
+			want: `BEGIN:VTIMEZONE
+TZID:America/New_York
+BEGIN:STANDARD
+DTSTART:20260101T000000
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0500
+TZNAME:EST
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20260308T020000
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0400
+TZNAME:EDT
+END:DAYLIGHT
+BEGIN:STANDARD
+DTSTART:20261101T020000
+TZOFFSETFROM:-0400
+TZOFFSETTO:-0500
+TZNAME:EST
+END:STANDARD
+END:VTIMEZONE
+`,
+		},
+		{
+			name: "no transitions",
+			zone: "Asia/Kolkata",
+			from: time.Date(2026, time.January, 10, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2027, time.June, 10, 9, 0, 0, 0, time.UTC),
+			want: `BEGIN:VTIMEZONE
+TZID:Asia/Kolkata
+BEGIN:STANDARD
+DTSTART:20260101T000000
+TZOFFSETFROM:+0530
+TZOFFSETTO:+0530
+TZNAME:IST
+END:STANDARD
+END:VTIMEZONE
+`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := encode(t, Timezone(loc, tt.from, tt.to))
+			want := strings.ReplaceAll(tt.want, "\n", "\r\n")
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestTimezoneYears(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name     string
+		to       time.Time
+		lastYear int
+	}{
+		{"to the end of the year of to", time.Date(2028, time.January, 5, 9, 0, 0, 0, time.UTC), 2028},
+		{"at most maxTimezoneYears", time.Date(2200, time.January, 5, 9, 0, 0, 0, time.UTC), 2026 + maxTimezoneYears - 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tz := Timezone(loc, from, tt.to)
+			// The initial observance, then two transitions a year
+			years := tt.lastYear - 2026 + 1
+			if got, want := len(tz.Components), 1+2*years; got != want {
+				t.Fatalf("got %d observances, want %d", got, want)
+			}
+			last := tz.Components[len(tz.Components)-1].Properties[0].Value
+			if !strings.HasPrefix(last, strconv.Itoa(tt.lastYear)) {
+				t.Errorf("got last transition %s, want one in %d", last, tt.lastYear)
+			}
+		})
+	}
+}
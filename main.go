@@ -3,14 +3,19 @@ package main
 import (
 	"fmt"
 	"net/http"
+	_ "time/tzdata" // Time zones of the users, on hosts without a zoneinfo database
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/vikash-parashar/task-manager-2/auth"
 	"github.com/vikash-parashar/task-manager-2/config"
 	"github.com/vikash-parashar/task-manager-2/handlers"
+	"github.com/vikash-parashar/task-manager-2/helpers"
 	"github.com/vikash-parashar/task-manager-2/models"
+	"github.com/vikash-parashar/task-manager-2/routes"
+	"github.com/vikash-parashar/task-manager-2/rpc"
 )
 
 // @title Task API
@@ -34,13 +39,57 @@ func main() {
 		panic(err)
 	}
 
+	// Initialize the attachment storage
+	err = config.InitStorage()
+	if err != nil {
+		panic(err)
+	}
+
+	// Stream the task events notified by the database
+	config.InitEvents()
+
+	// Fire reminders, and purge the trash and expired idempotency keys in the
+	// background
+	go helpers.FireReminders()
+	go helpers.PurgeTrash()
+	go helpers.PurgeIdempotencyKeys()
+
+	// Serve the gRPC TaskService on its own port
+	go func() {
+		fmt.Printf("gRPC server is running on port %d...\n", config.GRPCPort)
+		err := rpc.ListenAndServe(fmt.Sprintf(":%d", config.GRPCPort))
+		if err != nil {
+			fmt.Println("gRPC error:", err)
+		}
+	}()
+
 	r := chi.NewRouter()
+	// Tag every request with an ID, recorded in the audit log
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	// Allow all CORS requests
-	corsHandler := cors.AllowAll()
+	// Allow all CORS requests, and let browsers read the ETag and Location
+	// of tasks, the deprecation headers of legacy routes and whether a
+	// response was replayed
+	corsHandler := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{"ETag", "Location", "Deprecation", "Sunset", "Link", "Idempotent-Replayed"},
+	})
 	// Use CORS middleware
 	r.Use(corsHandler.Handler)
+	// Identify the calling user
+	r.Use(auth.Middleware)
+	// Replay the responses to retried requests with an Idempotency-Key
+	r.Use(handlers.Idempotent)
 
 	// Serve the Swagger UI at /swagger/index.html
 	r.Get("/swagger/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -52,69 +101,14 @@ func main() {
 		httpSwagger.URL("/swagger.json"),
 	))
 
-	// @Summary Create a new task
-	// @Description Creates a new task with the specified details
-	// @ID create-task
-	// @Produce json
-	// @Param task body Task true "Task details"
-	// @Success 201 {object} Task "Successfully created task"
-	// @Failure 400 {object} ErrorResponse "Bad request"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/create [post]
-	r.Post("/tasks/create", handlers.CreateTaskHandler)
-
-	// @Summary Get a task by ID
-	// @Description Retrieves a task by its unique identifier
-	// @ID get-task
-	// @Produce json
-	// @Param id path string true "Task ID"
-	// @Success 200 {object} Task "Successfully retrieved task"
-	// @Failure 404 {object} ErrorResponse "Task not found"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/get/{id} [get]
-	r.Get("/tasks/get/{id}", handlers.GetTaskHandler)
-
-	// @Summary Update a task by ID
-	// @Description Updates a task with the specified details
-	// @ID update-task
-	// @Produce json
-	// @Param id path string true "Task ID"
-	// @Param task body Task true "Task details"
-	// @Success 200 {object} Task "Successfully updated task"
-	// @Failure 400 {object} ErrorResponse "Bad request"
-	// @Failure 404 {object} ErrorResponse "Task not found"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/update/{id} [put]
-	r.Put("/tasks/update/{id}", handlers.UpdateTaskHandler)
-
-	// @Summary Delete a task by ID
-	// @Description Deletes a task by its unique identifier
-	// @ID delete-task
-	// @Produce json
-	// @Param id path string true "Task ID"
-	// @Success 200 {object} SuccessResponse "Successfully deleted task"
-	// @Failure 404 {object} ErrorResponse "Task not found"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/delete/{id} [delete]
-	r.Delete("/tasks/delete/{id}", handlers.DeleteTaskHandler)
-
-	// @Summary Get all tasks
-	// @Description Retrieves a list of all tasks
-	// @ID get-all-tasks
-	// @Produce json
-	// @Success 200 {array} Task "Successfully retrieved tasks"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/getAll [get]
-	r.Get("/tasks/getAll", handlers.GetAllTasksHandler)
+	// Versioned routes, see routes/v1.go
+	r.Route("/v1", routes.V1)
 
-	// @Summary Get tasks with due reminders
-	// @Description Retrieves a list of tasks with due reminders
-	// @ID get-tasks-with-due-reminders
-	// @Produce json
-	// @Success 200 {array} Task "Successfully retrieved tasks with due reminders"
-	// @Failure 500 {object} ErrorResponse "Internal server error"
-	// @Router /tasks/dueReminders [get]
-	r.Get("/tasks/dueReminders", handlers.GetTasksWithDueReminder)
+	// Unversioned routes, kept until their sunset
+	r.Group(func(r chi.Router) {
+		r.Use(handlers.Deprecated(config.LegacyRoutesDeprecated, config.LegacyRoutesSunset, "/v1"))
+		legacyRoutes(r)
+	})
 
 	port := 8080
 	fmt.Printf("Server is running on port %d...\n", port)
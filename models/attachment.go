@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Attachment is a file attached to a task. Its content is kept in the blob
+// storage under StorageKey.
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"taskID"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"contentType"` // Sniffed from the content
+	Size        int64     `json:"size"`        // In bytes
+	Checksum    string    `json:"checksum"`    // Hex encoded SHA-256 of the content
+	UploadedBy  string    `json:"uploadedBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	StorageKey  string    `json:"-"`
+}
+
+// createAttachmentTables creates the Attachment table.
+func createAttachmentTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS attachments (
+			id VARCHAR(36) PRIMARY KEY,
+			task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			filename VARCHAR(255) NOT NULL,
+			content_type VARCHAR(255) NOT NULL,
+			size BIGINT NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			uploaded_by VARCHAR(36) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			storage_key VARCHAR(255) NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Attachment table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS attachments_task_id_idx ON attachments (task_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create Attachment index: %v", err)
+	}
+
+	return nil
+}
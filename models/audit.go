@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditCreate   = "create"
+	AuditUpdate   = "update"
+	AuditDelete   = "delete"
+	AuditComplete = "complete"
+	AuditReopen   = "reopen"
+	AuditRestore  = "restore" // Out of the trash
+	AuditPurge    = "purge"   // Permanently deleted from the trash
+)
+
+// Audited entity types
+const (
+	AuditEntityTask          = "task"
+	AuditEntityReminder      = "reminder"
+	AuditEntityTag           = "tag" // A tag attached to or detached from a task
+	AuditEntityChecklistItem = "checklist_item"
+	AuditEntityAttachment    = "attachment"
+)
+
+// AuditEntry records a mutation of a task or of what belongs to it: its
+// reminders, tags, checklist items and attachments. Before and After
+// hold the fields that changed: the whole entity for a creation or a
+// deletion, only the differing fields for an update.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	ActorID    string          `json:"actorID"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityID"`
+	TaskID     string          `json:"taskID"` // Task the entity is or belongs to
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID  string          `json:"requestID"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditFilter selects audit entries. Empty fields match everything.
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	TaskID     string
+	RequestID  string
+	From, To   time.Time // Entries created in [From, To)
+	BeforeID   int64     // Only entries older than this one, to page through the log
+	Limit      int
+}
+
+// createAuditTables creates the audit log. It is append-only: a trigger
+// rejects any update or deletion of its rows.
+func createAuditTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGSERIAL PRIMARY KEY,
+			actor_id VARCHAR(36) NOT NULL DEFAULT '',
+			action VARCHAR(20) NOT NULL,
+			entity_type VARCHAR(20) NOT NULL,
+			entity_id VARCHAR(36) NOT NULL,
+			task_id VARCHAR(36) NOT NULL,
+			before_data JSONB,
+			after_data JSONB,
+			request_id VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create AuditLog table: %v", err)
+	}
+
+	// Times are in UTC, like the filters of the audit endpoint. Tables
+	// created before defaulted to the local time of the server.
+	_, err = db.Exec(`ALTER TABLE audit_log ALTER COLUMN created_at SET DEFAULT (NOW() AT TIME ZONE 'UTC')`)
+	if err != nil {
+		return fmt.Errorf("failed to update AuditLog table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_log is append-only';
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create AuditLog trigger function: %v", err)
+	}
+
+	err = createTrigger(db, "audit_log_append_only",
+		"BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only()")
+	if err != nil {
+		return err
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS audit_log_task_id_idx ON audit_log (task_id, id)`,
+		`CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id, id)`,
+		`CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, id)`,
+		`CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at)`,
+	} {
+		_, err = db.Exec(index)
+		if err != nil {
+			return fmt.Errorf("failed to create AuditLog index: %v", err)
+		}
+	}
+
+	return nil
+}
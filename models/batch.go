@@ -0,0 +1,32 @@
+package models
+
+// Batch operations on tasks
+const (
+	BatchCreate   = "create"
+	BatchUpdate   = "update"
+	BatchDelete   = "delete"
+	BatchComplete = "complete"
+)
+
+// Batch modes
+const (
+	BatchModeAtomic     = "atomic"     // All operations succeed or none is applied
+	BatchModeBestEffort = "bestEffort" // Each operation is applied on its own
+)
+
+// BatchRequest lists operations to apply to tasks in one request
+type BatchRequest struct {
+	Mode       string           `json:"mode"` // BatchModeAtomic (default) or BatchModeBestEffort
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is an operation of a batch. Task holds the new task for a
+// creation and the whole updated task for an update.
+type BatchOperation struct {
+	Op      string `json:"op"`                // BatchCreate, BatchUpdate, BatchDelete or BatchComplete
+	ID      string `json:"id,omitempty"`      // Task updated, deleted or completed
+	Version int64  `json:"version,omitempty"` // Version the update or deletion is based on, like If-Match
+	Cascade bool   `json:"cascade,omitempty"` // Also complete subtasks and checklist items
+	Task    *Task  `json:"task,omitempty"`
+	TaskErr error  `json:"-"` // Why the task could not be decoded, failing the operation alone
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CalendarFeed is the secret URL of the iCalendar feed of the tasks of a
+// user, for calendar apps to subscribe to
+type CalendarFeed struct {
+	UserID    string    `json:"userID"`
+	Token     string    `json:"token"` // Secret part of the URL, only known when the feed is created
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// createCalendarTables creates the table of calendar feeds and the
+// recurrence rule of tasks. A user has at most one feed, identified by the
+// hash of its token so that a leaked database does not leak the feeds.
+func createCalendarTables(db *sql.DB) error {
+	_, err := db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence VARCHAR(255) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add recurrence to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS calendar_feeds (
+			user_id VARCHAR(36) PRIMARY KEY,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create CalendarFeed table: %v", err)
+	}
+
+	return nil
+}
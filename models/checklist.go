@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ChecklistItem is a single step of a task
+type ChecklistItem struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"taskID"`
+	Text     string `json:"text"`
+	Done     bool   `json:"done"`
+	Position int    `json:"position"`
+}
+
+// createSubtaskTables creates the ChecklistItem table and the columns
+// linking a subtask to its parent.
+func createSubtaskTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		ALTER TABLE tasks
+			ADD COLUMN IF NOT EXISTS parent_id VARCHAR(36) REFERENCES tasks(id) ON DELETE CASCADE,
+			ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'open',
+			ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add subtask fields to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS tasks_parent_id_idx ON tasks (parent_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create Task parent index: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS checklist_items (
+			id VARCHAR(36) PRIMARY KEY,
+			task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			text VARCHAR(255) NOT NULL,
+			done BOOLEAN NOT NULL DEFAULT FALSE,
+			position INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ChecklistItem table: %v", err)
+	}
+
+	return nil
+}
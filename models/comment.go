@@ -0,0 +1,121 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Comment is a Markdown message posted on a task
+type Comment struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"taskID"`
+	AuthorID  string     `json:"authorID"`
+	Body      string     `json:"body"`     // Markdown
+	Mentions  []string   `json:"mentions"` // IDs of the users mentioned with @username
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"` // Set once the comment has been edited
+}
+
+// CommentRevision is a previous version of an edited comment
+type CommentRevision struct {
+	ID        string    `json:"id"`
+	CommentID string    `json:"commentID"`
+	Body      string    `json:"body"`
+	EditedBy  string    `json:"editedBy"`
+	EditedAt  time.Time `json:"editedAt"` // When this version was replaced
+}
+
+// Activity types
+const (
+	ActivityComment   = "comment"
+	ActivityCreated   = "created"
+	ActivityChanged   = "changed"
+	ActivityCompleted = "completed"
+	ActivityReopened  = "reopened"
+	ActivityDeleted   = "deleted" // Moved to the trash
+	ActivityRestored  = "restored"
+)
+
+// Activity is an entry of the activity feed of a task: either a comment or
+// a change made to the task
+type Activity struct {
+	Type      string    `json:"type"`
+	ActorID   string    `json:"actorID"`
+	CreatedAt time.Time `json:"createdAt"`
+	Comment   *Comment  `json:"comment,omitempty"`  // For ActivityComment
+	Field     string    `json:"field,omitempty"`    // For ActivityChanged
+	OldValue  string    `json:"oldValue,omitempty"` // For ActivityChanged
+	NewValue  string    `json:"newValue,omitempty"` // For ActivityChanged
+}
+
+// createCommentTables creates the tables holding comments, their history
+// and mentions, and the changes made to tasks.
+func createCommentTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS comments (
+			id VARCHAR(36) PRIMARY KEY,
+			task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			author_id VARCHAR(36) NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Comment table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS comments_task_id_idx ON comments (task_id, created_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to create Comment index: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS comment_revisions (
+			id VARCHAR(36) PRIMARY KEY,
+			comment_id VARCHAR(36) NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+			body TEXT NOT NULL,
+			edited_by VARCHAR(36) NOT NULL DEFAULT '',
+			edited_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create CommentRevision table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS comment_mentions (
+			comment_id VARCHAR(36) REFERENCES comments(id) ON DELETE CASCADE,
+			user_id VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (comment_id, user_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create CommentMention table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_activity (
+			id BIGSERIAL PRIMARY KEY,
+			task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			actor_id VARCHAR(36) NOT NULL DEFAULT '',
+			type VARCHAR(20) NOT NULL,
+			field VARCHAR(50) NOT NULL DEFAULT '',
+			old_value TEXT NOT NULL DEFAULT '',
+			new_value TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create TaskActivity table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS task_activity_task_id_idx ON task_activity (task_id, created_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to create TaskActivity index: %v", err)
+	}
+
+	return nil
+}
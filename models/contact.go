@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Contact is a person tasks are about, e.g. the John of "Call John"
+type Contact struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userID"` // Owner of the contact, set from the request
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// createContactTables creates the Contact table and links tasks to it.
+func createContactTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS contacts (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id VARCHAR(36) NOT NULL DEFAULT '',
+			name VARCHAR(255) NOT NULL,
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			phone VARCHAR(50) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Contact table: %v", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS contact_id VARCHAR(36) REFERENCES contacts(id) ON DELETE SET NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add contact_id to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS tasks_contact_id_idx ON tasks (contact_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create Task contact index: %v", err)
+	}
+
+	return nil
+}
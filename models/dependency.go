@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Dependency states that a task cannot start before another one is completed
+type Dependency struct {
+	TaskID      string `json:"taskID"`
+	BlockedByID string `json:"blockedByID"`
+}
+
+// createDependencyTables creates the TaskDependency table.
+func createDependencyTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id VARCHAR(36) REFERENCES tasks(id) ON DELETE CASCADE,
+			blocked_by_id VARCHAR(36) REFERENCES tasks(id) ON DELETE CASCADE,
+			PRIMARY KEY (task_id, blocked_by_id),
+			CHECK (task_id <> blocked_by_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create TaskDependency table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS task_dependencies_blocked_by_idx ON task_dependencies (blocked_by_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create TaskDependency index: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EventChannel is the Postgres channel task events are notified on, so
+// that every instance of the server receives them
+const EventChannel = "task_events"
+
+// Event types
+const (
+	EventTaskCreated   = "task.created" // Also sent when a task is restored from the trash
+	EventTaskUpdated   = "task.updated"
+	EventTaskDeleted   = "task.deleted" // Moved to the trash
+	EventReminderFired = "reminder.fired"
+)
+
+// Event is a change of a task, streamed to the owner of the task and to
+// the clients following the task or its project. IDs increase across all
+// instances of the server.
+type Event struct {
+	ID         int64     `json:"id"`
+	Type       string    `json:"type"`
+	TaskID     string    `json:"taskID"`
+	ReminderID string    `json:"reminderID,omitempty"` // Reminder that fired
+	UserID     string    `json:"userID"`               // Owner of the task
+	ProjectID  string    `json:"projectID"`
+	Version    int64     `json:"version,omitempty"` // Version of the task after the change
+	OccurredAt time.Time `json:"occurredAt"`
+
+	PreviousProjectID string `json:"previousProjectID,omitempty"` // Set when the task moved to another project
+}
+
+// createEventTables creates the trigger notifying the changes of tasks on
+// EventChannel, and the table of fired reminders. Notifications are sent
+// when the transaction commits, so rolled back changes are not streamed.
+func createEventTables(db *sql.DB) error {
+	_, err := db.Exec(`CREATE SEQUENCE IF NOT EXISTS task_events_id_seq`)
+	if err != nil {
+		return fmt.Errorf("failed to create Event sequence: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION tasks_notify_event() RETURNS TRIGGER AS $$
+		DECLARE
+			event_type TEXT;
+		BEGIN
+			IF TG_OP = 'INSERT' OR (OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL) THEN
+				event_type := '` + EventTaskCreated + `';
+			ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+				event_type := '` + EventTaskDeleted + `';
+			ELSIF NEW.deleted_at IS NULL THEN
+				event_type := '` + EventTaskUpdated + `';
+			ELSE
+				-- Changes in the trash are not streamed
+				RETURN NULL;
+			END IF;
+
+			PERFORM pg_notify('` + EventChannel + `', json_build_object(
+				'id', nextval('task_events_id_seq'),
+				'type', event_type,
+				'taskID', NEW.id,
+				'userID', COALESCE(NEW.user_id, ''),
+				'projectID', COALESCE(NEW.project_id, ''),
+				'previousProjectID', CASE WHEN TG_OP = 'UPDATE' AND OLD.project_id IS DISTINCT FROM NEW.project_id
+					THEN COALESCE(OLD.project_id, '') END,
+				'version', NEW.version,
+				'occurredAt', NOW()
+			)::TEXT);
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Event function: %v", err)
+	}
+
+	err = createTrigger(db, "tasks_notify_event", "AFTER INSERT OR UPDATE ON tasks FOR EACH ROW EXECUTE PROCEDURE tasks_notify_event()")
+	if err != nil {
+		return err
+	}
+
+	// Dates of reminders are RFC3339 text. Legacy dates in other formats are
+	// NULL instead of failing the whole query that casts them.
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION reminder_date(date VARCHAR) RETURNS TIMESTAMPTZ AS $$
+		BEGIN
+			RETURN date::TIMESTAMPTZ;
+		EXCEPTION WHEN OTHERS THEN
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql STABLE
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder date function: %v", err)
+	}
+
+	// A reminder fires once per date, and again if it is moved
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS fired_reminders (
+			reminder_id VARCHAR(36) REFERENCES reminders(id) ON DELETE CASCADE,
+			date VARCHAR(20),
+			fired_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (reminder_id, date)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create FiredReminder table: %v", err)
+	}
+
+	return nil
+}
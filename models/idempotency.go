@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IdempotentResponse is the response stored for an Idempotency-Key, replayed
+// when the request is retried.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header // Only the headers worth replaying, such as Location
+	Body   []byte
+}
+
+// IdempotencyKey is a key sent by a user in the Idempotency-Key header.
+// Response is nil while the first request with the key is in progress.
+type IdempotencyKey struct {
+	UserID      string
+	Key         string
+	Fingerprint string // Hash of the method, path and body of the request
+	Response    *IdempotentResponse
+	CreatedAt   time.Time // In UTC
+}
+
+// createIdempotencyTables creates the table of idempotency keys. A key is
+// scoped to its user, anonymous requests sharing the empty user ID.
+func createIdempotencyTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id VARCHAR(36) NOT NULL DEFAULT '',
+			key VARCHAR(255) NOT NULL,
+			fingerprint VARCHAR(64) NOT NULL,
+			status_code INTEGER,
+			headers JSONB,
+			body BYTEA,
+			created_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+			PRIMARY KEY (user_id, key)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create IdempotencyKey table: %v", err)
+	}
+
+	// Times are in UTC, like the expiry times they are compared with. Tables
+	// created before defaulted to the local time of the server.
+	_, err = db.Exec(`ALTER TABLE idempotency_keys ALTER COLUMN created_at SET DEFAULT (NOW() AT TIME ZONE 'UTC')`)
+	if err != nil {
+		return fmt.Errorf("failed to update IdempotencyKey table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to create IdempotencyKey expiry index: %v", err)
+	}
+
+	return nil
+}
@@ -4,8 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// NewID returns a new identifier for a database row. IDs are UUIDv7, so
+// they sort in order of creation.
+func NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
 // Task represents a task with its details
 type Task struct {
 	ID          string     `json:"id"`
@@ -14,13 +22,64 @@ type Task struct {
 	Priority    string     `json:"priority"`
 	DueDateTime time.Time  `json:"dueDateTime"`
 	Reminders   []Reminder `json:"reminders"`
+	Tags        []Tag      `json:"tags"`
+	UserID      string     `json:"userID"` // Owner of the task, set from the request
+	ProjectID   string     `json:"projectID"`
+	ContactID   string     `json:"contactID"` // Person the task is about
+
+	// Subtask fields
+	ParentID    string          `json:"parentID"`
+	Status      string          `json:"status"` // TaskStatusOpen, TaskStatusBlocked or TaskStatusCompleted
+	CompletedAt *time.Time      `json:"completedAt"`
+	Checklist   []ChecklistItem `json:"checklist"`
+	Subtasks    []Task          `json:"subtasks,omitempty"`
+	Progress    float64         `json:"progress"`  // Share of checklist items and subtasks done, from 0 to 1
+	BlockedBy   []string        `json:"blockedBy"` // IDs of the tasks that must be completed first
+
+	// Recurrence rule of a recurring task, as an iCalendar RRULE value such as
+	// FREQ=WEEKLY;BYDAY=MO. The due date is the first occurrence.
+	Recurrence string `json:"recurrence"`
+
+	Attachments []Attachment `json:"attachments"`
+
+	DeletedAt *time.Time `json:"deletedAt,omitempty"` // Set while the task is in the trash, in UTC
+
+	// Concurrency fields
+	Version   int64     `json:"version"` // Incremented on every change, returned as the ETag
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Time tracking fields
+	EstimateSeconds  int64 `json:"estimateSeconds"`  // Original estimate
+	TimeSpentSeconds int64 `json:"timeSpentSeconds"` // Total of the time entries, including running timers
 
 	// Notification fields
-	NotifyMethod  string `json:"notifyMethod"`  // e.g., "email", "push"
+	NotifyMethod  string `json:"notifyMethod"`  // NotifyMethodEmail, NotifyMethodPush or empty
 	NotifyStatus  string `json:"notifyStatus"`  // e.g., "pending", "sent", "failed"
 	NotifyMessage string `json:"notifyMessage"` // Additional information about the notification
 }
 
+// Task statuses
+const (
+	TaskStatusOpen      = "open"
+	TaskStatusBlocked   = "blocked" // Waiting on an incomplete dependency
+	TaskStatusCompleted = "completed"
+)
+
+// Notification methods
+const (
+	NotifyMethodEmail = "email"
+	NotifyMethodPush  = "push"
+)
+
+// TaskFilter narrows down task listings
+type TaskFilter struct {
+	TagIDs    []string
+	TagMode   string // TagModeAnd or TagModeOr, defaults to TagModeAnd
+	ProjectID string
+	ContactID string
+	UserID    string // Owner of the tasks
+}
+
 // Reminder represents a reminder associated with a task
 type Reminder struct {
 	ID     string `json:"id"`
@@ -61,6 +120,100 @@ func CreateTables(db *sql.DB) error {
 		return fmt.Errorf("failed to create Reminder table: %v", err)
 	}
 
+	// Track the owner of each task
+	_, err = db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS user_id VARCHAR(36)`)
+	if err != nil {
+		return fmt.Errorf("failed to add user_id to Task table: %v", err)
+	}
+
+	// Store the notification fields of each task
+	_, err = db.Exec(`
+		ALTER TABLE tasks
+			ADD COLUMN IF NOT EXISTS notify_method VARCHAR(50) NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS notify_status VARCHAR(50) NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS notify_message VARCHAR(255) NOT NULL DEFAULT ''
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add notification fields to Task table: %v", err)
+	}
+
+	err = createTagTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createProjectTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createContactTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createSubtaskTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createDependencyTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createUserTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createCommentTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createAttachmentTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createTimeEntryTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createAuditTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createTrashTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createIdempotencyTables(db)
+	if err != nil {
+		return err
+	}
+
+	err = createCalendarTables(db)
+	if err != nil {
+		return err
+	}
+
+	// Last, as its triggers watch the tables created above
+	err = createVersionTables(db)
+	if err != nil {
+		return err
+	}
+
+	// Notifies the versions set by the triggers above
+	err = createEventTables(db)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("Tables created successfully")
 	return nil
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Project groups tasks into a list. Tasks created inside a project inherit
+// its defaults unless they set the fields themselves.
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UserID      string    `json:"userID"`
+	Archived    bool      `json:"archived"`
+	Position    int       `json:"position"` // Sort order among the user's projects
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// Defaults for new tasks
+	DefaultPriority        string   `json:"defaultPriority"`
+	DefaultNotifyMethod    string   `json:"defaultNotifyMethod"`
+	DefaultReminderOffsets []string `json:"defaultReminderOffsets"` // Durations before the due date, e.g., "15m", "1h"
+}
+
+// createProjectTables creates the Project table and links tasks to it.
+func createProjectTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS projects (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			description VARCHAR(255) NOT NULL DEFAULT '',
+			user_id VARCHAR(36) NOT NULL DEFAULT '',
+			archived BOOLEAN NOT NULL DEFAULT FALSE,
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			default_priority VARCHAR(50) NOT NULL DEFAULT '',
+			default_notify_method VARCHAR(50) NOT NULL DEFAULT '',
+			default_reminder_offsets TEXT[] NOT NULL DEFAULT '{}'
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Project table: %v", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id VARCHAR(36) REFERENCES projects(id) ON DELETE SET NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add project_id to Task table: %v", err)
+	}
+
+	return nil
+}
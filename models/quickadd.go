@@ -0,0 +1,39 @@
+package models
+
+// Kinds of the parts of a quick-add text
+const (
+	QuickAddDate     = "date"     // Day the task is due, e.g. "on Monday"
+	QuickAddTime     = "time"     // Time of day the task is due, e.g. "at 2:30 pm"
+	QuickAddRelative = "relative" // Due date relative to now, e.g. "in 2 hours"
+	QuickAddPriority = "priority" // e.g. "!high"
+	QuickAddTag      = "tag"      // e.g. "#work"
+	QuickAddContact  = "contact"  // e.g. "@john", or the name of a contact in the title
+	QuickAddReminder = "reminder" // e.g. "remind me 15 minutes before"
+)
+
+// QuickAddRequest is a task written as free text, such as "Call John at
+// 2:30 pm on Monday"
+type QuickAddRequest struct {
+	Text     string `json:"text"`
+	TimeZone string `json:"timeZone"` // IANA name such as Europe/Paris, defaults to the time zone of the user
+	DryRun   bool   `json:"dryRun"`   // Only parse the text, to confirm the task before creating it
+}
+
+// QuickAddResult is the task parsed from a quick-add text, and how the
+// text was understood
+type QuickAddResult struct {
+	Task     Task            `json:"task"`     // Created task, or the task that would be created on a dry run
+	Created  bool            `json:"created"`  // False on a dry run
+	TimeZone string          `json:"timeZone"` // Time zone the text was parsed in
+	Tokens   []QuickAddToken `json:"tokens"`   // Parts of the text understood
+	Warnings []string        `json:"warnings"` // Parts that could not be applied, e.g. an unknown contact
+}
+
+// QuickAddToken is a part of a quick-add text that sets a field of the
+// task
+type QuickAddToken struct {
+	Text  string `json:"text"`         // As written
+	Kind  string `json:"kind"`         // QuickAddDate, QuickAddTime, ...
+	Value string `json:"value"`        // What the text means, e.g. the date 2026-10-26, the time 14:30 or the offset 15m
+	ID    string `json:"id,omitempty"` // Tag or contact the text refers to
+}
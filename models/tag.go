@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Tag is a colored label that can be attached to any number of tasks.
+// A tag belongs to a workspace when WorkspaceID is set, otherwise it is
+// private to the user that created it.
+type Tag struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"` // e.g., "#ff9800"
+	UserID      string `json:"userID"`
+	WorkspaceID string `json:"workspaceID,omitempty"`
+}
+
+// Tag filter modes
+const (
+	TagModeAnd = "and" // task must carry every tag
+	TagModeOr  = "or"  // task must carry at least one of the tags
+)
+
+// createTagTables creates the Tag table and the Task/Tag association table.
+func createTagTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS tags (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			color VARCHAR(7) NOT NULL,
+			user_id VARCHAR(36) NOT NULL,
+			workspace_id VARCHAR(36) NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Tag table: %v", err)
+	}
+
+	// Tag names are unique within their scope (workspace or user), ignoring case
+	_, err = db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS tags_workspace_name_idx
+		ON tags (workspace_id, LOWER(name)) WHERE workspace_id <> ''
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Tag workspace index: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS tags_user_name_idx
+		ON tags (user_id, LOWER(name)) WHERE workspace_id = ''
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Tag user index: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_tags (
+			task_id VARCHAR(36) REFERENCES tasks(id) ON DELETE CASCADE,
+			tag_id VARCHAR(36) REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (task_id, tag_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create TaskTag table: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TimeEntry is time spent by a user on a task, either tracked with a timer
+// or entered manually. A running timer has no EndedAt yet.
+type TimeEntry struct {
+	ID              string     `json:"id"`
+	TaskID          string     `json:"taskID"`
+	UserID          string     `json:"userID"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationSeconds int64      `json:"durationSeconds"` // Up to now for a running timer
+	Note            string     `json:"note"`
+	Manual          bool       `json:"manual"`
+}
+
+// Time report groupings
+const (
+	GroupByProject = "project"
+	GroupByUser    = "user"
+	GroupByTask    = "task"
+	GroupByDate    = "date"
+)
+
+// TimeReportFilter selects the time entries of the calling user summed by a
+// time report
+type TimeReportFilter struct {
+	GroupBy   string    // GroupByProject, GroupByUser, GroupByTask or GroupByDate
+	From, To  time.Time // Entries started in [From, To)
+	ProjectID string
+}
+
+// TimeReportRow is the time spent on one group of a time report
+type TimeReportRow struct {
+	Key     string `json:"key"`            // Project, user or task ID, or date as YYYY-MM-DD
+	Name    string `json:"name,omitempty"` // Project or task name
+	Seconds int64  `json:"seconds"`
+	Entries int    `json:"entries"`
+}
+
+// createTimeEntryTables creates the TimeEntry table and the estimate of tasks.
+// Times are stored in UTC.
+func createTimeEntryTables(db *sql.DB) error {
+	_, err := db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_seconds BIGINT NOT NULL DEFAULT 0`)
+	if err != nil {
+		return fmt.Errorf("failed to add estimate_seconds to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS time_entries (
+			id VARCHAR(36) PRIMARY KEY,
+			task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id VARCHAR(36) NOT NULL DEFAULT '',
+			started_at TIMESTAMP NOT NULL,
+			ended_at TIMESTAMP,
+			note VARCHAR(255) NOT NULL DEFAULT '',
+			manual BOOLEAN NOT NULL DEFAULT FALSE,
+			CHECK (ended_at IS NULL OR ended_at >= started_at)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create TimeEntry table: %v", err)
+	}
+
+	// A user can only have one running timer
+	_, err = db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (user_id) WHERE ended_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to create TimeEntry running index: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS time_entries_task_id_idx ON time_entries (task_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create TimeEntry task index: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// createTrashTables adds the deletion marker of tasks. A deleted task stays
+// in the trash, hidden from every query, until it is restored or purged.
+func createTrashTables(db *sql.DB) error {
+	_, err := db.Exec(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`)
+	if err != nil {
+		return fmt.Errorf("failed to add deleted_at to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to create Task trash index: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// User is a person that can own tasks and be mentioned in comments
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"` // Handle used for @mentions
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	TimeZone  string    `json:"timeZone"` // IANA name such as Europe/Paris, UTC when empty
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// createUserTables creates the User table.
+func createUserTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id VARCHAR(36) PRIMARY KEY,
+			username VARCHAR(50) NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create User table: %v", err)
+	}
+
+	// Store the time zone dates are written in by each user
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add time_zone to User table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS users_username_idx ON users (LOWER(username))`)
+	if err != nil {
+		return fmt.Errorf("failed to create User username index: %v", err)
+	}
+
+	return nil
+}
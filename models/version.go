@@ -0,0 +1,113 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// taskRelationTables hold rows that are part of a task, so changing them
+// changes the version of the task.
+var taskRelationTables = []string{"reminders", "task_tags", "checklist_items", "task_dependencies", "attachments", "time_entries"}
+
+// createVersionTables adds the version of tasks, used as their ETag. Triggers
+// increment it whenever the task, one of its subtasks or a row of
+// taskRelationTables changes, so a client holding the current version has
+// the current task.
+func createVersionTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		ALTER TABLE tasks
+			ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1,
+			ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add version to Task table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION tasks_bump_version() RETURNS TRIGGER AS $$
+		BEGIN
+			NEW.version := OLD.version + 1;
+			NEW.updated_at := NOW();
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Task version function: %v", err)
+	}
+
+	err = createTrigger(db, "tasks_bump_version", "BEFORE UPDATE ON tasks FOR EACH ROW EXECUTE PROCEDURE tasks_bump_version()")
+	if err != nil {
+		return err
+	}
+
+	// Touching the parent bumps its version in turn, and so on up to the root
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION tasks_touch_parent() RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				UPDATE tasks SET updated_at = NOW() WHERE id = NEW.parent_id;
+			ELSIF TG_OP = 'DELETE' THEN
+				UPDATE tasks SET updated_at = NOW() WHERE id = OLD.parent_id;
+			ELSE
+				UPDATE tasks SET updated_at = NOW() WHERE id IN (OLD.parent_id, NEW.parent_id);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create Task parent function: %v", err)
+	}
+
+	err = createTrigger(db, "tasks_touch_parent", "AFTER INSERT OR UPDATE OR DELETE ON tasks FOR EACH ROW EXECUTE PROCEDURE tasks_touch_parent()")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE OR REPLACE FUNCTION touch_task() RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				UPDATE tasks SET updated_at = NOW() WHERE id = NEW.task_id;
+			ELSIF TG_OP = 'DELETE' THEN
+				UPDATE tasks SET updated_at = NOW() WHERE id = OLD.task_id;
+			ELSE
+				UPDATE tasks SET updated_at = NOW() WHERE id IN (OLD.task_id, NEW.task_id);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create touch_task function: %v", err)
+	}
+
+	for _, table := range taskRelationTables {
+		err = createTrigger(db, table+"_touch_task",
+			"AFTER INSERT OR UPDATE OR DELETE ON "+table+" FOR EACH ROW EXECUTE PROCEDURE touch_task()")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createTrigger creates a trigger unless one with the same name exists, as
+// Postgres has no CREATE TRIGGER IF NOT EXISTS.
+func createTrigger(db *sql.DB, name, definition string) error {
+	_, err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = '` + name + `') THEN
+				CREATE TRIGGER ` + name + ` ` + definition + `;
+			END IF;
+		END
+		$$
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s trigger: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var units = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "wks": 7 * 24 * time.Hour,
+	"week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+var numbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var (
+	amountPattern  = regexp.MustCompile(`^(\d+)([a-z]+)$`)
+	ordinalPattern = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	clockPattern   = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?(am|pm|a\.m|p\.m)?$`)
+)
+
+// parseDay parses the day starting at the i-th word, and returns it with
+// the number of words it spans, 0 if there is none.
+func (p *parser) parseDay(i int) (day, int) {
+	now := p.now
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := p.norm(i)
+
+	switch first {
+	case "today":
+		return day{date: today}, 1
+	case "tonight":
+		return day{date: today, evening: true}, 1
+	case "tomorrow", "tmrw", "tmr":
+		return day{date: today.AddDate(0, 0, 1)}, 1
+	case "next", "this":
+		if weekday, ok := weekdays[p.norm(i+1)]; ok {
+			return day{weekday: weekday, isWeekday: true, strict: first == "next"}, 2
+		}
+		if first == "next" && p.norm(i+1) == "week" {
+			days := (8 - int(now.Weekday())) % 7
+			if days == 0 {
+				days = 7
+			}
+			return day{date: today.AddDate(0, 0, days)}, 2
+		}
+		return day{}, 0
+	}
+	if weekday, ok := weekdays[first]; ok {
+		return day{weekday: weekday, isWeekday: true}, 1
+	}
+	if date, err := time.ParseInLocation("2006-01-02", first, now.Location()); err == nil {
+		return day{date: date}, 1
+	}
+
+	// "nov 2", "november 2nd 2027", "2 nov" or "2nd of november"
+	if month, ok := months[first]; ok {
+		if d, ok := ordinal(p.norm(i + 1)); ok {
+			return p.monthDay(month, d, i+2, 2)
+		}
+		return day{}, 0
+	}
+	if d, ok := ordinal(first); ok {
+		j := i + 1
+		if p.norm(j) == "of" {
+			j++
+		}
+		if month, ok := months[p.norm(j)]; ok {
+			return p.monthDay(month, d, j+1, j+1-i)
+		}
+	}
+	return day{}, 0
+}
+
+// monthDay returns the given day of a month, in the year of the i-th word
+// if it is one, or else in its next occurrence. n is the number of words
+// before the year.
+func (p *parser) monthDay(month time.Month, d, i, n int) (day, int) {
+	now := p.now
+	year, hasYear := now.Year(), false
+	if y, err := strconv.Atoi(p.norm(i)); err == nil && len(p.norm(i)) == 4 {
+		year, hasYear = y, true
+	}
+	date := time.Date(year, month, d, 0, 0, 0, 0, now.Location())
+	if date.Day() != d {
+		return day{}, 0
+	}
+	if hasYear {
+		return day{date: date}, n + 1
+	}
+	if date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return day{date: date}, n
+}
+
+// ordinal parses a day of the month such as "2" or "2nd".
+func ordinal(value string) (int, bool) {
+	m := ordinalPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, false
+	}
+	d, _ := strconv.Atoi(m[1])
+	return d, d >= 1 && d <= 31
+}
+
+// parseClock parses the time of day starting at the i-th word, such as
+// "14:30", "2:30pm" or "2 pm", and returns it with the number of words it
+// spans, 0 if there is none. A bare hour such as "9" is only a time with
+// bare set.
+func (p *parser) parseClock(i int, bare bool) (hour, minute, n int) {
+	value := p.norm(i)
+	switch value {
+	case "noon":
+		return 12, 0, 1
+	case "midnight":
+		return 0, 0, 1
+	}
+
+	m := clockPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, 0, 0
+	}
+	n = 1
+	meridiem := m[3]
+	if meridiem == "" {
+		switch next := p.norm(i + 1); next {
+		case "am", "pm", "a.m", "p.m":
+			meridiem, n = next, 2
+		}
+	}
+	if m[2] == "" && meridiem == "" && !bare {
+		return 0, 0, 0
+	}
+
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0
+		}
+		hour %= 12
+		if strings.HasPrefix(meridiem, "p") {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, 0
+	}
+	return hour, minute, n
+}
+
+// duration parses the duration starting at the i-th word, such as "15m",
+// "1h30m", "2 hours" or "an hour", and returns it with the number of words
+// it spans, 0 if there is none.
+func (p *parser) duration(i int) (time.Duration, int) {
+	value := p.norm(i)
+	if m := amountPattern.FindStringSubmatch(value); m != nil {
+		if unit, ok := units[m[2]]; ok {
+			amount, _ := strconv.Atoi(m[1])
+			return time.Duration(amount) * unit, 1
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, 1
+	}
+
+	amount, ok := numbers[value]
+	if !ok {
+		var err error
+		amount, err = strconv.Atoi(value)
+		if err != nil || amount <= 0 {
+			return 0, 0
+		}
+	}
+	unit, ok := units[p.norm(i+1)]
+	if !ok {
+		return 0, 0
+	}
+	return time.Duration(amount) * unit, 2
+}
+
+// formatOffset formats an offset like time.Duration.String, without the
+// trailing zero units, e.g. "1h" rather than "1h0m0s".
+func formatOffset(d time.Duration) string {
+	s := d.String()
+	if d%time.Minute == 0 {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if d%time.Hour == 0 {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
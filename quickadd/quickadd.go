@@ -0,0 +1,363 @@
+// Package quickadd parses the free text of a task, such as "Call John at
+// 2:30 pm on Monday !high #work", into its title and the fields it
+// mentions.
+package quickadd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/vikash-parashar/task-manager-2/models"
+)
+
+// DefaultHour is the hour a task is due on a day given without a time.
+const DefaultHour = 9
+
+// eveningHour is the hour a task is due "tonight" without a time.
+const eveningHour = 20
+
+// Result is the outcome of parsing a text
+type Result struct {
+	Title     string                 // Text left once the fields are taken out
+	Due       time.Time              // Zero when the text has no date or time
+	Priority  string                 // low, medium, high, urgent or empty
+	Tags      []string               // Names of the #tags
+	Mentions  []string               // Names of the @mentions, kept in the title without the @
+	Reminders []time.Duration        // Offsets of the reminders before the due date
+	Tokens    []models.QuickAddToken // Parts of the text understood, in order
+}
+
+var priorities = map[string]string{
+	"!low":       "low",
+	"!medium":    "medium",
+	"!med":       "medium",
+	"!high":      "high",
+	"!important": "high",
+	"!urgent":    "urgent",
+}
+
+// word is a word of the text
+type word struct {
+	text string // As written
+	norm string // Lower-cased, without trailing punctuation
+}
+
+// parser holds the state of a parse
+type parser struct {
+	now    time.Time
+	words  []word
+	title  []string
+	result Result
+
+	day      *day // Date or weekday, resolved once the time is known
+	dayToken int
+
+	hasClock     bool
+	hour, minute int
+
+	hasRelative   bool
+	relative      time.Duration
+	relativeToken int
+}
+
+// day is a date, or a weekday when isWeekday is set
+type day struct {
+	date      time.Time
+	weekday   time.Weekday
+	isWeekday bool
+	strict    bool // "next friday" is never today
+	evening   bool // "tonight"
+}
+
+// Parse parses a task written as free text, with dates relative to now and
+// in its time zone. Only the first date, time and priority are taken, later
+// ones are left in the title.
+//
+// Due dates are written as "today", "tonight", "tomorrow", a weekday such
+// as "monday" or "next fri", "next week", "2026-11-02", "nov 2" or "2nd
+// november", optionally after "on" or "by", and times as "at 9", "2:30 pm",
+// "14:30", "noon" or "midnight". "in 2 hours" or "in 3 days" is relative
+// to now. A day without a time is due at DefaultHour, a time without a day
+// is due today or, once passed, tomorrow, and a weekday that has passed
+// means the next one.
+//
+// "!high" sets the priority, "#work" adds a tag, "@john" mentions a
+// contact, and "remind me 15 minutes before" adds a reminder.
+func Parse(text string, now time.Time) Result {
+	p := &parser{now: now, words: split(text)}
+	for i := 0; i < len(p.words); {
+		n := p.match(i)
+		if n == 0 {
+			p.title = append(p.title, p.words[i].text)
+			n = 1
+		}
+		i += n
+	}
+	p.resolveDue()
+
+	p.result.Title = strings.Trim(strings.Join(p.title, " "), " ,;:-")
+	return p.result
+}
+
+// ParseDue parses a due date written on its own, such as "tomorrow 14:30",
+// "mon 2:30pm", "2026-11-02 9am" or "in 2h", with dates relative to now and
+// in its time zone. Dates and times are written as in Parse, and a bare
+// hour such as "9" is a time. Text that is not only a due date is invalid.
+func ParseDue(text string, now time.Time) (time.Time, error) {
+	p := &parser{now: now, words: split(text)}
+	for i := 0; i < len(p.words); {
+		n := p.matchDue(i)
+		if n == 0 {
+			return time.Time{}, fmt.Errorf("invalid due date %q", text)
+		}
+		i += n
+	}
+	p.resolveDue()
+	if p.result.Due.IsZero() {
+		return time.Time{}, fmt.Errorf("invalid due date %q", text)
+	}
+	return p.result.Due, nil
+}
+
+// split splits a text into words.
+func split(text string) []word {
+	var words []word
+	for _, field := range strings.Fields(text) {
+		norm := strings.ToLower(strings.TrimRightFunc(field, func(r rune) bool {
+			return strings.ContainsRune(",.;:!?)", r)
+		}))
+		words = append(words, word{text: field, norm: norm})
+	}
+	return words
+}
+
+// match matches the fields starting at the i-th word, and returns the number
+// of words they span, or 0 for a word of the title.
+func (p *parser) match(i int) int {
+	for _, matcher := range []func(int) int{
+		p.matchTag,
+		p.matchPriority,
+		p.matchMention,
+		p.matchReminder,
+		p.matchRelative,
+		p.matchDay,
+		p.matchClock,
+	} {
+		if n := matcher(i); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// matchDue matches the parts of a due date starting at the i-th word, and
+// returns the number of words they span, 0 if there is none.
+func (p *parser) matchDue(i int) int {
+	if n := p.matchRelative(i); n > 0 {
+		return n
+	}
+	if n := p.matchDay(i); n > 0 {
+		return n
+	}
+	if p.hasClock {
+		return 0
+	}
+	hour, minute, n := p.parseClock(i, true)
+	if n == 0 {
+		return p.matchClock(i)
+	}
+	p.hasClock, p.hour, p.minute = true, hour, minute
+	return n
+}
+
+// norm returns the normalized i-th word, or "" past the last one.
+func (p *parser) norm(i int) string {
+	if i >= len(p.words) {
+		return ""
+	}
+	return p.words[i].norm
+}
+
+// token records the n words from the i-th one as a token, and returns its
+// index.
+func (p *parser) token(i, n int, kind, value string) int {
+	texts := make([]string, n)
+	for j := range texts {
+		texts[j] = p.words[i+j].text
+	}
+	p.result.Tokens = append(p.result.Tokens, models.QuickAddToken{
+		Text:  strings.Join(texts, " "),
+		Kind:  kind,
+		Value: value,
+	})
+	return len(p.result.Tokens) - 1
+}
+
+// name returns the name following the marker of a #tag or @mention, or ""
+// if there is none.
+func name(text string) string {
+	return strings.TrimRightFunc(text[1:], func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (p *parser) matchTag(i int) int {
+	text := p.words[i].text
+	if !strings.HasPrefix(text, "#") {
+		return 0
+	}
+	tag := name(text)
+	// "#42" is more likely the number of an issue than a tag
+	if tag == "" || strings.Trim(tag, "0123456789") == "" {
+		return 0
+	}
+	p.result.Tags = append(p.result.Tags, tag)
+	p.token(i, 1, models.QuickAddTag, tag)
+	return 1
+}
+
+func (p *parser) matchPriority(i int) int {
+	priority, ok := priorities[p.norm(i)]
+	if !ok || p.result.Priority != "" {
+		return 0
+	}
+	p.result.Priority = priority
+	p.token(i, 1, models.QuickAddPriority, priority)
+	return 1
+}
+
+func (p *parser) matchMention(i int) int {
+	text := p.words[i].text
+	if !strings.HasPrefix(text, "@") {
+		return 0
+	}
+	mention := name(text)
+	if mention == "" {
+		return 0
+	}
+	p.result.Mentions = append(p.result.Mentions, mention)
+	p.token(i, 1, models.QuickAddContact, mention)
+	p.title = append(p.title, text[1:])
+	return 1
+}
+
+// matchReminder matches "remind me 15 minutes before", "remind 1h before"
+// or "reminder 1d".
+func (p *parser) matchReminder(i int) int {
+	if p.norm(i) != "remind" && p.norm(i) != "reminder" {
+		return 0
+	}
+	j := i + 1
+	if p.norm(j) == "me" {
+		j++
+	}
+	offset, n := p.duration(j)
+	if n == 0 {
+		return 0
+	}
+	j += n
+	if p.norm(j) == "before" {
+		j++
+	}
+	p.result.Reminders = append(p.result.Reminders, offset)
+	p.token(i, j-i, models.QuickAddReminder, formatOffset(offset))
+	return j - i
+}
+
+// matchRelative matches "in 2 hours" or "in 3d".
+func (p *parser) matchRelative(i int) int {
+	if p.norm(i) != "in" || p.hasRelative || p.day != nil {
+		return 0
+	}
+	d, n := p.duration(i + 1)
+	if n == 0 {
+		return 0
+	}
+	p.hasRelative, p.relative = true, d
+	p.relativeToken = p.token(i, n+1, models.QuickAddRelative, "")
+	return n + 1
+}
+
+// matchDay matches a day, optionally after "on", "by" or "due".
+func (p *parser) matchDay(i int) int {
+	if p.day != nil || p.hasRelative {
+		return 0
+	}
+	j := i
+	for j < len(p.words) && j-i < 2 && (p.norm(j) == "on" || p.norm(j) == "by" || p.norm(j) == "due") {
+		j++
+	}
+	d, n := p.parseDay(j)
+	if n == 0 {
+		return 0
+	}
+	p.day = &d
+	p.dayToken = p.token(i, j-i+n, models.QuickAddDate, "")
+	return j - i + n
+}
+
+// matchClock matches a time of day, optionally after "at", which also
+// allows a bare hour such as "at 9".
+func (p *parser) matchClock(i int) int {
+	if p.hasClock {
+		return 0
+	}
+	j := i
+	if p.norm(j) == "at" {
+		j++
+	}
+	hour, minute, n := p.parseClock(j, j > i)
+	if n == 0 {
+		return 0
+	}
+	p.hasClock, p.hour, p.minute = true, hour, minute
+	p.token(i, j-i+n, models.QuickAddTime, time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC).Format("15:04"))
+	return j - i + n
+}
+
+// resolveDue combines the day, time and relative offset into the due date.
+func (p *parser) resolveDue() {
+	now := p.now
+	at := func(t time.Time, hour, minute int) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, now.Location())
+	}
+	hour, minute := DefaultHour, 0
+	if p.hasClock {
+		hour, minute = p.hour, p.minute
+	}
+
+	var due time.Time
+	switch {
+	case p.hasRelative:
+		due = now.Add(p.relative)
+		if p.hasClock {
+			due = at(due, hour, minute)
+		}
+		p.result.Tokens[p.relativeToken].Value = due.Format(time.RFC3339)
+	case p.day != nil:
+		if p.day.evening && !p.hasClock {
+			hour = eveningHour
+		}
+		if p.day.isWeekday {
+			days := (int(p.day.weekday) - int(now.Weekday()) + 7) % 7
+			if days == 0 && p.day.strict {
+				days = 7
+			}
+			due = at(now.AddDate(0, 0, days), hour, minute)
+			if days == 0 && !due.After(now) {
+				due = due.AddDate(0, 0, 7)
+			}
+		} else {
+			due = at(p.day.date, hour, minute)
+		}
+		p.result.Tokens[p.dayToken].Value = due.Format("2006-01-02")
+	case p.hasClock:
+		due = at(now, hour, minute)
+		if !due.After(now) {
+			due = due.AddDate(0, 0, 1)
+		}
+	}
+	p.result.Due = due
+}
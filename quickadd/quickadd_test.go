@@ -0,0 +1,120 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata" // Time zones do not depend on the system
+)
+
+// testNow returns Wednesday 14 October 2026 at 15:00 in Paris, the time the
+// tests parse dates at.
+func testNow(t *testing.T) (time.Time, *time.Location) {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2026, time.October, 14, 15, 0, 0, 0, loc), loc
+}
+
+func TestParse(t *testing.T) {
+	now, loc := testNow(t)
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+
+	for _, tt := range []struct {
+		text string
+		want Result // Tokens are not compared
+	}{
+		{"Call John next Friday", Result{Title: "Call John", Due: date(time.October, 16, DefaultHour, 0)}},
+		{"Call John next Wednesday", Result{Title: "Call John", Due: date(time.October, 21, DefaultHour, 0)}},
+		{"Call John friday at 11", Result{Title: "Call John", Due: date(time.October, 16, 11, 0)}},
+		{"Deploy in 2 hours", Result{Title: "Deploy", Due: date(time.October, 14, 17, 0)}},
+		{"Deploy in 3d at 8am", Result{Title: "Deploy", Due: date(time.October, 17, 8, 0)}},
+		{"Pay rent at 2:30pm", Result{Title: "Pay rent", Due: date(time.October, 15, 14, 30)}}, // Passed today
+		{"Pay rent 4 pm", Result{Title: "Pay rent", Due: date(time.October, 14, 16, 0)}},
+		{"Stand-up wednesday 9am", Result{Title: "Stand-up", Due: date(time.October, 21, 9, 0)}}, // Passed today
+		{"Stand-up wednesday 6pm", Result{Title: "Stand-up", Due: date(time.October, 14, 18, 0)}},
+		{"Read tonight", Result{Title: "Read", Due: date(time.October, 14, eveningHour, 0)}},
+		{"Report due tomorrow noon", Result{Title: "Report", Due: date(time.October, 15, 12, 0)}},
+		{"Plan next week", Result{Title: "Plan", Due: date(time.October, 19, DefaultHour, 0)}},
+		{"Dentist on 2nd of november !high #health", Result{
+			Title:    "Dentist",
+			Due:      date(time.November, 2, DefaultHour, 0), // After the change to winter time
+			Priority: "high",
+			Tags:     []string{"health"},
+		}},
+		{"Renew passport sept 1", Result{Title: "Renew passport", Due: time.Date(2027, time.September, 1, DefaultHour, 0, 0, 0, loc)}},
+		{"Call @john tomorrow, remind me 15 minutes before", Result{
+			Title:     "Call john",
+			Due:       date(time.October, 15, DefaultHour, 0),
+			Mentions:  []string{"john"},
+			Reminders: []time.Duration{15 * time.Minute},
+		}},
+		{"Fix #42 !low !urgent", Result{Title: "Fix #42 !urgent", Priority: "low"}},
+		{"Buy milk", Result{Title: "Buy milk"}},
+		{"Meet feb 30 at 25:00", Result{Title: "Meet feb 30 at 25:00"}}, // Invalid date and time
+		{"Wake up 13pm", Result{Title: "Wake up 13pm"}},
+		{"", Result{}},
+	} {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Parse(tt.text, now)
+			got.Tokens = nil
+			if !got.Due.Equal(tt.want.Due) {
+				t.Errorf("got due date %v, want %v", got.Due, tt.want.Due)
+			}
+			got.Due, tt.want.Due = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDue(t *testing.T) {
+	now, loc := testNow(t)
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+
+	for _, tt := range []struct {
+		text string
+		want time.Time // Zero when the text is invalid
+	}{
+		{"next Friday", date(time.October, 16, DefaultHour, 0)},
+		{"in 2 hours", date(time.October, 14, 17, 0)},
+		{"in 2h", date(time.October, 14, 17, 0)},
+		{"2:30pm", date(time.October, 15, 14, 30)}, // Passed today
+		{"17:45", date(time.October, 14, 17, 45)},
+		{"9", date(time.October, 15, 9, 0)}, // Bare hour, passed today
+		{"tomorrow 14:30", date(time.October, 15, 14, 30)},
+		{"mon 2:30pm", date(time.October, 19, 14, 30)},
+		{"2026-11-02 9am", date(time.November, 2, 9, 0)},
+		{"", time.Time{}},
+		{"soon", time.Time{}},
+		{"tomorrow please", time.Time{}},
+		{"2:30pm 4pm", time.Time{}},
+		{"25:00", time.Time{}},
+		{"13pm", time.Time{}},
+		{"feb 30", time.Time{}},
+		{"2026-13-01", time.Time{}},
+	} {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseDue(tt.text, now)
+			if tt.want.IsZero() {
+				if err == nil {
+					t.Errorf("got %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
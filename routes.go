@@ -0,0 +1,151 @@
+package main
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/handlers"
+)
+
+// legacyRoutes registers the unversioned routes, which predate /v1 and are
+// deprecated. New routes only go into the versioned API.
+func legacyRoutes(r chi.Router) {
+	// @Summary Create a new task
+	// @Description Creates a new task with the specified details
+	// @ID create-task
+	// @Produce json
+	// @Param task body Task true "Task details"
+	// @Success 201 {object} Task "Successfully created task"
+	// @Failure 400 {object} ErrorResponse "Bad request"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/create [post]
+	r.Post("/tasks/create", handlers.CreateTaskHandler)
+
+	// @Summary Get a task by ID
+	// @Description Retrieves a task by its unique identifier
+	// @ID get-task
+	// @Produce json
+	// @Param id path string true "Task ID"
+	// @Success 200 {object} Task "Successfully retrieved task"
+	// @Failure 404 {object} ErrorResponse "Task not found"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/get/{id} [get]
+	r.Get("/tasks/get/{id}", handlers.GetTaskHandler)
+
+	// @Summary Update a task by ID
+	// @Description Updates a task with the specified details
+	// @ID update-task
+	// @Produce json
+	// @Param id path string true "Task ID"
+	// @Param task body Task true "Task details"
+	// @Success 200 {object} Task "Successfully updated task"
+	// @Failure 400 {object} ErrorResponse "Bad request"
+	// @Failure 404 {object} ErrorResponse "Task not found"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/update/{id} [put]
+	r.Put("/tasks/update/{id}", handlers.UpdateTaskHandler)
+	// Partial updates, see handlers/patch.go
+	r.Patch("/tasks/update/{id}", handlers.PatchTaskHandler)
+
+	// @Summary Delete a task by ID
+	// @Description Deletes a task by its unique identifier
+	// @ID delete-task
+	// @Produce json
+	// @Param id path string true "Task ID"
+	// @Success 200 {object} SuccessResponse "Successfully deleted task"
+	// @Failure 404 {object} ErrorResponse "Task not found"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/delete/{id} [delete]
+	r.Delete("/tasks/delete/{id}", handlers.DeleteTaskHandler)
+
+	// @Summary Get all tasks
+	// @Description Retrieves a list of all tasks
+	// @ID get-all-tasks
+	// @Produce json
+	// @Success 200 {array} Task "Successfully retrieved tasks"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/getAll [get]
+	r.Get("/tasks/getAll", handlers.GetAllTasksHandler)
+
+	// @Summary Get tasks with due reminders
+	// @Description Retrieves a list of tasks with due reminders
+	// @ID get-tasks-with-due-reminders
+	// @Produce json
+	// @Success 200 {array} Task "Successfully retrieved tasks with due reminders"
+	// @Failure 500 {object} ErrorResponse "Internal server error"
+	// @Router /tasks/dueReminders [get]
+	r.Get("/tasks/dueReminders", handlers.GetTasksWithDueReminder)
+
+	// Reminders of a task, see handlers/reminders.go
+	r.Get("/tasks/{id}/reminders", handlers.GetRemindersHandler)
+	r.Post("/tasks/{id}/reminders", handlers.CreateReminderHandler)
+	r.Put("/tasks/{id}/reminders/{reminderID}", handlers.UpdateReminderHandler)
+	r.Delete("/tasks/{id}/reminders/{reminderID}", handlers.DeleteReminderHandler)
+
+	// Tags and their association with tasks, see handlers/tags.go
+	r.Post("/tags/create", handlers.CreateTagHandler)
+	r.Get("/tags/get/{id}", handlers.GetTagHandler)
+	r.Get("/tags/getAll", handlers.GetAllTagsHandler)
+	r.Put("/tags/update/{id}", handlers.UpdateTagHandler)
+	r.Delete("/tags/delete/{id}", handlers.DeleteTagHandler)
+	r.Post("/tags/merge/{id}", handlers.MergeTagsHandler)
+	r.Post("/tasks/attachTags/{id}", handlers.AttachTagsHandler)
+	r.Post("/tasks/detachTags/{id}", handlers.DetachTagsHandler)
+
+	// Projects grouping tasks, see handlers/projects.go
+	r.Post("/projects/create", handlers.CreateProjectHandler)
+	r.Get("/projects/get/{id}", handlers.GetProjectHandler)
+	r.Get("/projects/getAll", handlers.GetAllProjectsHandler)
+	r.Put("/projects/update/{id}", handlers.UpdateProjectHandler)
+	r.Post("/projects/archive/{id}", handlers.ArchiveProjectHandler)
+	r.Post("/projects/unarchive/{id}", handlers.UnarchiveProjectHandler)
+	r.Post("/projects/reorder", handlers.ReorderProjectsHandler)
+	r.Delete("/projects/delete/{id}", handlers.DeleteProjectHandler)
+
+	// Task completion and checklists, see handlers/subtasks.go
+	r.Post("/tasks/complete/{id}", handlers.CompleteTaskHandler)
+	r.Post("/tasks/reopen/{id}", handlers.ReopenTaskHandler)
+	r.Post("/checklist/create", handlers.CreateChecklistItemHandler)
+	r.Put("/checklist/update/{id}", handlers.UpdateChecklistItemHandler)
+	r.Post("/checklist/toggle/{id}", handlers.ToggleChecklistItemHandler)
+	r.Post("/checklist/reorder/{id}", handlers.ReorderChecklistHandler)
+	r.Delete("/checklist/delete/{id}", handlers.DeleteChecklistItemHandler)
+
+	// Task dependencies, see handlers/dependencies.go
+	r.Post("/tasks/addDependency/{id}", handlers.AddDependencyHandler)
+	r.Post("/tasks/removeDependency/{id}", handlers.RemoveDependencyHandler)
+	r.Get("/tasks/next", handlers.GetNextTasksHandler)
+
+	// Users, see handlers/users.go
+	r.Post("/users/create", handlers.CreateUserHandler)
+	r.Get("/users/get/{id}", handlers.GetUserHandler)
+	r.Get("/users/getAll", handlers.GetAllUsersHandler)
+
+	// Comments and the activity feed, see handlers/comments.go
+	r.Post("/comments/create", handlers.CreateCommentHandler)
+	r.Get("/comments/getAll/{id}", handlers.GetCommentsHandler)
+	r.Put("/comments/update/{id}", handlers.UpdateCommentHandler)
+	r.Delete("/comments/delete/{id}", handlers.DeleteCommentHandler)
+	r.Get("/comments/revisions/{id}", handlers.GetCommentRevisionsHandler)
+	r.Get("/tasks/activity/{id}", handlers.GetTaskActivityHandler)
+
+	// File attachments, see handlers/attachments.go
+	r.Post("/attachments/upload/{id}", handlers.UploadAttachmentHandler)
+	r.Get("/attachments/download/{id}", handlers.DownloadAttachmentHandler)
+	r.Delete("/attachments/delete/{id}", handlers.DeleteAttachmentHandler)
+
+	// Time tracking, see handlers/timeentries.go
+	r.Post("/timers/start/{id}", handlers.StartTimerHandler)
+	r.Post("/timers/stop", handlers.StopTimerHandler)
+	r.Get("/timers/current", handlers.GetRunningTimerHandler)
+	r.Post("/timeEntries/create", handlers.CreateTimeEntryHandler)
+	r.Get("/timeEntries/getAll/{id}", handlers.GetTimeEntriesHandler)
+	r.Delete("/timeEntries/delete/{id}", handlers.DeleteTimeEntryHandler)
+	r.Get("/reports/time", handlers.GetTimeReportHandler)
+
+	// Trash of deleted tasks, see handlers/trash.go
+	r.Get("/tasks/trash", handlers.GetTrashHandler)
+	r.Post("/tasks/restore/{id}", handlers.RestoreTaskHandler)
+	r.Delete("/tasks/purge/{id}", handlers.PurgeTaskHandler)
+
+	// Audit log of task and reminder changes, see handlers/audit.go
+	r.Get("/audit", handlers.GetAuditLogHandler)
+}
@@ -0,0 +1,151 @@
+// Package routes registers the routes of the versioned API, for the server
+// and for the tests of its clients.
+package routes
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/vikash-parashar/task-manager-2/handlers"
+)
+
+// V1 registers version 1 of the API, mounted at /v1. Resources are
+// nouns, and the HTTP method says what is done to them. A later version gets
+// its own function, mounted next to this one.
+func V1(r chi.Router) {
+	// Tasks, see handlers/handlers.go
+	r.Route("/tasks", func(r chi.Router) {
+		r.Get("/", handlers.GetAllTasksHandler)
+		r.Post("/", handlers.CreateTaskHandler)
+		r.Get("/next", handlers.GetNextTasksHandler)
+		r.Get("/dueReminders", handlers.GetTasksWithDueReminder)
+		// Batches of operations, see handlers/batch.go
+		r.Post("/batch", handlers.BatchTasksHandler)
+		// Tasks written as free text, see handlers/quickadd.go
+		r.Post("/quickAdd", handlers.QuickAddHandler)
+
+		// Trash of deleted tasks, see handlers/trash.go
+		r.Get("/trash", handlers.GetTrashHandler)
+		r.Post("/trash/{id}/restore", handlers.RestoreTaskHandler)
+		r.Delete("/trash/{id}", handlers.PurgeTaskHandler)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", handlers.GetTaskHandler)
+			r.Put("/", handlers.UpdateTaskHandler)
+			// Partial updates, see handlers/patch.go
+			r.Patch("/", handlers.PatchTaskHandler)
+			r.Delete("/", handlers.DeleteTaskHandler)
+
+			// Completion and checklist, see handlers/subtasks.go
+			r.Post("/complete", handlers.CompleteTaskHandler)
+			r.Post("/reopen", handlers.ReopenTaskHandler)
+			r.Put("/checklist/order", handlers.ReorderChecklistHandler)
+
+			// Reminders, see handlers/reminders.go
+			r.Get("/reminders", handlers.GetRemindersHandler)
+			r.Post("/reminders", handlers.CreateReminderHandler)
+			r.Put("/reminders/{reminderID}", handlers.UpdateReminderHandler)
+			r.Delete("/reminders/{reminderID}", handlers.DeleteReminderHandler)
+
+			// Tags, see handlers/tags.go
+			r.Post("/tags", handlers.AttachTagsHandler)
+			r.Delete("/tags", handlers.DetachTagsHandler)
+
+			// Dependencies, see handlers/dependencies.go
+			r.Post("/dependencies", handlers.AddDependencyHandler)
+			r.Delete("/dependencies", handlers.RemoveDependencyHandler)
+
+			// Comments and the activity feed, see handlers/comments.go
+			r.Get("/comments", handlers.GetCommentsHandler)
+			r.Get("/activity", handlers.GetTaskActivityHandler)
+
+			// Attachments, see handlers/attachments.go
+			r.Post("/attachments", handlers.UploadAttachmentHandler)
+
+			// Time tracking, see handlers/timeentries.go
+			r.Post("/timer", handlers.StartTimerHandler)
+			r.Get("/timeEntries", handlers.GetTimeEntriesHandler)
+		})
+	})
+
+	// Tags, see handlers/tags.go
+	r.Route("/tags", func(r chi.Router) {
+		r.Get("/", handlers.GetAllTagsHandler)
+		r.Post("/", handlers.CreateTagHandler)
+		r.Get("/{id}", handlers.GetTagHandler)
+		r.Put("/{id}", handlers.UpdateTagHandler)
+		r.Delete("/{id}", handlers.DeleteTagHandler)
+		r.Post("/{id}/merge", handlers.MergeTagsHandler)
+	})
+
+	// Projects, see handlers/projects.go
+	r.Route("/projects", func(r chi.Router) {
+		r.Get("/", handlers.GetAllProjectsHandler)
+		r.Post("/", handlers.CreateProjectHandler)
+		r.Put("/order", handlers.ReorderProjectsHandler)
+		r.Get("/{id}", handlers.GetProjectHandler)
+		r.Put("/{id}", handlers.UpdateProjectHandler)
+		r.Delete("/{id}", handlers.DeleteProjectHandler)
+		r.Post("/{id}/archive", handlers.ArchiveProjectHandler)
+		r.Post("/{id}/unarchive", handlers.UnarchiveProjectHandler)
+	})
+
+	// Contacts, see handlers/contacts.go
+	r.Route("/contacts", func(r chi.Router) {
+		r.Get("/", handlers.GetAllContactsHandler)
+		r.Post("/", handlers.CreateContactHandler)
+		r.Get("/{id}", handlers.GetContactHandler)
+		r.Put("/{id}", handlers.UpdateContactHandler)
+		r.Delete("/{id}", handlers.DeleteContactHandler)
+	})
+
+	// Checklist items, see handlers/subtasks.go
+	r.Route("/checklistItems", func(r chi.Router) {
+		r.Post("/", handlers.CreateChecklistItemHandler)
+		r.Put("/{id}", handlers.UpdateChecklistItemHandler)
+		r.Delete("/{id}", handlers.DeleteChecklistItemHandler)
+		r.Post("/{id}/toggle", handlers.ToggleChecklistItemHandler)
+	})
+
+	// Users, see handlers/users.go
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/", handlers.GetAllUsersHandler)
+		r.Post("/", handlers.CreateUserHandler)
+		r.Get("/{id}", handlers.GetUserHandler)
+		r.Put("/{id}", handlers.UpdateUserHandler)
+	})
+
+	// Comments, see handlers/comments.go
+	r.Route("/comments", func(r chi.Router) {
+		r.Post("/", handlers.CreateCommentHandler)
+		r.Put("/{id}", handlers.UpdateCommentHandler)
+		r.Delete("/{id}", handlers.DeleteCommentHandler)
+		r.Get("/{id}/revisions", handlers.GetCommentRevisionsHandler)
+	})
+
+	// Attachments, see handlers/attachments.go
+	r.Get("/attachments/{id}", handlers.DownloadAttachmentHandler)
+	r.Delete("/attachments/{id}", handlers.DeleteAttachmentHandler)
+
+	// Time tracking, see handlers/timeentries.go
+	r.Get("/timers/current", handlers.GetRunningTimerHandler)
+	r.Delete("/timers/current", handlers.StopTimerHandler)
+	r.Post("/timeEntries", handlers.CreateTimeEntryHandler)
+	r.Delete("/timeEntries/{id}", handlers.DeleteTimeEntryHandler)
+	r.Get("/reports/time", handlers.GetTimeReportHandler)
+
+	// Audit log of task and reminder changes, see handlers/audit.go
+	r.Get("/audit", handlers.GetAuditLogHandler)
+
+	// iCalendar feed of the tasks of a user, see handlers/calendar.go
+	r.Route("/calendar", func(r chi.Router) {
+		r.Post("/feed", handlers.CreateCalendarFeedHandler)
+		r.Delete("/feed", handlers.DeleteCalendarFeedHandler)
+		r.Get("/{token}.ics", handlers.GetCalendarFeedHandler)
+	})
+
+	// Stream of task events, see handlers/events.go
+	r.Get("/events", handlers.StreamEventsHandler)
+	// Task boards, see handlers/websocket.go
+	r.Get("/ws", handlers.BoardSocketHandler)
+	// GraphQL queries over tasks, reminders and contacts, see graph/
+	r.Post("/graphql", handlers.GraphQLHandler)
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"time"
+
+	taskv1 "github.com/vikash-parashar/task-manager-2/api/task/v1"
+	"github.com/vikash-parashar/task-manager-2/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timestamp returns nil for zero and missing times.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// taskFromProto returns the task to create or update, without the read
+// only fields.
+func taskFromProto(t *taskv1.Task) models.Task {
+	task := models.Task{
+		ID:              t.GetId(),
+		Title:           t.GetTitle(),
+		Description:     t.GetDescription(),
+		Priority:        t.GetPriority(),
+		ProjectID:       t.GetProjectId(),
+		ContactID:       t.GetContactId(),
+		ParentID:        t.GetParentId(),
+		EstimateSeconds: t.GetEstimateSeconds(),
+		NotifyMethod:    t.GetNotifyMethod(),
+		NotifyStatus:    t.GetNotifyStatus(),
+		NotifyMessage:   t.GetNotifyMessage(),
+	}
+	if t.GetDueDateTime() != nil {
+		task.DueDateTime = t.GetDueDateTime().AsTime()
+	}
+	for _, reminder := range t.GetReminders() {
+		task.Reminders = append(task.Reminders, models.Reminder{ID: reminder.GetId(), Date: reminder.GetDate()})
+	}
+	return task
+}
+
+func taskToProto(task models.Task) *taskv1.Task {
+	t := &taskv1.Task{
+		Id:               task.ID,
+		Title:            task.Title,
+		Description:      task.Description,
+		Priority:         task.Priority,
+		DueDateTime:      timestamp(&task.DueDateTime),
+		UserId:           task.UserID,
+		ProjectId:        task.ProjectID,
+		ContactId:        task.ContactID,
+		ParentId:         task.ParentID,
+		Status:           task.Status,
+		CompletedAt:      timestamp(task.CompletedAt),
+		Subtasks:         tasksToProto(task.Subtasks),
+		Progress:         task.Progress,
+		BlockedBy:        task.BlockedBy,
+		Version:          task.Version,
+		UpdatedAt:        timestamp(&task.UpdatedAt),
+		EstimateSeconds:  task.EstimateSeconds,
+		TimeSpentSeconds: task.TimeSpentSeconds,
+		NotifyMethod:     task.NotifyMethod,
+		NotifyStatus:     task.NotifyStatus,
+		NotifyMessage:    task.NotifyMessage,
+	}
+	for _, reminder := range task.Reminders {
+		t.Reminders = append(t.Reminders, &taskv1.Reminder{Id: reminder.ID, Date: reminder.Date, TaskId: reminder.TaskID})
+	}
+	for _, tag := range task.Tags {
+		t.Tags = append(t.Tags, &taskv1.Tag{Id: tag.ID, Name: tag.Name, Color: tag.Color})
+	}
+	return t
+}
+
+func tasksToProto(tasks []models.Task) []*taskv1.Task {
+	var t []*taskv1.Task
+	for _, task := range tasks {
+		t = append(t, taskToProto(task))
+	}
+	return t
+}
+
+func eventToProto(event models.Event) *taskv1.TaskEvent {
+	return &taskv1.TaskEvent{
+		Id:                event.ID,
+		Type:              event.Type,
+		TaskId:            event.TaskID,
+		ReminderId:        event.ReminderID,
+		UserId:            event.UserID,
+		ProjectId:         event.ProjectID,
+		Version:           event.Version,
+		OccurredAt:        timestamppb.New(event.OccurredAt),
+		PreviousProjectId: event.PreviousProjectID,
+	}
+}
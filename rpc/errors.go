@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"errors"
+	"log"
+
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the domain of the error codes in ErrorInfo details
+const errorDomain = "task-manager"
+
+// kindCode maps the kinds of controller errors to status codes, as
+// kindStatus maps them to HTTP statuses.
+var kindCode = map[controllers.ErrorKind]codes.Code{
+	controllers.KindNotFound:     codes.NotFound,
+	controllers.KindConflict:     codes.Aborted,
+	controllers.KindValidation:   codes.InvalidArgument,
+	controllers.KindUnauthorized: codes.Unauthenticated,
+	controllers.KindForbidden:    codes.PermissionDenied,
+	controllers.KindPrecondition: codes.FailedPrecondition,
+	controllers.KindTooLarge:     codes.InvalidArgument,
+	controllers.KindUnsupported:  codes.InvalidArgument,
+}
+
+// statusError returns the status reporting an error returned by the
+// controllers. Its ErrorInfo detail holds the stable code of the error,
+// e.g. task_not_found, and a BadRequest detail lists the invalid fields.
+// Unexpected errors are logged and reported without their details.
+func statusError(err error) error {
+	domainErr := controllers.Classify(err)
+	if domainErr == nil {
+		log.Println("gRPC error:", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	code, ok := kindCode[domainErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+	st := status.New(code, err.Error())
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: errorDomain}}
+	var validationErr *controllers.ValidationError
+	if errors.As(err, &validationErr) {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range validationErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
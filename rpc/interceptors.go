@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys carrying the IDs of the calling user and of their
+// workspace, the counterparts of auth.UserHeader and auth.WorkspaceHeader
+var (
+	userMetadata      = strings.ToLower(auth.UserHeader)
+	workspaceMetadata = strings.ToLower(auth.WorkspaceHeader)
+)
+
+// withUser stores the calling user's ID and workspace in the context, like
+// auth.Middleware. The workspace is only set for a user.
+func withUser(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	userID := first(md.Get(userMetadata))
+	if userID != "" {
+		ctx = auth.WithUserID(ctx, userID)
+	}
+	if workspaceID := first(md.Get(workspaceMetadata)); userID != "" && workspaceID != "" {
+		ctx = auth.WithWorkspaceID(ctx, workspaceID)
+	}
+	return ctx
+}
+
+// first returns the first of the values of a metadata key, or "".
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func authUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withUser(ctx), req)
+}
+
+func authStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: withUser(ss.Context())})
+}
+
+// serverStream replaces the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// recoverUnary reports panics as internal errors instead of crashing the
+// server, like middleware.Recoverer.
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("panic in %s: %v\n%s", info.FullMethod, p, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("panic in %s: %v\n%s", info.FullMethod, p, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(srv, ss)
+}
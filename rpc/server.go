@@ -0,0 +1,212 @@
+// Package rpc serves the gRPC TaskService, for backend services. Calls run
+// through the same controllers as the HTTP routes, and identify the user
+// the same way, see interceptors.go.
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	taskv1 "github.com/vikash-parashar/task-manager-2/api/task/v1"
+	"github.com/vikash-parashar/task-manager-2/auth"
+	"github.com/vikash-parashar/task-manager-2/config"
+	"github.com/vikash-parashar/task-manager-2/controllers"
+	"github.com/vikash-parashar/task-manager-2/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// eventStreamReset tells a resuming client that events may have been
+// missed, and that it should reload its tasks
+const eventStreamReset = "stream.reset"
+
+// Server implements TaskService
+type Server struct {
+	taskv1.UnimplementedTaskServiceServer
+}
+
+// NewServer returns a gRPC server serving TaskService.
+func NewServer() *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoverUnary, authUnary),
+		grpc.ChainStreamInterceptor(recoverStream, authStream),
+	)
+	taskv1.RegisterTaskServiceServer(s, &Server{})
+	return s
+}
+
+// ListenAndServe serves TaskService on addr.
+func ListenAndServe(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return NewServer().Serve(lis)
+}
+
+// taskFilter returns the filter of a task listing, like parseTaskFilter.
+func taskFilter(filter *taskv1.TaskFilter) (models.TaskFilter, error) {
+	f := models.TaskFilter{
+		TagIDs:    filter.GetTagIds(),
+		TagMode:   strings.ToLower(filter.GetTagMode()),
+		ProjectID: filter.GetProjectId(),
+		ContactID: filter.GetContactId(),
+	}
+
+	switch f.TagMode {
+	case "":
+		f.TagMode = models.TagModeAnd
+	case models.TagModeAnd, models.TagModeOr:
+	default:
+		return models.TaskFilter{}, status.Errorf(codes.InvalidArgument, "tag_mode must be %q or %q", models.TagModeAnd, models.TagModeOr)
+	}
+	return f, nil
+}
+
+func (s *Server) CreateTask(ctx context.Context, req *taskv1.CreateTaskRequest) (*taskv1.Task, error) {
+	newTask := taskFromProto(req.GetTask())
+	newTask.UserID = auth.UserID(ctx)
+
+	task, err := controllers.CreateTask(ctx, newTask)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *Server) GetTask(ctx context.Context, req *taskv1.GetTaskRequest) (*taskv1.Task, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	depth := controllers.DefaultSubtaskDepth
+	if req.Depth != nil {
+		depth = int(req.GetDepth())
+		if depth < 0 || depth > controllers.MaxSubtaskDepth {
+			return nil, status.Errorf(codes.InvalidArgument, "depth must be between 0 and %d", controllers.MaxSubtaskDepth)
+		}
+	}
+
+	task, err := controllers.GetTask(req.GetId(), depth)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *Server) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskRequest) (*taskv1.Task, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	updatedTask := taskFromProto(req.GetTask())
+	updatedTask.Version = req.GetVersion()
+
+	err := controllers.UpdateTask(ctx, req.GetId(), updatedTask)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	task, err := controllers.GetTask(req.GetId(), controllers.DefaultSubtaskDepth)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *Server) DeleteTask(ctx context.Context, req *taskv1.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	err := controllers.DeleteTask(ctx, req.GetId(), req.GetVersion())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListTasks(ctx context.Context, req *taskv1.ListTasksRequest) (*taskv1.ListTasksResponse, error) {
+	filter, err := taskFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := controllers.GetAllTasks(filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &taskv1.ListTasksResponse{Tasks: tasksToProto(tasks)}, nil
+}
+
+func (s *Server) ListDueReminders(ctx context.Context, req *taskv1.ListDueRemindersRequest) (*taskv1.ListDueRemindersResponse, error) {
+	filter, err := taskFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := controllers.GetTasksWithDueReminders(time.Now(), filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &taskv1.ListDueRemindersResponse{Tasks: tasksToProto(tasks)}, nil
+}
+
+// WatchTasks streams the events of the tasks of the user, like
+// StreamEventsHandler, narrowed to the tasks and projects requested.
+func (s *Server) WatchTasks(req *taskv1.WatchTasksRequest, stream taskv1.TaskService_WatchTasksServer) error {
+	userID := auth.UserID(stream.Context())
+	if userID == "" {
+		return statusError(controllers.ErrUnauthorized)
+	}
+
+	tasks := make(map[string]bool, len(req.GetTaskIds()))
+	for _, id := range req.GetTaskIds() {
+		tasks[id] = true
+	}
+	projects := make(map[string]bool, len(req.GetProjectIds()))
+	for _, id := range req.GetProjectIds() {
+		projects[id] = true
+	}
+	all := len(tasks) == 0 && len(projects) == 0
+
+	// Both maps are only read once the subscription is made
+	sub := config.Events.Subscribe(func(event models.Event) bool {
+		if event.UserID != userID {
+			return false
+		}
+		return all || tasks[event.TaskID] || projects[event.ProjectID] ||
+			(event.PreviousProjectID != "" && projects[event.PreviousProjectID])
+	}, req.GetLastEventId())
+	defer sub.Close()
+
+	if sub.Lost {
+		err := stream.Send(&taskv1.TaskEvent{Type: eventStreamReset})
+		if err != nil {
+			return err
+		}
+	}
+	for _, event := range sub.Missed {
+		err := stream.Send(eventToProto(event))
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "the stream lagged behind, resume it with last_event_id")
+			}
+			err := stream.Send(eventToProto(event))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures a connection to an S3-compatible object store, such
+// as AWS S3 or a local MinIO server.
+type S3Config struct {
+	Endpoint        string // e.g., "s3.amazonaws.com" or "localhost:9000"
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+}
+
+// S3 stores blobs as objects of an S3 bucket.
+type S3 struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 connects to the object store and creates the bucket if it does not
+// exist yet.
+func NewS3(ctx context.Context, cfg S3Config) (*S3, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %v", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check S3 bucket: %v", err)
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 bucket: %v", err)
+		}
+	}
+
+	return &S3{client: client, bucket: cfg.Bucket}, nil
+}
+
+// s3PartSize is the size of the parts of uploads of unknown size, the
+// smallest S3 allows. Without it the client sizes its part buffer for the
+// largest object possible, hundreds of MiB on every upload.
+const s3PartSize = 5 << 20
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	opts := minio.PutObjectOptions{ContentType: contentType}
+	if size < 0 {
+		opts.PartSize = s3PartSize
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, opts)
+	return err
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy, stat the object to report missing blobs right away
+	_, err = object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a local stand-in for an S3 server, with the subset of the API
+// the S3 store uses: buckets, objects and multipart uploads. Signatures are
+// not checked.
+type fakeS3 struct {
+	mu       sync.Mutex
+	buckets  map[string]bool
+	objects  map[string][]byte         // By bucket/key
+	uploads  map[string]map[int][]byte // Parts by upload ID
+	partSize []int                     // Sizes of the parts uploaded, in order
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{buckets: map[string]bool{}, objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+			if !f.buckets[bucket] {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			f.buckets[bucket] = true
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+		return
+	}
+	if !f.buckets[bucket] {
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	name := bucket + "/" + key
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = map[int][]byte{}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadID string `xml:"UploadId"`
+		}{Bucket: bucket, Key: key, UploadID: id})
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		body, err := readBody(r)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[number] = body
+		f.partSize = append(f.partSize, len(body))
+		w.Header().Set("ETag", etag(body))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		numbers := make([]int, 0, len(parts))
+		for number := range parts {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+		var object []byte
+		for _, number := range numbers {
+			object = append(object, parts[number]...)
+		}
+		f.objects[name] = object
+		delete(f.uploads, query.Get("uploadId"))
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: etag(object)})
+
+	case r.Method == http.MethodPut:
+		body, err := readBody(r)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[name] = body
+		w.Header().Set("ETag", etag(body))
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		object, ok := f.objects[name]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", etag(object))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(object)))
+		if r.Method == http.MethodGet {
+			w.Write(object)
+		}
+
+	case r.Method == http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// readBody reads the body of an upload, decoding the aws-chunked encoding of
+// streaming signatures.
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var body []byte
+	br := bufio.NewReader(r.Body)
+	for {
+		header, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return body, nil
+		}
+		chunk := make([]byte, size+2) // Followed by CRLF
+		_, err = io.ReadFull(br, chunk)
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, chunk[:size]...)
+	}
+}
+
+func etag(content []byte) string {
+	sum := md5.Sum(content)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(v)
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// newTestS3 returns an S3 store on a new fakeS3 server.
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3(context.Background(), S3Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Region:          "us-east-1",
+		Bucket:          "attachments",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio123",
+	})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+	return store, fake
+}
+
+func TestS3(t *testing.T) {
+	store, fake := newTestS3(t)
+	if !fake.buckets["attachments"] {
+		t.Fatal("NewS3 did not create the bucket")
+	}
+	testStore(t, store)
+}
+
+func TestS3UnknownSizeUsesSmallParts(t *testing.T) {
+	store, fake := newTestS3(t)
+
+	// A little over one part, streamed without its size like attachments
+	content := bytes.Repeat([]byte("0123456789abcdef"), (s3PartSize+1<<20)/16)
+	err := store.Put(context.Background(), "task-1/big", io.MultiReader(bytes.NewReader(content)), -1, "application/octet-stream")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	assertBlob(t, store, "task-1/big", content)
+
+	want := []int{s3PartSize, len(content) - s3PartSize}
+	if fmt.Sprint(fake.partSize) != fmt.Sprint(want) {
+		t.Fatalf("uploaded parts of %v bytes, want %v", fake.partSize, want)
+	}
+}
@@ -0,0 +1,26 @@
+// Package storage keeps the content of file attachments. Attachments are
+// stored as opaque blobs under a key; their metadata lives in the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// Store is a blob storage backend.
+type Store interface {
+	// Put stores the content of r under key. size is the length of the
+	// content, or -1 if unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the blob stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
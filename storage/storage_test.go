@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testStore runs the behaviour every Store must have against store.
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	content := []byte("hello, attachment")
+	err := store.Put(ctx, "task-1/a", bytes.NewReader(content), -1, "text/plain")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	assertBlob(t, store, "task-1/a", content)
+
+	// Putting again replaces the blob
+	content = []byte("replaced")
+	err = store.Put(ctx, "task-1/a", bytes.NewReader(content), int64(len(content)), "text/plain")
+	if err != nil {
+		t.Fatalf("Put again: %v", err)
+	}
+	assertBlob(t, store, "task-1/a", content)
+
+	_, err = store.Get(ctx, "task-1/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get missing blob: got %v, want ErrNotFound", err)
+	}
+
+	err = store.Delete(ctx, "task-1/a")
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = store.Get(ctx, "task-1/a")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get deleted blob: got %v, want ErrNotFound", err)
+	}
+	err = store.Delete(ctx, "task-1/a")
+	if err != nil {
+		t.Fatalf("Delete missing blob: %v", err)
+	}
+}
+
+// assertBlob checks that the blob stored under key holds want.
+func assertBlob(t *testing.T, store Store, key string, want []byte) {
+	t.Helper()
+	r, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get %s: %v", key, err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("blob %s holds %d bytes %.20q, want %d bytes %.20q", key, len(got), got, len(want), want)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../outside", "/etc/passwd", "a/../../outside"} {
+		err := store.Put(context.Background(), key, bytes.NewReader(nil), 0, "")
+		if err == nil {
+			t.Errorf("Put %q: want an error", key)
+		}
+	}
+}